@@ -50,6 +50,15 @@ func GetApp() *cli.Command {
 		commands.ConfigCmd(),
 		commands.MigrateCmd(),
 		commands.SupportCmd(),
+		commands.LogCmd(),
+		commands.VerifyPackageCmd(),
+		commands.LintPackageCmd(),
+		commands.CacheCmd(),
+		commands.ChecksumsCmd(),
+		commands.LintCmd(),
+		commands.FmtCmd(),
+		commands.NewCmd(),
+		commands.OutdatedCmd(),
 		// Internal commands
 		commands.InternalPluginProvider(),
 		commands.InternalPluginProviderRoot(),
@@ -65,6 +65,52 @@ func BuildCmd() *cli.Command {
 				Name:  "no-suffix",
 				Usage: gotext.Get("Do not add suffix to package name"),
 			},
+			&cli.IntFlag{
+				Name:    "jobs",
+				Aliases: []string{"j"},
+				Value:   1,
+				Usage:   gotext.Get("Number of independent packages to build in parallel"),
+			},
+			&cli.BoolFlag{
+				Name:  "nocheck",
+				Usage: gotext.Get("Do not run the check() function of the build script"),
+			},
+			&cli.BoolFlag{
+				Name:  "reproducible",
+				Usage: gotext.Get("Make the package output depend only on the sources"),
+			},
+			&cli.BoolFlag{
+				Name:  "verify-reproducible",
+				Usage: gotext.Get("Build the package twice and check that the results are identical"),
+			},
+			&cli.BoolFlag{
+				Name:  "sbom",
+				Usage: gotext.Get("Write an SPDX SBOM and a build provenance statement next to the package"),
+			},
+			&cli.BoolFlag{
+				Name:  "clean-root",
+				Usage: gotext.Get("Build in a rootfs from the base image with only the declared build dependencies"),
+			},
+			&cli.StringFlag{
+				Name:  "base-image",
+				Usage: gotext.Get("Base image tarball or OCI layout for --clean-root"),
+			},
+			&cli.StringFlag{
+				Name:  "target-distro",
+				Usage: gotext.Get("Build for another distro, given as an os-release file or id:version"),
+			},
+			&cli.StringFlag{
+				Name:  "target-format",
+				Usage: gotext.Get("Build another package format (deb, rpm, apk or archlinux)"),
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: gotext.Get("Print what would be done without changing anything"),
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: gotext.Get("Print the dry-run plan in JSON format"),
+			},
 		},
 		Action: func(ctx context.Context, c *cli.Command) error {
 			d, f, err := deps.ForBuildAction(ctx)
@@ -87,6 +133,18 @@ func BuildCmd() *cli.Command {
 				Clean:       c.Bool("clean"),
 				Interactive: c.Bool("interactive"),
 				NoSuffix:    c.Bool("no-suffix"),
+				Jobs:        c.Int("jobs"),
+				NoCheck:     c.Bool("nocheck"),
+				DryRun:      c.Bool("dry-run"),
+				Json:        c.Bool("json"),
+
+				Reproducible:       c.Bool("reproducible"),
+				VerifyReproducible: c.Bool("verify-reproducible"),
+				SBOM:               c.Bool("sbom"),
+				CleanRoot:          c.Bool("clean-root"),
+				BaseImage:          c.String("base-image"),
+				TargetDistro:       c.String("target-distro"),
+				TargetFormat:       c.String("target-format"),
 			})
 		},
 	}
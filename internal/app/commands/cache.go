@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v3"
+
+	"go.stplr.dev/stplr/internal/app/deps"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/cachegc"
+	"go.stplr.dev/stplr/internal/cliutils"
+	"go.stplr.dev/stplr/internal/cliutils2"
+	"go.stplr.dev/stplr/internal/usecase/cache"
+)
+
+func CacheCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: gotext.Get("Inspect and prune the caches"),
+		Commands: []*cli.Command{
+			CacheListCmd(),
+			CacheDuCmd(),
+			CachePruneCmd(),
+		},
+	}
+}
+
+func CacheListCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: gotext.Get("List the built packages, sources, repo clones and compiler caches"),
+		Action: cliutils2.ReadonlyAction(func(ctx context.Context, c *cli.Command) error {
+			d, f, err := deps.ForCacheAction(ctx)
+			if err != nil {
+				return err
+			}
+			defer f()
+
+			return cache.New(cachegc.New(d.Config), output.FromContext(ctx)).List(ctx)
+		}),
+	}
+}
+
+func CacheDuCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "du",
+		Usage: gotext.Get("Show the disk usage of the caches"),
+		Action: cliutils2.ReadonlyAction(func(ctx context.Context, c *cli.Command) error {
+			d, f, err := deps.ForCacheAction(ctx)
+			if err != nil {
+				return err
+			}
+			defer f()
+
+			return cache.New(cachegc.New(d.Config), output.FromContext(ctx)).Du(ctx)
+		}),
+	}
+}
+
+func CachePruneCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "prune",
+		Usage: gotext.Get("Remove old entries from the caches"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "older-than",
+				Usage: gotext.Get("Remove entries not modified for longer than this, e.g. 30d, 2w or 12h"),
+			},
+			&cli.IntFlag{
+				Name:  "keep-last",
+				Usage: gotext.Get("Keep only this many versions of every package"),
+			},
+			&cli.StringFlag{
+				Name:  "max-size",
+				Usage: gotext.Get("Remove the oldest entries until the caches fit into this size, e.g. 10G"),
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: gotext.Get("Print what would be removed without removing anything"),
+			},
+		},
+		Action: cliutils2.RootNeededAction(cliutils2.ActionWithLocks(
+			[]string{"repo-cache"},
+			func(ctx context.Context, c *cli.Command) error {
+				if err := cliutils.ExitIfCantDropCapsToBuilderUserNoPrivs(); err != nil {
+					return err
+				}
+
+				d, f, err := deps.ForCacheAction(ctx)
+				if err != nil {
+					return err
+				}
+				defer f()
+
+				return cache.New(cachegc.New(d.Config), output.FromContext(ctx)).Prune(ctx, cache.PruneOptions{
+					OlderThan: c.String("older-than"),
+					KeepLast:  c.Int("keep-last"),
+					MaxSize:   c.String("max-size"),
+					DryRun:    c.Bool("dry-run"),
+				})
+			},
+		)),
+	}
+}
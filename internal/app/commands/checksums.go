@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v3"
+
+	"go.stplr.dev/stplr/internal/app/deps"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/usecase/checksums"
+)
+
+func ChecksumsCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "checksums",
+		Usage: gotext.Get("Compute the checksums of the sources of a build script"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "script",
+				Aliases: []string{"s"},
+				Value:   "Staplerfile",
+				Usage:   gotext.Get("Path to the build script"),
+			},
+			&cli.BoolFlag{
+				Name:    "update",
+				Aliases: []string{"u"},
+				Usage:   gotext.Get("Write the checksums into the build script instead of printing them"),
+			},
+			&cli.StringFlag{
+				Name:  "algo",
+				Value: "sha256",
+				Usage: gotext.Get("Hash algorithm, such as sha256, sha512 or blake2b-256"),
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			d, f, err := deps.ForChecksumsAction(ctx)
+			if err != nil {
+				return err
+			}
+			defer f()
+
+			return checksums.New(d.Info, output.FromContext(ctx)).Run(ctx, checksums.Options{
+				Script:    c.String("script"),
+				Update:    c.Bool("update"),
+				Algorithm: c.String("algo"),
+			})
+		},
+	}
+}
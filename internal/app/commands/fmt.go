@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v3"
+
+	"go.stplr.dev/stplr/internal/usecase/format"
+)
+
+func FmtCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "fmt",
+		Usage:     gotext.Get("Format build scripts in the canonical style"),
+		ArgsUsage: gotext.Get("[script]..."),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "write",
+				Aliases: []string{"w"},
+				Usage:   gotext.Get("Write the formatted scripts back instead of printing them"),
+			},
+			&cli.BoolFlag{
+				Name:    "diff",
+				Aliases: []string{"d"},
+				Usage:   gotext.Get("Print the diffs of the scripts that are not formatted"),
+			},
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: gotext.Get("List the scripts that are not formatted and fail if there are any"),
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			scripts := c.Args().Slice()
+			if len(scripts) == 0 {
+				scripts = []string{"Staplerfile"}
+			}
+
+			return format.New().Run(ctx, format.Options{
+				Scripts: scripts,
+				Write:   c.Bool("write"),
+				Diff:    c.Bool("diff"),
+				Check:   c.Bool("check"),
+			})
+		},
+	}
+}
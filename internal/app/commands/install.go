@@ -57,6 +57,24 @@ func InstallCmd() *cli.Command {
 				Aliases: []string{"c"},
 				Usage:   gotext.Get("Build package from scratch even if there's an already built package available"),
 			},
+			&cli.IntFlag{
+				Name:    "jobs",
+				Aliases: []string{"j"},
+				Value:   1,
+				Usage:   gotext.Get("Number of independent packages to build in parallel"),
+			},
+			&cli.BoolFlag{
+				Name:  "nocheck",
+				Usage: gotext.Get("Do not run the check() function of the build script"),
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: gotext.Get("Print what would be done without changing anything"),
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: gotext.Get("Print the dry-run plan in JSON format"),
+			},
 		},
 		ShellComplete: cliutils.BashCompleteWithError(func(ctx context.Context, c *cli.Command) error {
 			d, f, err := deps.ForInstallShellComp(ctx)
@@ -84,6 +102,10 @@ func InstallCmd() *cli.Command {
 					Pkgs:        c.Args().Slice(),
 					Clean:       c.Bool("clean"),
 					Interactive: c.Bool("interactive"),
+					Jobs:        c.Int("jobs"),
+					NoCheck:     c.Bool("nocheck"),
+					DryRun:      c.Bool("dry-run"),
+					Json:        c.Bool("json"),
 				})
 			})),
 	}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v3"
+
+	"go.stplr.dev/stplr/internal/app/deps"
+	"go.stplr.dev/stplr/internal/cliutils2"
+	"go.stplr.dev/stplr/internal/usecase/lint"
+)
+
+func LintCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "lint",
+		Usage:     gotext.Get("Check build scripts for mistakes without building them"),
+		ArgsUsage: gotext.Get("[script]..."),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: gotext.Get("Print the diagnostics as JSON"),
+			},
+		},
+		Action: cliutils2.ReadonlyAction(func(ctx context.Context, c *cli.Command) error {
+			scripts := c.Args().Slice()
+			if len(scripts) == 0 {
+				scripts = []string{"Staplerfile"}
+			}
+
+			d, f, err := deps.ForLintAction(ctx)
+			if err != nil {
+				return err
+			}
+			defer f()
+
+			return lint.New(d.Info).Run(ctx, lint.Options{
+				Scripts: scripts,
+				JSON:    c.Bool("json"),
+			})
+		}),
+	}
+}
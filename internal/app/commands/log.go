@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v3"
+
+	"go.stplr.dev/stplr/internal/app/deps"
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/cliutils2"
+	"go.stplr.dev/stplr/internal/usecase/log"
+)
+
+func LogCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "log",
+		Usage:     gotext.Get("Show build logs of a package"),
+		ArgsUsage: gotext.Get("<package>"),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "last",
+				Usage: gotext.Get("Show the log of the last build"),
+			},
+			&cli.BoolFlag{
+				Name:  "failed",
+				Usage: gotext.Get("Show the log of the last failed build"),
+			},
+		},
+		Action: cliutils2.ReadonlyAction(func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() != 1 {
+				return errors.NewI18nError(gotext.Get("Command log expected 1 argument, got %d", c.Args().Len()))
+			}
+
+			d, f, err := deps.ForLogAction(ctx)
+			if err != nil {
+				return err
+			}
+			defer f()
+
+			return log.New(d.Logs).Run(ctx, log.Options{
+				Pkg:    c.Args().First(),
+				Last:   c.Bool("last"),
+				Failed: c.Bool("failed"),
+			})
+		}),
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package commands
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/urfave/cli/v3"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/usecase/newpkg"
+)
+
+func NewCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "new",
+		Usage:     gotext.Get("Create a Staplerfile for a source tree or archive"),
+		ArgsUsage: gotext.Get("<name>"),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "from",
+				Usage: gotext.Get("Local archive or directory, or URL of the source, such as a git repository"),
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   gotext.Get("Directory of the Staplerfile, the name of the package by default"),
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() != 1 {
+				return errors.NewI18nError(gotext.Get("Command new expected 1 argument, got %d", c.Args().Len()))
+			}
+			if c.String("from") == "" {
+				return errors.NewI18nError(gotext.Get("The --from flag is required"))
+			}
+
+			return newpkg.New(output.FromContext(ctx)).Run(ctx, newpkg.Options{
+				Name:   c.Args().First(),
+				From:   c.String("from"),
+				Output: c.String("output"),
+			})
+		},
+	}
+}
@@ -47,6 +47,24 @@ func UpgradeCmd() *cli.Command {
 				Aliases: []string{"c"},
 				Usage:   gotext.Get("Build package from scratch even if there's an already built package available"),
 			},
+			&cli.IntFlag{
+				Name:    "jobs",
+				Aliases: []string{"j"},
+				Value:   1,
+				Usage:   gotext.Get("Number of independent packages to build in parallel"),
+			},
+			&cli.BoolFlag{
+				Name:  "nocheck",
+				Usage: gotext.Get("Do not run the check() function of the build script"),
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: gotext.Get("Print what would be done without changing anything"),
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: gotext.Get("Print the dry-run plan in JSON format"),
+			},
 		},
 		Action: cliutils2.RootNeededAction(cliutils2.ActionWithLocks(
 			[]string{"repo-cache", "install-pkgs"},
@@ -60,6 +78,10 @@ func UpgradeCmd() *cli.Command {
 				return upgrade.New(d.Builder, d.Updater, d.Manager, d.DB, d.Repos, d.Info, output.FromContext(ctx)).Run(ctx, upgrade.Options{
 					Clean:       c.Bool("clean"),
 					Interactive: c.Bool("interactive"),
+					Jobs:        c.Int("jobs"),
+					NoCheck:     c.Bool("nocheck"),
+					DryRun:      c.Bool("dry-run"),
+					Json:        c.Bool("json"),
 				})
 			})),
 	}
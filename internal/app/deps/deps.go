@@ -20,11 +20,15 @@ package deps
 
 import (
 	"context"
+	"os"
 	"path/filepath"
 
+	"github.com/spf13/afero"
+
 	"go.stplr.dev/stplr/internal/app/deps/internal/builder"
 	"go.stplr.dev/stplr/internal/app/output"
 	"go.stplr.dev/stplr/internal/build"
+	"go.stplr.dev/stplr/internal/buildlog"
 	"go.stplr.dev/stplr/internal/config"
 	"go.stplr.dev/stplr/internal/config/savers"
 	"go.stplr.dev/stplr/internal/copier"
@@ -628,3 +632,141 @@ func ForSupportAction(ctx context.Context) (*SupportActionDeps, Cleanup, error)
 		Out: b.Output,
 	}, b.Cleanup, nil
 }
+
+type LogActionDeps struct {
+	Logs []*buildlog.Store
+}
+
+func ForLogAction(ctx context.Context) (*LogActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Config().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fs := afero.NewOsFs()
+	systemDir := buildlog.Dir(b.Cfg.GetPaths().CacheDir)
+	logs := []*buildlog.Store{buildlog.NewStore(fs, systemDir)}
+
+	// Builds run by a regular user keep their logs in the user cache dir
+	if userCacheDir, err := os.UserCacheDir(); err == nil {
+		userDir := buildlog.Dir(filepath.Join(userCacheDir, "stplr"))
+		if userDir != systemDir {
+			logs = append(logs, buildlog.NewStore(fs, userDir))
+		}
+	}
+
+	return &LogActionDeps{
+		Logs: logs,
+	}, b.Cleanup, nil
+}
+
+type VerifyPackageActionDeps struct {
+	Config *config.ALRConfig
+}
+
+func ForVerifyPackageAction(ctx context.Context) (*VerifyPackageActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Config().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &VerifyPackageActionDeps{
+		Config: b.Cfg,
+	}, b.Cleanup, nil
+}
+
+type CacheActionDeps struct {
+	Config *config.ALRConfig
+}
+
+func ForCacheAction(ctx context.Context) (*CacheActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Config().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &CacheActionDeps{
+		Config: b.Cfg,
+	}, b.Cleanup, nil
+}
+
+type LintPackageActionDeps struct {
+	Config *config.ALRConfig
+}
+
+func ForLintPackageAction(ctx context.Context) (*LintPackageActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Config().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &LintPackageActionDeps{
+		Config: b.Cfg,
+	}, b.Cleanup, nil
+}
+
+type ChecksumsActionDeps struct {
+	Info *distro.OSRelease
+}
+
+func ForChecksumsAction(ctx context.Context) (*ChecksumsActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Info().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &ChecksumsActionDeps{
+		Info: b.Info,
+	}, b.Cleanup, nil
+}
+
+type LintActionDeps struct {
+	Info *distro.OSRelease
+}
+
+func ForLintAction(ctx context.Context) (*LintActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Info().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &LintActionDeps{
+		Info: b.Info,
+	}, b.Cleanup, nil
+}
+
+type OutdatedActionDeps struct {
+	Config *config.ALRConfig
+}
+
+func ForOutdatedAction(ctx context.Context) (*OutdatedActionDeps, Cleanup, error) {
+	b, err := builder.
+		Start(ctx).
+		Config().
+		End()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &OutdatedActionDeps{
+		Config: b.Cfg,
+	}, b.Cleanup, nil
+}
@@ -29,12 +29,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 
 	"github.com/gobwas/glob"
 	"github.com/leonelquinteros/gotext"
 
 	"go.stplr.dev/stplr/internal/app/output"
-	"go.stplr.dev/stplr/internal/cliprompts"
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/manager"
 	"go.stplr.dev/stplr/internal/scripter"
@@ -45,8 +45,9 @@ import (
 
 // ================================================
 
+// GetBuiltPaths returns the paths of the packages of deps to install.
 func GetBuiltPaths(deps []*commonbuild.BuiltDep) []string {
-	return scripter.Map(deps, func(dep *commonbuild.BuiltDep) string {
+	return scripter.Map(scripter.WithoutDebugInfo(deps), func(dep *commonbuild.BuiltDep) string {
 		return dep.Path
 	})
 }
@@ -57,8 +58,9 @@ type PackageFinder interface {
 }
 
 type SourcesInput struct {
-	Sources   []string
-	Checksums []string
+	Sources      []string
+	Checksums    []string
+	ValidPGPKeys []string
 }
 
 type BuildArgs struct {
@@ -89,6 +91,11 @@ type BuildPackageFromScriptArgs struct {
 	BuildArgs
 	Script   string
 	Packages []string
+	// SourceDateEpoch, SourceURL and SourceCommit override the ones
+	// derived from Script, which may be a temporary copy.
+	SourceDateEpoch int64
+	SourceURL       string
+	SourceCommit    string
 }
 
 func (b *Builder) BuildPackageFromDb(
@@ -115,14 +122,27 @@ func (b *Builder) BuildPackageFromDb(
 		args.Opts.DisableFirejail = true
 	}
 
+	sourceURL, sourceCommit := ScriptSource(scriptInfo.Script)
+	var lintLevels map[string]string
+	if repo, err := b.repos.GetRepo(scriptInfo.Repository); err == nil {
+		if repo.URL != "" {
+			sourceURL = repo.URL
+		}
+		lintLevels = repo.Lint
+	}
+
 	return b.BuildPackage(ctx, &commonbuild.BuildInput{
-		BasePkgName: name,
-		Script:      scriptInfo.Script,
-		Repository_: scriptInfo.Repository,
-		Packages_:   args.Packages,
-		PkgFormat_:  args.PkgFormat(),
-		Opts:        args.Opts,
-		Info_:       args.Info,
+		BasePkgName:     name,
+		Script:          scriptInfo.Script,
+		Repository_:     scriptInfo.Repository,
+		Packages_:       args.Packages,
+		PkgFormat_:      args.PkgFormat(),
+		Opts:            args.Opts,
+		Info_:           args.Info,
+		SourceDateEpoch: SourceDateEpoch(scriptInfo.Script),
+		SourceURL:       sourceURL,
+		SourceCommit:    sourceCommit,
+		LintLevels:      lintLevels,
 	})
 }
 
@@ -130,13 +150,26 @@ func (b *Builder) BuildPackageFromScript(
 	ctx context.Context,
 	args *BuildPackageFromScriptArgs,
 ) ([]*commonbuild.BuiltDep, error) {
+	epoch := args.SourceDateEpoch
+	if epoch == 0 {
+		epoch = SourceDateEpoch(args.Script)
+	}
+
+	sourceURL, sourceCommit := args.SourceURL, args.SourceCommit
+	if sourceCommit == "" {
+		sourceURL, sourceCommit = ScriptSource(args.Script)
+	}
+
 	return b.BuildPackage(ctx, &commonbuild.BuildInput{
-		Script:      args.Script,
-		Repository_: "default",
-		Packages_:   args.Packages,
-		PkgFormat_:  args.PkgFormat(),
-		Opts:        args.Opts,
-		Info_:       args.Info,
+		Script:          args.Script,
+		Repository_:     "default",
+		Packages_:       args.Packages,
+		PkgFormat_:      args.PkgFormat(),
+		Opts:            args.Opts,
+		Info_:           args.Info,
+		SourceDateEpoch: epoch,
+		SourceURL:       sourceURL,
+		SourceCommit:    sourceCommit,
 	})
 }
 
@@ -168,6 +201,11 @@ func (b *Builder) InstallALRPackages(
 			return err
 		}
 
+		if plan := b.planFor(input); plan != nil {
+			plan.addInstallLocal(scripter.GetBuiltName(res))
+			continue
+		}
+
 		err = b.installerExecutor.InstallLocal(
 			ctx,
 			GetBuiltPaths(res),
@@ -193,6 +231,12 @@ func (i *Builder) InstallPkgs(
 		return nil, err
 	}
 
+	if plan := i.planFor(input); plan != nil {
+		plan.addInstallLocal(scripter.GetBuiltName(builtDeps))
+		plan.addInstall(repoDeps)
+		return builtDeps, nil
+	}
+
 	if len(builtDeps) > 0 {
 		err = i.installerExecutor.InstallLocal(ctx, GetBuiltPaths(builtDeps), &manager.Opts{
 			NoConfirm: !input.BuildOpts().Interactive,
@@ -218,50 +262,31 @@ func (i *Builder) InstallPkgs(
 }
 
 func (b *Builder) BuildALRDeps(ctx context.Context, input InstallInput, depends []string) (buildDeps []*commonbuild.BuiltDep, repoDeps []string, err error) {
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed init resolver: %w", err)
-	}
-
 	if len(depends) > 0 {
 		b.out.Info(gotext.Get("Installing dependencies"))
 
-		found, notFound, err := b.repos.FindPkgs(ctx, depends)
+		b.beginRun()
+		defer func() {
+			if endErr := b.endRun(ctx, input, buildDeps, err); endErr != nil && err == nil {
+				err = endErr
+			}
+		}()
+
+		g, err := b.resolveDepGraph(ctx, input, depends)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed FindPkgs: %w", err)
+			return nil, nil, fmt.Errorf("failed to resolve dependencies: %w", err)
 		}
-		repoDeps = notFound
+		repoDeps = g.repoDeps
 
-		pkgs, err := cliprompts.FlattenPkgs(
-			ctx,
-			found,
-			"install",
-			input.BuildOpts().Interactive,
-		)
+		results, err := b.scheduleDeps(ctx, input, g, func(ctx context.Context, node *depNode) ([]*commonbuild.BuiltDep, error) {
+			return b.buildDepNode(ctx, input, node)
+		})
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to flatten packages: %w", err)
+			return nil, nil, err
 		}
-		pkgsMap := groupPackages(pkgs)
-
-		for basePkgName := range pkgsMap {
-			pkg := pkgsMap[basePkgName].pkg
-
-			res, err := b.BuildPackageFromDb(
-				ctx,
-				&BuildPackageFromDbArgs{
-					Package:  pkg,
-					Packages: pkgsMap[basePkgName].packages,
-					BuildArgs: BuildArgs{
-						Opts:       input.BuildOpts(),
-						Info:       input.OSRelease(),
-						PkgFormat_: input.PkgFormat(),
-					},
-				},
-			)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed build package from db: %w", err)
-			}
 
-			buildDeps = append(buildDeps, res...)
+		for _, root := range g.roots {
+			buildDeps = append(buildDeps, results[root]...)
 		}
 	}
 
@@ -271,6 +296,54 @@ func (b *Builder) BuildALRDeps(ctx context.Context, input InstallInput, depends
 	return buildDeps, repoDeps, nil
 }
 
+// buildDepNode builds the packages of node unless they were already built
+// earlier in this run.
+func (b *Builder) buildDepNode(ctx context.Context, input InstallInput, node *depNode) ([]*commonbuild.BuiltDep, error) {
+	// cross-target builds make the same package for the host and the target
+	key := input.PkgFormat() + ":" + node.key
+
+	b.mu.Lock()
+	res, ok := b.built[key]
+	b.mu.Unlock()
+	if ok && builtAll(res, node.packages) {
+		return res, nil
+	}
+
+	pkg := *node.pkg
+	res, err := b.BuildPackageFromDb(
+		ctx,
+		&BuildPackageFromDbArgs{
+			Package:  &pkg,
+			Packages: node.packages,
+			BuildArgs: BuildArgs{
+				Opts:       input.BuildOpts(),
+				Info:       input.OSRelease(),
+				PkgFormat_: input.PkgFormat(),
+			},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed build package from db: %w", err)
+	}
+
+	b.mu.Lock()
+	b.built[key] = res
+	b.mu.Unlock()
+
+	return res, nil
+}
+
+func builtAll(deps []*commonbuild.BuiltDep, names []string) bool {
+	for _, name := range names {
+		if !slices.ContainsFunc(deps, func(dep *commonbuild.BuiltDep) bool {
+			return dep.Name == name
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
 func firejailedPatternMatch(fullName, pattern string) (bool, error) {
 	g, err := glob.Compile(pattern)
 	if err != nil {
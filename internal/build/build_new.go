@@ -23,12 +23,16 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 	"text/template"
+	"time"
 
 	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/buildlog"
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/installer"
 	"go.stplr.dev/stplr/internal/scripter"
+	"go.stplr.dev/stplr/pkg/staplerfile"
 )
 
 type Builder struct {
@@ -44,6 +48,29 @@ type Builder struct {
 	nonfreeViewer        NonFreeViewerExecutor
 	checksExecutor       ChecksExecutor
 	out                  output.Output
+
+	mu sync.Mutex
+	// chosen remembers which packages were picked for a dependency name.
+	chosen map[string][]staplerfile.Package
+	// built holds results of dependency builds done during this run.
+	built map[string][]*commonbuild.BuiltDep
+
+	promptMu sync.Mutex
+
+	// running counts the builds and dependency graphs in progress.
+	running int
+	// buildDeps holds the build dependencies to remove once no build
+	// is running anymore.
+	buildDeps []string
+	// baseDirs holds the base directories of the packages built
+	// since no build was running.
+	baseDirs []string
+	// slots limit the builds of dependencies of the run.
+	slots jobSlots
+
+	plan        *Plan
+	logs        *buildlog.Store
+	cachePruner CachePruner
 }
 
 func NewBuilder(
@@ -63,13 +90,16 @@ func NewBuilder(
 		scriptResolver:       scriptResolver,
 		scriptExecutor:       scriptExecutor,
 		cacheExecutor:        cacheExecutor,
-		installerExecutor:    installerExecutor,
+		installerExecutor:    newSerialInstaller(installerExecutor),
 		sourceExecutor:       sourceExecutor,
 		nonfreeViewer:        nonfreeViewer,
 		checksExecutor:       checksExecutor,
 		repos:                repos,
 		scriptViewerExecutor: scriptViewerExecutor,
 		out:                  output.NewConsoleOutput(),
+		chosen:               make(map[string][]staplerfile.Package),
+		built:                make(map[string][]*commonbuild.BuiltDep),
+		plan:                 NewPlan(),
 	}
 }
 
@@ -80,7 +110,17 @@ type BuildStep interface {
 
 func runSteps(ctx context.Context, state *BuildState, steps []BuildStep) ([]*commonbuild.BuiltDep, error) {
 	for _, step := range steps {
-		if err := step.Run(ctx, state); err != nil {
+		run := step.Run
+		if dr, ok := step.(dryRunner); ok && state.Plan != nil {
+			run = dr.DryRun
+		}
+		start := time.Now()
+		err := run(ctx, state)
+		state.Timings = append(state.Timings, buildlog.StepTiming{
+			Name:     step.Name(),
+			Duration: time.Since(start),
+		})
+		if err != nil {
 			return nil, fmt.Errorf("step %q failed: %w", step.Name(), err)
 		}
 
@@ -112,6 +152,7 @@ func (b *Builder) BuildPackage(ctx context.Context, input *commonbuild.BuildInpu
 	state.Input = input
 	state.Repository = input.Repository()
 	state.BasePackage = input.BasePkgName
+	state.Plan = b.planFor(input)
 
 	steps := []BuildStep{
 		ReadScriptStep(
@@ -122,12 +163,12 @@ func (b *Builder) BuildPackage(ctx context.Context, input *commonbuild.BuildInpu
 		CheckCacheStep(
 			b.cacheExecutor,
 		),
-		ScriptViewStep(
+		&serialStep{&b.promptMu, ScriptViewStep(
 			b.scriptViewerExecutor,
-		),
-		NonfreeViewStep(
+		)},
+		&serialStep{&b.promptMu, NonfreeViewStep(
 			b.nonfreeViewer,
-		),
+		)},
 		// Check arch and check is package already installed
 		ChecksStep(
 			b.checksExecutor,
@@ -141,6 +182,9 @@ func (b *Builder) BuildPackage(ctx context.Context, input *commonbuild.BuildInpu
 			b.repos,
 			b,
 		),
+		OpenBuildLogStep(
+			b.logs,
+		),
 		//
 		PrepareStep(
 			b.scriptExecutor,
@@ -148,14 +192,20 @@ func (b *Builder) BuildPackage(ctx context.Context, input *commonbuild.BuildInpu
 		),
 		BuildPackagesStep(
 			b.scriptExecutor,
+			b.cfg,
 		),
-		PostStep(
-			b.installerExecutor,
+		&serialStep{&b.promptMu, PostStep(
 			b.sourceExecutor,
-		),
+			b,
+		)},
 	}
 
+	b.beginRun()
 	res, stepsErr := runSteps(ctx, state, steps)
+	if err := b.endRun(ctx, input, res, stepsErr); err != nil && stepsErr == nil {
+		stepsErr = err
+	}
+	b.finishBuildLog(state, stepsErr)
 	if stepsErr != nil {
 		if input.BasePkgName != "" {
 			repo, err := b.repos.GetRepo(input.Repository())
@@ -196,3 +246,13 @@ func (b *Builder) BuildPackage(ctx context.Context, input *commonbuild.BuildInpu
 	}
 	return res, nil
 }
+
+func (b *Builder) finishBuildLog(state *BuildState, buildErr error) {
+	if state.Log == nil {
+		return
+	}
+	state.Log.Steps = state.Timings
+	if err := b.logs.Finish(state.Log, time.Now(), buildErr); err != nil {
+		slog.Warn("failed to save build log", "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"time"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/cachegc"
+	"go.stplr.dev/stplr/internal/config/common"
+	"go.stplr.dev/stplr/internal/units"
+)
+
+type cachePrunerConfig interface {
+	cachegc.Config
+	CacheMaxSize() string
+	CacheMaxAge() string
+}
+
+// LocalCachePruner applies the cache size and age limits
+// of the configuration after builds.
+type LocalCachePruner struct {
+	cfg cachePrunerConfig
+	out output.Output
+}
+
+func NewLocalCachePruner(cfg cachePrunerConfig, out output.Output) *LocalCachePruner {
+	return &LocalCachePruner{cfg: cfg, out: out}
+}
+
+// Prune prunes the caches, keeping the packages at keep. Failures
+// are only reported, as the build itself has succeeded.
+func (p *LocalCachePruner) Prune(ctx context.Context, keep []string) {
+	policy, err := cachegc.ConfigPolicy(p.cfg.CacheMaxSize(), p.cfg.CacheMaxAge())
+	if err != nil {
+		p.out.Warn(gotext.Get("Invalid %s or %s: %s", common.CACHE_MAX_SIZE, common.CACHE_MAX_AGE, err))
+		return
+	}
+	if policy.IsZero() {
+		return
+	}
+
+	c := cachegc.New(p.cfg)
+	entries, err := c.List(ctx)
+	if err != nil {
+		p.out.Warn(gotext.Get("Failed to prune the caches: %s", err))
+		return
+	}
+
+	pruned := cachegc.Select(entries, policy, time.Now(), keep)
+	if len(pruned) == 0 {
+		return
+	}
+	if err := c.Remove(ctx, pruned); err != nil {
+		p.out.Warn(gotext.Get("Failed to prune the caches: %s", err))
+		return
+	}
+	p.out.Info(gotext.Get("Pruned %d cache entries, freed %s", len(pruned), units.FormatSize(cachegc.Total(pruned))))
+}
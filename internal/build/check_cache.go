@@ -20,20 +20,28 @@ package build
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/goreleaser/nfpm/v2"
+	"github.com/leonelquinteros/gotext"
 
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/buildcache"
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/scripter"
 	"go.stplr.dev/stplr/pkg/staplerfile"
 )
 
-type LocalCacheExecutor struct{ cfg commonbuild.Config }
+type LocalCacheExecutor struct {
+	cfg commonbuild.Config
+	out output.Output
+}
 
-func NewLocalCacheExecutor(cfg commonbuild.Config) *LocalCacheExecutor {
-	return &LocalCacheExecutor{cfg: cfg}
+func NewLocalCacheExecutor(cfg commonbuild.Config, out output.Output) *LocalCacheExecutor {
+	return &LocalCacheExecutor{cfg: cfg, out: out}
 }
 
 type CheckForBuiltPackageInput interface {
@@ -41,26 +49,108 @@ type CheckForBuiltPackageInput interface {
 	commonbuild.PkgFormatter
 	commonbuild.RepositoryGetter
 	commonbuild.BuildOptsProvider
+	ScriptPath() string
 }
 
+// CheckForBuiltPackage returns the built package of pkg if it was built
+// from the same inputs, followed by its debug info package if one was
+// built along with it. The inputs are compared by the cache manifests
+// stored next to the packages.
 func (c *LocalCacheExecutor) CheckForBuiltPackage(
 	ctx context.Context,
 	input CheckForBuiltPackageInput,
 	pkg *staplerfile.Package,
-) (string, bool, error) {
+) ([]*commonbuild.BuiltDep, bool, error) {
+	pkgPath, cached, err := c.cachedPackage(input, pkg)
+	if err != nil || cached == nil {
+		return nil, false, err
+	}
+	deps := []*commonbuild.BuiltDep{{Name: pkg.Name, Path: pkgPath}}
+
+	if cached.DebugInfo {
+		dbg := scripter.DebugInfoPackage(pkg, input.PkgFormat())
+		dbgPath, dbgCached, err := c.cachedPackage(input, dbg)
+		if err != nil || dbgCached == nil {
+			if err == nil {
+				c.out.Info(gotext.Get("Rebuilding %q: its debug info package %q is not cached", pkg.Name, dbg.Name))
+			}
+			return nil, false, err
+		}
+		deps = append(deps, &commonbuild.BuiltDep{Name: dbg.Name, Path: dbgPath, DebugInfo: true})
+	}
+
+	c.out.Info(gotext.Get("Using cached package %q, its inputs are unchanged (key %s)", pkg.Name, shortKey(cached.Key)))
+	return deps, true, nil
+}
+
+// cachedPackage returns the path and the cache manifest of the built
+// package of pkg, or a nil manifest if there is none or its inputs
+// changed.
+func (c *LocalCacheExecutor) cachedPackage(
+	input CheckForBuiltPackageInput,
+	pkg *staplerfile.Package,
+) (string, *buildcache.Manifest, error) {
 	filename, err := pkgFileName(input, pkg)
 	if err != nil {
-		return "", false, err
+		return "", nil, err
 	}
 
-	pkgPath := filepath.Join(commonbuild.GetBaseDir(c.cfg, pkg.Name), filename)
+	basePkg := pkg.BasePkgName
+	if basePkg == "" {
+		basePkg = pkg.Name
+	}
+	pkgPath := filepath.Join(commonbuild.GetBaseDir(c.cfg, basePkg), filename)
 
 	_, err = os.Stat(pkgPath)
 	if err != nil {
-		return "", false, nil
+		return "", nil, nil
+	}
+
+	cached, err := buildcache.Read(pkgPath)
+	if errors.Is(err, os.ErrNotExist) {
+		c.out.Info(gotext.Get("Rebuilding %q: the cached package has no cache manifest", pkg.Name))
+		return "", nil, nil
+	}
+	if err != nil {
+		c.out.Warn(gotext.Get("Rebuilding %q: %s", pkg.Name, err))
+		return "", nil, nil
+	}
+
+	current, err := buildcache.New(c.cfg, input, pkg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if changed := current.Changed(cached); len(changed) > 0 {
+		reasons := make([]string, 0, len(changed))
+		for _, name := range changed {
+			reasons = append(reasons, changeReason(name))
+		}
+		c.out.Info(gotext.Get("Rebuilding %q: %s", pkg.Name, strings.Join(reasons, ", ")))
+		return "", nil, nil
+	}
+	return pkgPath, cached, nil
+}
+
+func changeReason(input string) string {
+	switch input {
+	case buildcache.InputScript:
+		return gotext.Get("the Staplerfile or the files next to it changed")
+	case buildcache.InputSources:
+		return gotext.Get("the sources or checksums changed")
+	case buildcache.InputVars:
+		return gotext.Get("the package variables changed")
+	case buildcache.InputOptions:
+		return gotext.Get("the build options changed")
 	}
+	return gotext.Get("%s changed", input)
+}
 
-	return pkgPath, true, nil
+func shortKey(key string) string {
+	if len(key) > 12 {
+		return key[:12]
+	}
+	return key
 }
 
 func pkgFileName(input CheckForBuiltPackageInput, pkg *staplerfile.Package) (string, error) {
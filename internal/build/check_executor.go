@@ -50,17 +50,12 @@ func NewChecksRunner(mgr manager.Manager, cfg checksRunnerConfig) *ChecksRunner
 }
 
 func (r *ChecksRunner) RunChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) (bool, error) {
-	installed, err := r.mgr.ListInstalled(nil)
-	if err != nil {
-		return false, err
-	}
-
-	filename, err := pkgFileName(input, pkg)
+	instVer, err := r.installedVersion(pkg, input)
 	if err != nil {
 		return false, err
 	}
 
-	if instVer, ok := installed[filename]; ok {
+	if instVer != "" {
 		slog.Warn(gotext.Get("This package is already installed"),
 			"name", pkg.Name,
 			"version", instVer,
@@ -70,6 +65,37 @@ func (r *ChecksRunner) RunChecks(ctx context.Context, pkg *staplerfile.Package,
 	return true, nil
 }
 
+// FailedChecks returns the checks that pkg fails, without asking
+// whether to continue.
+func (r *ChecksRunner) FailedChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) ([]string, error) {
+	instVer, err := r.installedVersion(pkg, input)
+	if err != nil || instVer == "" {
+		return nil, err
+	}
+	return []string{gotext.Get("This package is already installed (version %s)", instVer)}, nil
+}
+
+// installedVersion returns the installed version of pkg or an empty
+// string if it isn't installed.
+func (r *ChecksRunner) installedVersion(pkg *staplerfile.Package, input *commonbuild.BuildInput) (string, error) {
+	if input.BuildOpts().CrossTarget {
+		// the package is not meant for this system
+		return "", nil
+	}
+
+	installed, err := r.mgr.ListInstalled(nil)
+	if err != nil {
+		return "", err
+	}
+
+	filename, err := pkgFileName(input, pkg)
+	if err != nil {
+		return "", err
+	}
+
+	return installed[filename], nil
+}
+
 func (r *ChecksRunner) RunPreChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) error {
 	if r.cfg.ForbidSkipInChecksums() {
 		checksums := pkg.Checksums.Resolved()
@@ -91,5 +117,6 @@ func (r *ChecksRunner) RunPreChecks(ctx context.Context, pkg *staplerfile.Packag
 
 type ChecksExecutor interface {
 	RunChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) (bool, error)
+	FailedChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) ([]string, error)
 	RunPreChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) error
 }
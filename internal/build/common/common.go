@@ -20,6 +20,7 @@ package common
 
 import (
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -29,7 +30,25 @@ import (
 	"go.stplr.dev/stplr/pkg/types"
 )
 
-func CreateBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string {
+// buildCaches are the caches of compilers and package managers that
+// are kept in the build cache directory of a package, the variables
+// point them to their subdirectories.
+var buildCaches = []struct {
+	env string
+	dir string
+}{
+	{"CCACHE_DIR", "ccache"},
+	{"SCCACHE_DIR", "sccache"},
+	{"GOCACHE", "go-build"},
+	{"GOMODCACHE", "go-mod"},
+	{"CARGO_HOME", "cargo"},
+	{"npm_config_cache", "npm"},
+	{"PIP_CACHE_DIR", "pip"},
+}
+
+// CreateBuildEnvVars returns the environment of the build script.
+// SOURCE_DATE_EPOCH is only set when sourceDateEpoch is not zero.
+func CreateBuildEnvVars(info *distro.OSRelease, dirs types.Directories, sourceDateEpoch int64) []string {
 	env := os.Environ()
 
 	env = append(
@@ -56,5 +75,15 @@ func CreateBuildEnvVars(info *distro.OSRelease, dirs types.Directories) []string
 		env = append(env, "HOME="+dirs.HomeDir)
 	}
 
+	if dirs.CacheDir != "" {
+		for _, c := range buildCaches {
+			env = append(env, c.env+"="+filepath.Join(dirs.CacheDir, c.dir))
+		}
+	}
+
+	if sourceDateEpoch != 0 {
+		env = append(env, "SOURCE_DATE_EPOCH="+strconv.FormatInt(sourceDateEpoch, 10))
+	}
+
 	return env
 }
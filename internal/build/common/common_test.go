@@ -33,10 +33,12 @@ import (
 
 func TestCreateBuildEnvVars(t *testing.T) {
 	tests := []struct {
-		name        string
-		info        *distro.OSRelease
-		dirs        types.Directories
-		expectedEnv map[string]string
+		name            string
+		info            *distro.OSRelease
+		dirs            types.Directories
+		sourceDateEpoch int64
+		expectedEnv     map[string]string
+		unexpectedEnv   []string
 	}{
 		{
 			name: "All fields populated",
@@ -82,12 +84,42 @@ func TestCreateBuildEnvVars(t *testing.T) {
 				"DISTRO_VERSION_ID":  "3.18",
 				"DISTRO_ID_LIKE":     "musl",
 			},
+			unexpectedEnv: []string{"SOURCE_DATE_EPOCH", "CCACHE_DIR"},
+		},
+		{
+			name: "Build cache",
+			info: &distro.OSRelease{
+				Name: "Fedora",
+				ID:   "fedora",
+			},
+			dirs: types.Directories{
+				SrcDir:   "/build/src",
+				CacheDir: "/cache/foo",
+			},
+			expectedEnv: map[string]string{
+				"CCACHE_DIR":  "/cache/foo/ccache",
+				"SCCACHE_DIR": "/cache/foo/sccache",
+				"GOCACHE":     "/cache/foo/go-build",
+				"CARGO_HOME":  "/cache/foo/cargo",
+			},
+		},
+		{
+			name: "Source date epoch",
+			info: &distro.OSRelease{
+				Name: "Debian",
+				ID:   "debian",
+			},
+			sourceDateEpoch: 1700000000,
+			expectedEnv: map[string]string{
+				"DISTRO_ID":         "debian",
+				"SOURCE_DATE_EPOCH": "1700000000",
+			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			envVars := common.CreateBuildEnvVars(tt.info, tt.dirs)
+			envVars := common.CreateBuildEnvVars(tt.info, tt.dirs, tt.sourceDateEpoch)
 
 			// Convert the resulting env slice to a map for easier assertion
 			envMap := make(map[string]string)
@@ -103,6 +135,10 @@ func TestCreateBuildEnvVars(t *testing.T) {
 				assert.True(t, exists, "Expected key %q to exist in env", key)
 				assert.Equal(t, expected, actual, "Mismatch for env var %q", key)
 			}
+
+			for _, key := range tt.unexpectedEnv {
+				assert.NotContains(t, envMap, key)
+			}
 		})
 	}
 }
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/cliprompts"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// DependencyCycleError is returned when Stapler packages depend on each other
+// in a loop, so no build order exists.
+type DependencyCycleError struct {
+	Cycle []string
+}
+
+func (e *DependencyCycleError) Error() string {
+	return gotext.Get("Dependency cycle detected: %s", strings.Join(e.Cycle, " -> "))
+}
+
+type depNode struct {
+	key      string
+	pkg      *staplerfile.Package
+	packages []string
+	// deps must be built before this node.
+	deps []*depNode
+}
+
+type depGraph struct {
+	nodes map[string]*depNode
+	roots []*depNode
+	// repoDeps are root dependencies that are not provided by any Stapler repo.
+	repoDeps []string
+}
+
+func depNodeKey(pkg *staplerfile.Package) string {
+	name := pkg.BasePkgName
+	if name == "" {
+		name = pkg.Name
+	}
+	return pkg.Repository + "/" + name
+}
+
+type depGraphResolver struct {
+	b         *Builder
+	input     InstallInput
+	overrides []string
+	graph     *depGraph
+}
+
+// resolveDepGraph looks up every Stapler package reachable from depends
+// through build_deps and deps and returns the resulting graph.
+func (b *Builder) resolveDepGraph(ctx context.Context, input InstallInput, depends []string) (*depGraph, error) {
+	r := staplerfile.NewResolver(input.OSRelease())
+	if err := r.Init(); err != nil {
+		return nil, err
+	}
+
+	res := &depGraphResolver{
+		b:         b,
+		input:     input,
+		overrides: r.Names(),
+		graph:     &depGraph{nodes: make(map[string]*depNode)},
+	}
+
+	roots, repoDeps, err := res.lookup(ctx, depends)
+	if err != nil {
+		return nil, err
+	}
+	res.graph.roots = roots
+	res.graph.repoDeps = repoDeps
+
+	return res.graph, nil
+}
+
+// lookup returns graph nodes for names, creating and expanding the nodes
+// that were not seen yet. Names not found in any repo are returned separately.
+func (r *depGraphResolver) lookup(ctx context.Context, names []string) ([]*depNode, []string, error) {
+	var nodes []*depNode
+	var notFound []string
+
+	for _, name := range removeDuplicates(names) {
+		pkgs, err := r.b.choosePkgs(ctx, r.input, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(pkgs) == 0 {
+			notFound = append(notFound, name)
+			continue
+		}
+
+		for _, item := range groupPackages(pkgs) {
+			key := depNodeKey(item.pkg)
+			node, ok := r.graph.nodes[key]
+			if ok {
+				for _, p := range item.packages {
+					if !slices.Contains(node.packages, p) {
+						node.packages = append(node.packages, p)
+					}
+				}
+			} else {
+				node = &depNode{key: key, pkg: item.pkg, packages: item.packages}
+				r.graph.nodes[key] = node
+				if err := r.expand(ctx, node); err != nil {
+					return nil, nil, err
+				}
+			}
+			if !slices.Contains(nodes, node) {
+				nodes = append(nodes, node)
+			}
+		}
+	}
+
+	return nodes, notFound, nil
+}
+
+func (r *depGraphResolver) expand(ctx context.Context, node *depNode) error {
+	pkg := *node.pkg
+	staplerfile.ResolvePackage(&pkg, r.overrides)
+
+	buildDeps, err := r.b.installerExecutor.RemoveAlreadyInstalled(ctx, pkg.BuildDepends.Resolved())
+	if err != nil {
+		return err
+	}
+
+	deps, _, err := r.lookup(ctx, append(buildDeps, pkg.Depends.Resolved()...))
+	if err != nil {
+		return err
+	}
+
+	for _, dep := range deps {
+		// Subpackages of the same base package are built together.
+		if dep != node {
+			node.deps = append(node.deps, dep)
+		}
+	}
+
+	return nil
+}
+
+// choosePkgs finds the packages providing name, asking the user to choose
+// when there are several. The choice is remembered for the whole run so
+// that nested builds don't ask again.
+func (b *Builder) choosePkgs(ctx context.Context, input InstallInput, name string) ([]staplerfile.Package, error) {
+	b.mu.Lock()
+	pkgs, ok := b.chosen[name]
+	b.mu.Unlock()
+	if ok {
+		return pkgs, nil
+	}
+
+	found, _, err := b.repos.FindPkgs(ctx, []string{name})
+	if err != nil {
+		return nil, fmt.Errorf("failed FindPkgs: %w", err)
+	}
+
+	b.promptMu.Lock()
+	pkgs, err = cliprompts.FlattenPkgs(ctx, found, "install", input.BuildOpts().Interactive)
+	b.promptMu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten packages: %w", err)
+	}
+
+	b.mu.Lock()
+	b.chosen[name] = pkgs
+	b.mu.Unlock()
+
+	return pkgs, nil
+}
+
+// order returns the nodes of g so that every node comes after its
+// dependencies.
+func (g *depGraph) order() ([]*depNode, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[*depNode]int, len(g.nodes))
+	var stack []*depNode
+	var out []*depNode
+
+	var visit func(n *depNode) error
+	visit = func(n *depNode) error {
+		switch state[n] {
+		case visited:
+			return nil
+		case visiting:
+			start := slices.Index(stack, n)
+			cycle := make([]string, 0, len(stack)-start+1)
+			for _, s := range stack[start:] {
+				cycle = append(cycle, s.key)
+			}
+			return &DependencyCycleError{Cycle: append(cycle, n.key)}
+		}
+
+		state[n] = visiting
+		stack = append(stack, n)
+		for _, dep := range n.deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[n] = visited
+		out = append(out, n)
+		return nil
+	}
+
+	for _, root := range g.roots {
+		if err := visit(root); err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
+}
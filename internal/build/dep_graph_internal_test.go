@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/manager"
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type fakeFinder struct {
+	pkgs map[string]staplerfile.Package
+}
+
+func (f *fakeFinder) FindPkgs(ctx context.Context, names []string) (map[string][]staplerfile.Package, []string, error) {
+	found := make(map[string][]staplerfile.Package)
+	var notFound []string
+	for _, name := range names {
+		if pkg, ok := f.pkgs[name]; ok {
+			found[name] = []staplerfile.Package{pkg}
+		} else {
+			notFound = append(notFound, name)
+		}
+	}
+	return found, notFound, nil
+}
+
+func (f *fakeFinder) GetRepo(name string) (types.Repo, error) {
+	return types.Repo{Name: name}, nil
+}
+
+type fakeInstaller struct {
+	installed []string
+}
+
+func (i *fakeInstaller) InstallLocal(ctx context.Context, paths []string, opts *manager.Opts) error {
+	return nil
+}
+
+func (i *fakeInstaller) Install(ctx context.Context, pkgs []string, opts *manager.Opts) error {
+	return nil
+}
+
+func (i *fakeInstaller) Remove(ctx context.Context, pkgs []string, opts *manager.Opts) error {
+	return nil
+}
+
+func (i *fakeInstaller) RemoveAlreadyInstalled(ctx context.Context, pkgs []string) ([]string, error) {
+	var out []string
+	for _, p := range pkgs {
+		if !slices.Contains(i.installed, p) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func testPkg(name string, buildDeps, deps []string) staplerfile.Package {
+	return staplerfile.Package{
+		Repository:   "repo",
+		Name:         name,
+		BuildDepends: staplerfile.OverridableFromMap(map[string][]string{"": buildDeps}),
+		Depends:      staplerfile.OverridableFromMap(map[string][]string{"": deps}),
+	}
+}
+
+func newTestBuilder(pkgs []staplerfile.Package, installed []string) *Builder {
+	finder := &fakeFinder{pkgs: make(map[string]staplerfile.Package)}
+	for _, p := range pkgs {
+		finder.pkgs[p.Name] = p
+	}
+	return NewBuilder(nil, nil, nil, nil, &fakeInstaller{installed: installed}, nil, nil, nil, finder, nil)
+}
+
+func testInput(jobs int) *BuildArgs {
+	return &BuildArgs{
+		Opts: &types.BuildOpts{Jobs: jobs},
+		Info: &distro.OSRelease{ID: "test"},
+	}
+}
+
+func orderKeys(t *testing.T, g *depGraph) []string {
+	t.Helper()
+	order, err := g.order()
+	require.NoError(t, err)
+	var keys []string
+	for _, n := range order {
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+func TestResolveDepGraph(t *testing.T) {
+	b := newTestBuilder([]staplerfile.Package{
+		testPkg("app", []string{"libfoo", "make"}, []string{"libbar"}),
+		testPkg("libfoo", nil, []string{"libbase"}),
+		testPkg("libbar", nil, []string{"libbase", "glibc"}),
+		testPkg("libbase", nil, nil),
+	}, []string{"make"})
+
+	g, err := b.resolveDepGraph(context.Background(), testInput(1), []string{"app", "curl"})
+	require.NoError(t, err)
+
+	assert.Len(t, g.nodes, 4)
+	assert.Equal(t, []string{"curl"}, g.repoDeps)
+	require.Len(t, g.roots, 1)
+	assert.Equal(t, "repo/app", g.roots[0].key)
+
+	keys := orderKeys(t, g)
+	assert.Equal(t, "repo/libbase", keys[0])
+	assert.Equal(t, "repo/app", keys[len(keys)-1])
+}
+
+func TestResolveDepGraphCycle(t *testing.T) {
+	b := newTestBuilder([]staplerfile.Package{
+		testPkg("a", []string{"b"}, nil),
+		testPkg("b", nil, []string{"c"}),
+		testPkg("c", nil, []string{"a"}),
+	}, nil)
+
+	g, err := b.resolveDepGraph(context.Background(), testInput(1), []string{"a"})
+	require.NoError(t, err)
+
+	_, err = g.order()
+	var cycleErr *DependencyCycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.Equal(t, []string{"repo/a", "repo/b", "repo/c", "repo/a"}, cycleErr.Cycle)
+}
+
+func TestScheduleDepGraph(t *testing.T) {
+	b := newTestBuilder([]staplerfile.Package{
+		testPkg("app", []string{"a", "b", "c"}, nil),
+		testPkg("a", nil, []string{"base"}),
+		testPkg("b", nil, []string{"base"}),
+		testPkg("c", nil, []string{"base"}),
+		testPkg("base", nil, nil),
+	}, nil)
+
+	g, err := b.resolveDepGraph(context.Background(), testInput(2), []string{"app"})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	done := make(map[string]bool)
+	var running, maxRunning atomic.Int32
+
+	res, err := scheduleDepGraph(context.Background(), g, newJobSlots(2), func(ctx context.Context, n *depNode) ([]*commonbuild.BuiltDep, error) {
+		cur := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := maxRunning.Load()
+			if cur <= old || maxRunning.CompareAndSwap(old, cur) {
+				break
+			}
+		}
+
+		mu.Lock()
+		for _, dep := range n.deps {
+			assert.True(t, done[dep.key], "%s built before %s", n.key, dep.key)
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		done[n.key] = true
+		mu.Unlock()
+
+		return []*commonbuild.BuiltDep{{Name: n.pkg.Name}}, nil
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, res, 5)
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestScheduleDepGraphStopsOnError(t *testing.T) {
+	b := newTestBuilder([]staplerfile.Package{
+		testPkg("app", []string{"broken"}, nil),
+		testPkg("broken", nil, nil),
+	}, nil)
+
+	g, err := b.resolveDepGraph(context.Background(), testInput(4), []string{"app"})
+	require.NoError(t, err)
+
+	var built []string
+	_, err = scheduleDepGraph(context.Background(), g, newJobSlots(4), func(ctx context.Context, n *depNode) ([]*commonbuild.BuiltDep, error) {
+		built = append(built, n.key)
+		if n.key == "repo/broken" {
+			return nil, errors.New("boom")
+		}
+		return nil, nil
+	})
+	require.Error(t, err)
+	assert.Equal(t, []string{"repo/broken"}, built)
+}
+
+func TestScheduleDepsNested(t *testing.T) {
+	b := newTestBuilder([]staplerfile.Package{
+		testPkg("app", []string{"a", "b", "c"}, nil),
+		testPkg("a", nil, nil),
+		testPkg("b", nil, nil),
+		testPkg("c", nil, nil),
+		testPkg("tool", []string{"x", "y", "z"}, nil),
+		testPkg("x", nil, nil),
+		testPkg("y", nil, nil),
+		testPkg("z", nil, nil),
+	}, nil)
+	input := testInput(2)
+
+	outer, err := b.resolveDepGraph(context.Background(), input, []string{"a", "b", "c"})
+	require.NoError(t, err)
+	nested, err := b.resolveDepGraph(context.Background(), input, []string{"x", "y", "z"})
+	require.NoError(t, err)
+
+	var running, maxRunning atomic.Int32
+	var build buildFunc
+	build = func(ctx context.Context, n *depNode) ([]*commonbuild.BuiltDep, error) {
+		// every package of the outer graph builds the nested graph
+		// of its own build dependencies first
+		if slices.Contains([]string{"a", "b", "c"}, n.pkg.Name) {
+			if _, err := b.scheduleDeps(ctx, input, nested, build); err != nil {
+				return nil, err
+			}
+		}
+
+		cur := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := maxRunning.Load()
+			if cur <= old || maxRunning.CompareAndSwap(old, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return []*commonbuild.BuiltDep{{Name: n.pkg.Name}}, nil
+	}
+
+	res, err := b.scheduleDeps(context.Background(), input, outer, build)
+	require.NoError(t, err)
+	assert.Len(t, res, 3)
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestBuiltAll(t *testing.T) {
+	deps := []*commonbuild.BuiltDep{{Name: "foo"}, {Name: "foo-libs"}}
+
+	assert.True(t, builtAll(deps, []string{"foo", "foo-libs"}))
+	assert.False(t, builtAll(deps, []string{"foo-doc"}))
+}
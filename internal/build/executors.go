@@ -26,7 +26,15 @@ import (
 )
 
 type CacheExecutor interface {
-	CheckForBuiltPackage(ctx context.Context, input CheckForBuiltPackageInput, pkgs *staplerfile.Package) (string, bool, error)
+	// CheckForBuiltPackage returns the cached package of pkg,
+	// followed by its debug info package if there is one.
+	CheckForBuiltPackage(ctx context.Context, input CheckForBuiltPackageInput, pkg *staplerfile.Package) ([]*commonbuild.BuiltDep, bool, error)
+}
+
+type CachePruner interface {
+	// Prune applies the cache limits of the configuration,
+	// keeping the packages at keep.
+	Prune(ctx context.Context, keep []string)
 }
 
 type SourceDownloaderExecutor interface {
@@ -23,7 +23,10 @@
 package build
 
 import (
+	"github.com/spf13/afero"
+
 	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/buildlog"
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/db"
 	"go.stplr.dev/stplr/internal/installer"
@@ -34,6 +37,7 @@ import (
 type mainBuilderConfig interface {
 	commonbuild.Config
 	checksRunnerConfig
+	cachePrunerConfig
 }
 
 func NewMainBuilder(
@@ -49,7 +53,7 @@ func NewMainBuilder(
 		cfg,
 		NewScriptResolver(cfg),
 		scriptExecutor,
-		NewLocalCacheExecutor(cfg),
+		NewLocalCacheExecutor(cfg, out),
 		installerExecutor,
 		NewLocalSourceDownloader(cfg, out),
 		NewChecksRunner(mgr, cfg),
@@ -57,6 +61,8 @@ func NewMainBuilder(
 		repos,
 		NewScriptViewer(cfg),
 	)
+	builder.logs = buildlog.NewStore(afero.NewOsFs(), buildlog.Dir(cfg.GetPaths().CacheDir))
+	builder.cachePruner = NewLocalCachePruner(cfg, out)
 
 	return builder, nil
 }
@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+)
+
+// Plan describes what a build would do. With BuildOpts.DryRun set the
+// steps fill it instead of changing anything on the system.
+type Plan struct {
+	mu sync.Mutex
+
+	// Builds are listed in the order they would run.
+	Builds []PlannedBuild `json:"builds"`
+	// Cached packages would be taken from the built-package cache.
+	Cached []PlannedPackage `json:"cached"`
+	// Install lists native packages that would be passed to the
+	// package manager.
+	Install []string `json:"install"`
+	// InstallLocal lists built packages that would be installed.
+	InstallLocal []string `json:"install_local"`
+	// RemoveBuildDeps lists build dependencies that would be offered
+	// for removal after the build.
+	RemoveBuildDeps []string `json:"remove_build_deps"`
+	// FailedChecks lists the checks that would ask whether to continue
+	// the build.
+	FailedChecks []PlannedCheck `json:"failed_checks"`
+}
+
+type PlannedBuild struct {
+	Repository  string   `json:"repository"`
+	BasePackage string   `json:"base_package"`
+	Version     string   `json:"version"`
+	Packages    []string `json:"packages"`
+	Sources     []string `json:"sources"`
+}
+
+type PlannedPackage struct {
+	Repository string `json:"repository"`
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+}
+
+type PlannedCheck struct {
+	Repository string `json:"repository"`
+	Name       string `json:"name"`
+	Message    string `json:"message"`
+}
+
+func NewPlan() *Plan {
+	return &Plan{
+		Builds:          []PlannedBuild{},
+		Cached:          []PlannedPackage{},
+		Install:         []string{},
+		InstallLocal:    []string{},
+		RemoveBuildDeps: []string{},
+		FailedChecks:    []PlannedCheck{},
+	}
+}
+
+// dryRunner is implemented by steps that change the system. In dry-run
+// mode DryRun is called instead of Run and only records to the plan.
+type dryRunner interface {
+	DryRun(ctx context.Context, state *BuildState) error
+}
+
+// planFor returns the plan to record to, or nil when input is not
+// a dry run. All the recording methods are no-ops on a nil plan.
+func (b *Builder) planFor(input commonbuild.BuildOptsProvider) *Plan {
+	if input.BuildOpts().DryRun {
+		return b.plan
+	}
+	return nil
+}
+
+// Plan returns what the dry runs done by this builder would do.
+func (b *Builder) Plan() *Plan {
+	return b.plan
+}
+
+func (p *Plan) addBuild(build PlannedBuild) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Builds = append(p.Builds, build)
+}
+
+func (p *Plan) addCached(pkg PlannedPackage) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Cached = append(p.Cached, pkg)
+}
+
+func (p *Plan) addInstall(pkgs []string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Install = appendUnique(p.Install, pkgs)
+}
+
+func (p *Plan) addInstallLocal(pkgs []string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.InstallLocal = appendUnique(p.InstallLocal, pkgs)
+}
+
+func (p *Plan) addRemoveBuildDeps(pkgs []string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.RemoveBuildDeps = appendUnique(p.RemoveBuildDeps, pkgs)
+}
+
+func (p *Plan) addFailedCheck(check PlannedCheck) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.FailedChecks = append(p.FailedChecks, check)
+}
+
+func appendUnique(s, items []string) []string {
+	for _, item := range items {
+		if !slices.Contains(s, item) {
+			s = append(s, item)
+		}
+	}
+	return s
+}
+
+func (p *Plan) IsEmpty() bool {
+	return len(p.Builds) == 0 &&
+		len(p.Cached) == 0 &&
+		len(p.Install) == 0 &&
+		len(p.InstallLocal) == 0 &&
+		len(p.RemoveBuildDeps) == 0 &&
+		len(p.FailedChecks) == 0
+}
+
+// Write writes the plan as JSON or as human-readable text.
+func (p *Plan) Write(w io.Writer, asJSON bool) error {
+	if asJSON {
+		return p.WriteJSON(w)
+	}
+	return p.WriteText(w)
+}
+
+func (p *Plan) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+func (p *Plan) WriteText(w io.Writer) error {
+	var err error
+	printf := func(format string, a ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, a...)
+		}
+	}
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		printf("%s\n", title)
+		for _, item := range items {
+			printf("  %s\n", item)
+		}
+	}
+
+	if p.IsEmpty() {
+		printf("%s\n", gotext.Get("There is nothing to do."))
+		return err
+	}
+
+	if len(p.Builds) > 0 {
+		printf("%s\n", gotext.Get("Packages to build (in order):"))
+		for i, b := range p.Builds {
+			printf("  %d. %s/%s %s", i+1, b.Repository, b.BasePackage, b.Version)
+			if len(b.Packages) > 1 || len(b.Packages) == 1 && b.Packages[0] != b.BasePackage {
+				printf(" (%s)", strings.Join(b.Packages, ", "))
+			}
+			printf("\n")
+			for _, src := range b.Sources {
+				printf("     %s %s\n", gotext.Get("source:"), src)
+			}
+		}
+	}
+
+	if len(p.Cached) > 0 {
+		printf("%s\n", gotext.Get("Found in cache:"))
+		for _, c := range p.Cached {
+			printf("  %s/%s: %s\n", c.Repository, c.Name, c.Path)
+		}
+	}
+
+	list(gotext.Get("Built packages to install:"), p.InstallLocal)
+	list(gotext.Get("Native packages to install:"), p.Install)
+	list(gotext.Get("Build dependencies to remove afterwards:"), p.RemoveBuildDeps)
+
+	if len(p.FailedChecks) > 0 {
+		printf("%s\n", gotext.Get("Failed checks:"))
+		for _, c := range p.FailedChecks {
+			printf("  %s/%s: %s\n", c.Repository, c.Name, c.Message)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type recordingStep struct {
+	ran, dryRan bool
+}
+
+func (s *recordingStep) Name() string { return "recording" }
+
+func (s *recordingStep) Run(ctx context.Context, state *BuildState) error {
+	s.ran = true
+	return nil
+}
+
+func (s *recordingStep) DryRun(ctx context.Context, state *BuildState) error {
+	s.dryRan = true
+	return nil
+}
+
+func TestRunStepsDryRun(t *testing.T) {
+	step := &recordingStep{}
+	_, err := runSteps(context.Background(), &BuildState{Plan: NewPlan()}, []BuildStep{step})
+	require.NoError(t, err)
+	assert.False(t, step.ran)
+	assert.True(t, step.dryRan)
+
+	step = &recordingStep{}
+	_, err = runSteps(context.Background(), &BuildState{}, []BuildStep{step})
+	require.NoError(t, err)
+	assert.True(t, step.ran)
+	assert.False(t, step.dryRan)
+}
+
+type fakeChecks struct {
+	failed map[string][]string
+}
+
+func (f *fakeChecks) RunChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) (bool, error) {
+	panic("RunChecks must not be called in dry-run mode")
+}
+
+func (f *fakeChecks) FailedChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) ([]string, error) {
+	return f.failed[pkg.Name], nil
+}
+
+func (f *fakeChecks) RunPreChecks(ctx context.Context, pkg *staplerfile.Package, input *commonbuild.BuildInput) error {
+	return nil
+}
+
+func TestDryRunSteps(t *testing.T) {
+	ctx := context.Background()
+	plan := NewPlan()
+
+	state := &BuildState{
+		Input: &commonbuild.BuildInput{
+			Opts: &types.BuildOpts{DryRun: true},
+		},
+		Repository:  "repo",
+		BasePackage: "foo",
+		Version:     "1.0",
+		Packages: []*staplerfile.Package{
+			{Name: "foo"},
+			{Name: "foo-libs"},
+		},
+		FlatVars: flatVars{
+			Sources: []string{"https://example.com/foo-1.0.tar.gz"},
+		},
+		InstalledBuildDeps: []string{"gcc"},
+		Plan:               plan,
+	}
+
+	checks := &fakeChecks{failed: map[string][]string{
+		"foo-libs": {"This package is already installed (version 0.9)"},
+	}}
+	require.NoError(t, ChecksStep(checks).DryRun(ctx, state))
+	require.NoError(t, BuildPackagesStep(nil, nil).DryRun(ctx, state))
+	require.NoError(t, PostStep(nil, nil).DryRun(ctx, state))
+
+	assert.Equal(t, []PlannedBuild{{
+		Repository:  "repo",
+		BasePackage: "foo",
+		Version:     "1.0",
+		Packages:    []string{"foo", "foo-libs"},
+		Sources:     []string{"https://example.com/foo-1.0.tar.gz"},
+	}}, plan.Builds)
+	assert.Equal(t, []string{"gcc"}, plan.RemoveBuildDeps)
+	assert.Equal(t, []PlannedCheck{{
+		Repository: "repo",
+		Name:       "foo-libs",
+		Message:    "This package is already installed (version 0.9)",
+	}}, plan.FailedChecks)
+	assert.Len(t, state.BuiltDeps, 2)
+}
+
+func TestPlanWrite(t *testing.T) {
+	plan := NewPlan()
+	plan.addBuild(PlannedBuild{
+		Repository:  "repo",
+		BasePackage: "foo",
+		Version:     "1.0",
+		Packages:    []string{"foo"},
+		Sources:     []string{"https://example.com/foo.tar.gz"},
+	})
+	plan.addCached(PlannedPackage{Repository: "repo", Name: "bar", Path: "/cache/bar.deb"})
+	plan.addInstall([]string{"make", "make"})
+	plan.addInstallLocal([]string{"foo"})
+	plan.addRemoveBuildDeps([]string{"make"})
+	plan.addFailedCheck(PlannedCheck{Repository: "repo", Name: "foo", Message: "This package is already installed (version 0.9)"})
+
+	var text bytes.Buffer
+	require.NoError(t, plan.Write(&text, false))
+	assert.Equal(t, `Packages to build (in order):
+  1. repo/foo 1.0
+     source: https://example.com/foo.tar.gz
+Found in cache:
+  repo/bar: /cache/bar.deb
+Built packages to install:
+  foo
+Native packages to install:
+  make
+Build dependencies to remove afterwards:
+  make
+Failed checks:
+  repo/foo: This package is already installed (version 0.9)
+`, text.String())
+
+	var out bytes.Buffer
+	require.NoError(t, plan.Write(&out, true))
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, []any{"make"}, decoded["install"])
+	assert.Len(t, decoded["builds"], 1)
+}
+
+func TestPlanWriteEmpty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, NewPlan().Write(&out, true))
+	assert.Contains(t, out.String(), `"builds": []`)
+
+	out.Reset()
+	require.NoError(t, NewPlan().Write(&out, false))
+	assert.Equal(t, "There is nothing to do.\n", out.String())
+}
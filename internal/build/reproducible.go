@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// SourceDateEpoch returns the timestamp used as SOURCE_DATE_EPOCH for
+// script: the commit time of the git repository containing the script
+// or, if it is not in a repository, the script modification time.
+// It returns 0 if neither is available.
+func SourceDateEpoch(script string) int64 {
+	epoch, err := commitTime(filepath.Dir(script))
+	if err == nil {
+		return epoch
+	}
+	slog.Debug("using script mtime as SOURCE_DATE_EPOCH", "script", script, "err", err)
+
+	fi, err := os.Stat(script)
+	if err != nil {
+		slog.Debug("failed to stat script", "script", script, "err", err)
+		return 0
+	}
+	return fi.ModTime().Unix()
+}
+
+func commitTime(dir string) (int64, error) {
+	r, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return 0, err
+	}
+	ref, err := r.Head()
+	if err != nil {
+		return 0, err
+	}
+	commit, err := r.CommitObject(ref.Hash())
+	if err != nil {
+		return 0, err
+	}
+	return commit.Committer.When.Unix(), nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceDateEpochFromCommit(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "foo", "Staplerfile")
+	require.NoError(t, os.MkdirAll(filepath.Dir(script), 0o755))
+	require.NoError(t, os.WriteFile(script, []byte("name=foo\n"), 0o644))
+
+	r, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add("foo/Staplerfile")
+	require.NoError(t, err)
+
+	when := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	_, err = w.Commit("add foo", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: when},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, when.Unix(), SourceDateEpoch(script))
+}
+
+func TestSourceDateEpochFromMtime(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "Staplerfile")
+	require.NoError(t, os.WriteFile(script, []byte("name=foo\n"), 0o644))
+
+	mtime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, os.Chtimes(script, mtime, mtime))
+
+	assert.Equal(t, mtime.Unix(), SourceDateEpoch(script))
+}
+
+func TestSourceDateEpochMissing(t *testing.T) {
+	assert.Zero(t, SourceDateEpoch(filepath.Join(t.TempDir(), "Staplerfile")))
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"slices"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/cliprompts"
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/manager"
+	"go.stplr.dev/stplr/internal/scripter"
+)
+
+// beginRun marks the start of a build or of a dependency graph.
+func (b *Builder) beginRun() {
+	b.mu.Lock()
+	b.running++
+	b.mu.Unlock()
+}
+
+// deferBuildDeps remembers the build dependencies installed by a build.
+// They are removed at the end of the outermost build, as other builds
+// of the run may need them too while they don't install them.
+func (b *Builder) deferBuildDeps(deps []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, dep := range deps {
+		if !slices.Contains(b.buildDeps, dep) {
+			b.buildDeps = append(b.buildDeps, dep)
+		}
+	}
+}
+
+// keepBaseDir remembers the base directory of basePkg, so that pruning
+// at the end of the run keeps the build directories of the package.
+func (b *Builder) keepBaseDir(basePkg string) {
+	dir := commonbuild.GetBaseDir(b.cfg, basePkg)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !slices.Contains(b.baseDirs, dir) {
+		b.baseDirs = append(b.baseDirs, dir)
+	}
+}
+
+// endRun marks the end of a build or of a dependency graph started by
+// beginRun. When the outermost one succeeds, no other build is running,
+// so the build dependencies installed during the run are removed and
+// the caches are pruned, keeping the packages built in the run and res
+// along with the build directories of their base packages.
+func (b *Builder) endRun(ctx context.Context, input commonbuild.BuildOptsProvider, res []*commonbuild.BuiltDep, runErr error) error {
+	b.mu.Lock()
+	b.running--
+	if b.running > 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	deps := b.buildDeps
+	b.buildDeps = nil
+	keep := append(getPaths(res), b.baseDirs...)
+	b.baseDirs = nil
+	b.slots = nil
+	for _, built := range b.built {
+		keep = append(keep, getPaths(built)...)
+	}
+	b.mu.Unlock()
+
+	if runErr != nil || b.planFor(input) != nil {
+		return nil
+	}
+
+	if err := b.removeBuildDeps(ctx, input, deps); err != nil {
+		return err
+	}
+	if b.cachePruner != nil {
+		b.cachePruner.Prune(ctx, keep)
+	}
+	return nil
+}
+
+func (b *Builder) removeBuildDeps(ctx context.Context, input commonbuild.BuildOptsProvider, deps []string) error {
+	if len(deps) == 0 {
+		return nil
+	}
+
+	b.promptMu.Lock()
+	remove, err := cliprompts.YesNoPrompt(ctx, gotext.Get("Would you like to remove the build dependencies?"), input.BuildOpts().Interactive, false)
+	b.promptMu.Unlock()
+	if err != nil || !remove {
+		return err
+	}
+
+	return b.installerExecutor.Remove(ctx, deps, &manager.Opts{
+		NoConfirm: !input.BuildOpts().Interactive,
+	})
+}
+
+// getPaths returns the paths of all packages of deps,
+// including the debug info packages.
+func getPaths(deps []*commonbuild.BuiltDep) []string {
+	return scripter.Map(deps, func(dep *commonbuild.BuiltDep) string {
+		return dep.Path
+	})
+}
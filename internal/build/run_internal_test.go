@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+)
+
+type fakePruner struct {
+	calls int
+	keep  []string
+}
+
+func (p *fakePruner) Prune(ctx context.Context, keep []string) {
+	p.calls++
+	p.keep = keep
+}
+
+func TestBuildDepsKeptUntilOutermostRunEnds(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBuilder(nil, nil)
+	input := testInput(2)
+
+	b.beginRun()
+	b.beginRun()
+	b.deferBuildDeps([]string{"gcc", "make"})
+	b.beginRun()
+	b.deferBuildDeps([]string{"gcc", "cmake"})
+	require.NoError(t, b.endRun(ctx, input, nil, nil))
+	require.NoError(t, b.endRun(ctx, input, nil, nil))
+	assert.Equal(t, []string{"gcc", "make", "cmake"}, b.buildDeps)
+
+	require.NoError(t, b.endRun(ctx, input, nil, nil))
+	assert.Empty(t, b.buildDeps)
+	assert.Zero(t, b.running)
+}
+
+func TestFailedRunForgetsBuildDeps(t *testing.T) {
+	ctx := context.Background()
+	b := newTestBuilder(nil, nil)
+
+	b.beginRun()
+	b.deferBuildDeps([]string{"gcc"})
+	require.NoError(t, b.endRun(ctx, testInput(1), nil, errors.New("build failed")))
+	assert.Empty(t, b.buildDeps)
+	assert.Zero(t, b.running)
+}
+
+func TestCachesPrunedOnceAfterRun(t *testing.T) {
+	ctx := context.Background()
+	pruner := &fakePruner{}
+	b := newTestBuilder(nil, nil)
+	b.cachePruner = pruner
+	input := testInput(2)
+
+	b.beginRun()
+	b.beginRun()
+	b.built["rpm:repo/a"] = []*commonbuild.BuiltDep{{Name: "a", Path: "/pkgs/a/a.rpm"}}
+	b.baseDirs = []string{"/pkgs/a"}
+	require.NoError(t, b.endRun(ctx, input, b.built["rpm:repo/a"], nil))
+	assert.Zero(t, pruner.calls)
+
+	top := []*commonbuild.BuiltDep{
+		{Name: "top", Path: "/pkgs/top/top.rpm"},
+		{Name: "top-debuginfo", Path: "/pkgs/top/top-debuginfo.rpm", DebugInfo: true},
+	}
+	require.NoError(t, b.endRun(ctx, input, top, nil))
+	assert.Equal(t, 1, pruner.calls)
+	assert.ElementsMatch(t, []string{"/pkgs/a/a.rpm", "/pkgs/a", "/pkgs/top/top.rpm", "/pkgs/top/top-debuginfo.rpm"}, pruner.keep)
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/installer"
+	"go.stplr.dev/stplr/internal/manager"
+)
+
+// buildFunc builds a single node of the dependency graph.
+type buildFunc func(ctx context.Context, node *depNode) ([]*commonbuild.BuiltDep, error)
+
+func jobsLimit(input InstallInput) int {
+	return max(input.BuildOpts().Jobs, 1)
+}
+
+// jobSlots limits the number of packages built at the same time. All
+// dependency graphs of a run share them, including the nested graphs
+// of build dependencies, so that the limit is global.
+type jobSlots chan struct{}
+
+func newJobSlots(jobs int) jobSlots {
+	return make(jobSlots, jobs)
+}
+
+func (s jobSlots) acquire(ctx context.Context) error {
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s jobSlots) release() {
+	<-s
+}
+
+// jobSlotKey marks the contexts of builds that hold a job slot.
+type jobSlotKey struct{}
+
+// scheduleDeps runs build for every node of g with the job slots of the
+// run. A build that schedules the graph of its own dependencies gives
+// its slot away while it waits for them, so that nested graphs can't
+// run out of slots held by their parents.
+func (b *Builder) scheduleDeps(ctx context.Context, input InstallInput, g *depGraph, build buildFunc) (map[*depNode][]*commonbuild.BuiltDep, error) {
+	b.mu.Lock()
+	if b.slots == nil {
+		b.slots = newJobSlots(jobsLimit(input))
+	}
+	slots := b.slots
+	b.mu.Unlock()
+
+	if ctx.Value(jobSlotKey{}) != nil {
+		slots.release()
+		defer func() {
+			slots <- struct{}{}
+		}()
+	}
+
+	return scheduleDepGraph(ctx, g, slots, build)
+}
+
+// scheduleDepGraph runs build for every node of g, starting a node only
+// after all of its dependencies have been built. A node is built only
+// while it holds one of slots. The first failure cancels the remaining
+// builds.
+func scheduleDepGraph(ctx context.Context, g *depGraph, slots jobSlots, build buildFunc) (map[*depNode][]*commonbuild.BuiltDep, error) {
+	order, err := g.order()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := make(map[*depNode]int, len(order))
+	dependents := make(map[*depNode][]*depNode, len(order))
+	var ready []*depNode
+	for _, n := range order {
+		pending[n] = len(n.deps)
+		for _, dep := range n.deps {
+			dependents[dep] = append(dependents[dep], n)
+		}
+		if len(n.deps) == 0 {
+			ready = append(ready, n)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		node *depNode
+		res  []*commonbuild.BuiltDep
+		err  error
+	}
+
+	results := make(chan result)
+	out := make(map[*depNode][]*commonbuild.BuiltDep, len(order))
+	var wg sync.WaitGroup
+	var firstErr error
+	running := 0
+
+	for len(out) < len(order) {
+		for firstErr == nil && len(ready) > 0 {
+			n := ready[0]
+			ready = ready[1:]
+			running++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := slots.acquire(ctx); err != nil {
+					results <- result{n, nil, err}
+					return
+				}
+				slog.Debug("building dependency", "pkg", n.key)
+				res, err := build(context.WithValue(ctx, jobSlotKey{}, true), n)
+				slots.release()
+				results <- result{n, res, err}
+			}()
+		}
+
+		if running == 0 {
+			break
+		}
+
+		r := <-results
+		running--
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to build %s: %w", r.node.key, r.err)
+				cancel()
+			}
+			continue
+		}
+
+		out[r.node] = r.res
+		for _, d := range dependents[r.node] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+		// keep the order stable to make logs easier to follow
+		slices.SortStableFunc(ready, func(a, b *depNode) int {
+			return slices.Index(order, a) - slices.Index(order, b)
+		})
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return out, nil
+}
+
+// serialInstaller lets parallel builds share a single package manager,
+// which can't run several transactions at once.
+type serialInstaller struct {
+	mu   sync.Mutex
+	next installer.InstallerExecutor
+}
+
+func newSerialInstaller(next installer.InstallerExecutor) *serialInstaller {
+	return &serialInstaller{next: next}
+}
+
+func (i *serialInstaller) InstallLocal(ctx context.Context, paths []string, opts *manager.Opts) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.next.InstallLocal(ctx, paths, opts)
+}
+
+func (i *serialInstaller) Install(ctx context.Context, pkgs []string, opts *manager.Opts) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.next.Install(ctx, pkgs, opts)
+}
+
+func (i *serialInstaller) Remove(ctx context.Context, pkgs []string, opts *manager.Opts) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.next.Remove(ctx, pkgs, opts)
+}
+
+func (i *serialInstaller) RemoveAlreadyInstalled(ctx context.Context, pkgs []string) ([]string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.next.RemoveAlreadyInstalled(ctx, pkgs)
+}
+
+// serialStep makes sure that only one build at a time runs the step.
+// It is used for steps that talk to the user.
+type serialStep struct {
+	mu   *sync.Mutex
+	step BuildStep
+}
+
+func (s *serialStep) Name() string {
+	return s.step.Name()
+}
+
+func (s *serialStep) Run(ctx context.Context, state *BuildState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.step.Run(ctx, state)
+}
+
+func (s *serialStep) DryRun(ctx context.Context, state *BuildState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if dr, ok := s.step.(dryRunner); ok {
+		return dr.DryRun(ctx, state)
+	}
+	return s.step.Run(ctx, state)
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"log/slog"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// ScriptSource returns the URL of the origin remote and the HEAD commit
+// of the git repository containing script. Both are empty if the script
+// is not in a repository.
+func ScriptSource(script string) (url, commit string) {
+	r, err := git.PlainOpenWithOptions(filepath.Dir(script), &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		slog.Debug("script is not in a git repository", "script", script, "err", err)
+		return "", ""
+	}
+
+	if ref, err := r.Head(); err == nil {
+		commit = ref.Hash().String()
+	}
+	if remote, err := r.Remote(git.DefaultRemoteName); err == nil && len(remote.Config().URLs) > 0 {
+		url = remote.Config().URLs[0]
+	}
+
+	return url, commit
+}
@@ -21,13 +21,20 @@ package build
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/leonelquinteros/gotext"
+
 	"go.stplr.dev/stplr/internal/app/output"
 	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/srcsig"
 	"go.stplr.dev/stplr/pkg/dl"
 	"go.stplr.dev/stplr/pkg/dl/cache/local"
 )
@@ -59,7 +66,24 @@ func (s *LocalSourceDownloader) DownloadSources(
 		return err
 	}
 
+	validKeys, err := srcsig.ParseFingerprints(si.ValidPGPKeys)
+	if err != nil {
+		return fmt.Errorf("validpgpkeys: %w", err)
+	}
+
+	signatures := signedSources(si.Sources)
+	names := make([]string, len(si.Sources))
+	var extract []int
+
 	for i, src := range si.Sources {
+		// signed archives are extracted only after their signature is checked
+		if _, signed := signatures[i]; signed {
+			var unpack bool
+			if src, unpack = withoutExtraction(src); unpack {
+				extract = append(extract, i)
+			}
+		}
+
 		opts := dl.Options{
 			Name:        fmt.Sprintf("[%d]", i),
 			URL:         src,
@@ -82,10 +106,98 @@ func (s *LocalSourceDownloader) DownloadSources(
 			return err
 		}
 
-		_, err = dl.Download(ctx, opts)
+		res, err := dl.Download(ctx, opts)
 		if err != nil {
 			return err
 		}
+		if res.Type == dl.TypeFile {
+			names[i] = res.Name
+		}
+	}
+
+	if len(signatures) == 0 {
+		return nil
+	}
+
+	srcDir := commonbuild.GetSrcDir(s.cfg, basePkg)
+	if err := s.verifySignatures(input, srcDir, validKeys, signatures, names); err != nil {
+		return err
+	}
+
+	for _, i := range extract {
+		if _, err := dl.Extract(filepath.Join(srcDir, names[i]), srcDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// signedSources maps the indices of the sources that have a detached
+// signature among the sources to the indices of their signatures.
+func signedSources(sources []string) map[int]int {
+	files := map[string]int{}
+	for i, src := range sources {
+		if name, ok := dl.SourceFileName(src); ok {
+			files[name] = i
+		}
+	}
+
+	signatures := map[int]int{}
+	for i, src := range sources {
+		name, ok := dl.SourceFileName(src)
+		if !ok || !srcsig.IsSignature(name) {
+			continue
+		}
+		if file, ok := files[srcsig.SignedFile(name)]; ok {
+			signatures[file] = i
+		}
+	}
+	return signatures
+}
+
+// withoutExtraction turns off the extraction of the archive at src,
+// which also keeps it apart from extracted copies in the cache. It
+// tells if the archive should be extracted after its signature is
+// checked.
+func withoutExtraction(src string) (string, bool) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return src, false
+	}
+	query := u.Query()
+	if query.Get("~archive") == "false" {
+		return src, false
+	}
+	query.Set("~archive", "false")
+	u.RawQuery = query.Encode()
+	return u.String(), true
+}
+
+func (s *LocalSourceDownloader) verifySignatures(
+	input *commonbuild.BuildInput,
+	srcDir string,
+	validKeys []string,
+	signatures map[int]int,
+	names []string,
+) error {
+	keyring, err := srcsig.LoadKeyring(srcsig.FindKeyring(commonbuild.GetScriptDir(input.Script)))
+	if err != nil {
+		return err
+	}
+	verifier := srcsig.NewVerifier(keyring, validKeys)
+
+	for _, file := range slices.Sorted(maps.Keys(signatures)) {
+		sig := signatures[file]
+		if names[file] == "" || names[sig] == "" {
+			return errors.New(gotext.Get("Source [%d] with a signature is not a file", file))
+		}
+
+		fingerprint, err := verifier.Verify(filepath.Join(srcDir, names[file]), filepath.Join(srcDir, names[sig]))
+		if err != nil {
+			return errors.New(gotext.Get("Verifying the signature of %s: %s", names[file], err))
+		}
+		s.out.Info("%s", gotext.Get("Signature of %s is good, signed by %s", names[file], fingerprint))
 	}
 
 	return nil
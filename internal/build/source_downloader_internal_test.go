@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignedSources(t *testing.T) {
+	sources := []string{
+		"https://example.com/foo-1.0.tar.gz",
+		"https://example.com/foo-1.0.tar.gz.asc",
+		"https://example.com/download?id=2&~name=bar-2.0.zip",
+		"https://example.com/bar-2.0.zip.sig",
+		"git+https://example.com/baz.git",
+		"https://example.com/baz.git.sig",
+		"https://example.com/orphan.sig",
+	}
+
+	assert.Equal(t, map[int]int{0: 1, 2: 3}, signedSources(sources))
+}
+
+func TestWithoutExtraction(t *testing.T) {
+	src, extract := withoutExtraction("https://example.com/foo-1.0.tar.gz")
+	assert.Equal(t, "https://example.com/foo-1.0.tar.gz?~archive=false", src)
+	assert.True(t, extract)
+
+	src, extract = withoutExtraction("https://example.com/foo-1.0.tar.gz?~archive=false")
+	assert.Equal(t, "https://example.com/foo-1.0.tar.gz?~archive=false", src)
+	assert.False(t, extract)
+}
@@ -19,6 +19,7 @@
 package build
 
 import (
+	"go.stplr.dev/stplr/internal/buildlog"
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/pkg/staplerfile"
 )
@@ -40,6 +41,12 @@ type BuildState struct {
 	RepoDeps []string
 
 	ShouldExit bool
+
+	// Plan is set for dry runs.
+	Plan *Plan
+
+	Log     *buildlog.Entry
+	Timings []buildlog.StepTiming
 }
 
 type flatVars struct {
@@ -48,6 +55,7 @@ type flatVars struct {
 	Depends      []string
 	Sources      []string
 	Checksums    []string
+	ValidPGPKeys []string
 }
 
 func NewBuildState() *BuildState {
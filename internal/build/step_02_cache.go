@@ -40,19 +40,26 @@ func (s *checkCacheStep) Name() string {
 func (s *checkCacheStep) Run(ctx context.Context, state *BuildState) error {
 	if !state.Input.Opts.Clean {
 		var remaining []*staplerfile.Package
+		// debug info packages go last, like the ones that are built
+		var debugDeps []*commonbuild.BuiltDep
 		for _, pkg := range state.Packages {
-			builtPkgPath, ok, err := s.cacheExecutor.CheckForBuiltPackage(ctx, state.Input, pkg)
+			deps, ok, err := s.cacheExecutor.CheckForBuiltPackage(ctx, state.Input, pkg)
 			if err != nil {
 				return err
 			}
 			if ok {
-				state.BuiltDeps = append(state.BuiltDeps, &commonbuild.BuiltDep{
-					Path: builtPkgPath,
+				state.Plan.addCached(PlannedPackage{
+					Repository: state.Repository,
+					Name:       pkg.Name,
+					Path:       deps[0].Path,
 				})
+				state.BuiltDeps = append(state.BuiltDeps, deps[0])
+				debugDeps = append(debugDeps, deps[1:]...)
 			} else {
 				remaining = append(remaining, pkg)
 			}
 		}
+		state.BuiltDeps = append(state.BuiltDeps, debugDeps...)
 
 		if len(remaining) == 0 {
 			state.ShouldExit = true
@@ -37,11 +37,11 @@ type MockCacheExecutor struct {
 	mock.Mock
 }
 
-func (m *MockCacheExecutor) CheckForBuiltPackage(ctx context.Context, input build.CheckForBuiltPackageInput, pkg *staplerfile.Package) (string, bool, error) {
+func (m *MockCacheExecutor) CheckForBuiltPackage(ctx context.Context, input build.CheckForBuiltPackageInput, pkg *staplerfile.Package) ([]*commonbuild.BuiltDep, bool, error) {
 	args := m.Called(ctx, input, pkg)
-	path, _ := args.Get(0).(string)
+	deps, _ := args.Get(0).([]*commonbuild.BuiltDep)
 	ok, _ := args.Get(1).(bool)
-	return path, ok, args.Error(2)
+	return deps, ok, args.Error(2)
 }
 
 func TestCheckCacheStep(t *testing.T) {
@@ -61,6 +61,9 @@ func TestCheckCacheStep(t *testing.T) {
 		expectedBuiltDeps []string
 		expectExit        bool
 		expectErr         bool
+		// debugPaths are the paths of the cached
+		// debug info packages by package name
+		debugPaths map[string]string
 	}{
 		{
 			name:  "All packages found in cache",
@@ -82,6 +85,17 @@ func TestCheckCacheStep(t *testing.T) {
 			expectedBuiltDeps: []string{"/path/to/pkg1"},
 			expectExit:        false,
 		},
+		{
+			name:  "Debug info packages found in cache",
+			clean: false,
+			cacheResults: []cacheResult{
+				{&staplerfile.Package{Name: "pkg1"}, "/path/to/pkg1", true, nil},
+				{&staplerfile.Package{Name: "pkg2"}, "/path/to/pkg2", true, nil},
+			},
+			debugPaths:        map[string]string{"pkg1": "/path/to/pkg1-debuginfo"},
+			expectedBuiltDeps: []string{"/path/to/pkg1", "/path/to/pkg2", "/path/to/pkg1-debuginfo"},
+			expectExit:        true,
+		},
 		{
 			name:  "Cache check error",
 			clean: false,
@@ -110,7 +124,14 @@ func TestCheckCacheStep(t *testing.T) {
 			for _, res := range tt.cacheResults {
 				packages = append(packages, res.pkg)
 				if !tt.clean {
-					mockCache.On("CheckForBuiltPackage", ctx, mock.Anything, res.pkg).Return(res.path, res.found, res.err)
+					var deps []*commonbuild.BuiltDep
+					if res.found {
+						deps = append(deps, &commonbuild.BuiltDep{Name: res.pkg.Name, Path: res.path})
+						if path, ok := tt.debugPaths[res.pkg.Name]; ok {
+							deps = append(deps, &commonbuild.BuiltDep{Name: res.pkg.Name + "-debuginfo", Path: path, DebugInfo: true})
+						}
+					}
+					mockCache.On("CheckForBuiltPackage", ctx, mock.Anything, res.pkg).Return(deps, res.found, res.err)
 				}
 			}
 
@@ -135,7 +156,7 @@ func TestCheckCacheStep(t *testing.T) {
 			for _, dep := range state.BuiltDeps {
 				builtPaths = append(builtPaths, dep.Path)
 			}
-			assert.ElementsMatch(t, tt.expectedBuiltDeps, builtPaths)
+			assert.Equal(t, tt.expectedBuiltDeps, builtPaths)
 		})
 	}
 }
@@ -49,3 +49,7 @@ func (s *scriptViewStep) Run(ctx context.Context, state *BuildState) error {
 		state.BasePackage,
 	)
 }
+
+func (s *scriptViewStep) DryRun(ctx context.Context, state *BuildState) error {
+	return nil
+}
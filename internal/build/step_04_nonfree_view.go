@@ -145,3 +145,7 @@ func (s *nonfreeViewStep) Run(ctx context.Context, state *BuildState) error {
 	}
 	return nil
 }
+
+func (s *nonfreeViewStep) DryRun(ctx context.Context, state *BuildState) error {
+	return nil
+}
@@ -55,3 +55,20 @@ func (s *checksStep) Run(ctx context.Context, state *BuildState) error {
 	}
 	return nil
 }
+
+func (s *checksStep) DryRun(ctx context.Context, state *BuildState) error {
+	for _, pkg := range state.Packages {
+		failed, err := s.e.FailedChecks(ctx, pkg, state.Input)
+		if err != nil {
+			return fmt.Errorf("FailedChecks failed: %w", err)
+		}
+		for _, msg := range failed {
+			state.Plan.addFailedCheck(PlannedCheck{
+				Repository: state.Repository,
+				Name:       pkg.Name,
+				Message:    msg,
+			})
+		}
+	}
+	return nil
+}
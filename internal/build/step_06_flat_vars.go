@@ -39,12 +39,14 @@ func (s *flatVarsStep) Run(ctx context.Context, state *BuildState) error {
 	depends := []string{}
 	sources := []string{}
 	checksums := []string{}
+	validPGPKeys := []string{}
 	for _, pkg := range state.Packages {
 		buildDepends = append(buildDepends, pkg.BuildDepends.Resolved()...)
 		optDepends = append(optDepends, pkg.OptDepends.Resolved()...)
 		depends = append(depends, pkg.Depends.Resolved()...)
 		sources = append(sources, pkg.Sources.Resolved()...)
 		checksums = append(checksums, pkg.Checksums.Resolved()...)
+		validPGPKeys = append(validPGPKeys, pkg.ValidPGPKeys.Resolved()...)
 	}
 	if len(sources) != len(checksums) {
 		return errors.New(gotext.Get("The checksums array must be the same length as sources"))
@@ -53,6 +55,7 @@ func (s *flatVarsStep) Run(ctx context.Context, state *BuildState) error {
 
 	state.FlatVars.Sources = sources
 	state.FlatVars.Checksums = checksums
+	state.FlatVars.ValidPGPKeys = removeDuplicates(validPGPKeys)
 	state.FlatVars.BuildDepends = removeDuplicates(buildDepends)
 	state.FlatVars.OptDepends = removeDuplicates(optDepends)
 	state.FlatVars.Depends = removeDuplicates(depends)
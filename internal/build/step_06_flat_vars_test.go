@@ -51,6 +51,9 @@ func TestFlatVarsStepRunSuccess(t *testing.T) {
 			Checksums: staplerfile.OverridableFromMap(map[string][]string{
 				"": {"abc123", "def456"},
 			}),
+			ValidPGPKeys: staplerfile.OverridableFromMap(map[string][]string{
+				"": {"A2C0C1F83C5217E2A9B47D5E6C319B0AF0D17E44"},
+			}),
 		},
 		{
 			BuildDepends: staplerfile.OverridableFromMap(map[string][]string{
@@ -68,6 +71,9 @@ func TestFlatVarsStepRunSuccess(t *testing.T) {
 			Checksums: staplerfile.OverridableFromMap(map[string][]string{
 				"": {"def456"}, // duplicate
 			}),
+			ValidPGPKeys: staplerfile.OverridableFromMap(map[string][]string{
+				"": {"A2C0C1F83C5217E2A9B47D5E6C319B0AF0D17E44"}, // duplicate
+			}),
 		},
 	}
 
@@ -86,6 +92,7 @@ func TestFlatVarsStepRunSuccess(t *testing.T) {
 	assert.ElementsMatch(t, []string{"make", "gcc"}, state.FlatVars.BuildDepends)
 	assert.ElementsMatch(t, []string{"graphviz", "dot"}, state.FlatVars.OptDepends)
 	assert.ElementsMatch(t, []string{"libc"}, state.FlatVars.Depends)
+	assert.Equal(t, []string{"A2C0C1F83C5217E2A9B47D5E6C319B0AF0D17E44"}, state.FlatVars.ValidPGPKeys)
 }
 
 func TestFlatVarsStepRunChecksumMismatch(t *testing.T) {
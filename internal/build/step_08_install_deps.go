@@ -22,10 +22,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"go.stplr.dev/stplr/internal/cliprompts"
 	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/depver"
 	"go.stplr.dev/stplr/internal/installer"
 )
 
@@ -52,18 +54,45 @@ func (s *installDepsStep) Name() string {
 }
 
 func (s *installDepsStep) Run(ctx context.Context, state *BuildState) error {
-	slog.Debug("installBuildDeps")
-	alrBuildDeps, installedBuildDeps, err := s.installBuildDeps(ctx, state.Input, state.FlatVars.BuildDepends)
+	if state.Input.BuildOpts().CleanRootImage != "" {
+		return s.runCleanRoot(ctx, state)
+	}
+	if state.Input.BuildOpts().CrossTarget {
+		return s.runCrossTarget(ctx, state)
+	}
+
+	buildDeps, err := s.installerExecutor.RemoveAlreadyInstalled(ctx, state.FlatVars.BuildDepends)
 	if err != nil {
 		return err
 	}
 
-	slog.Debug("installOptDeps")
-	_, err = s.installOptDeps(ctx, state.Input, state.FlatVars.OptDepends)
+	if jobsLimit(state.Input) > 1 {
+		// Build the whole dependency graph at once, so that build and
+		// runtime dependencies that don't depend on each other are built
+		// in parallel. The calls below get the results from the builder.
+		slog.Debug("BuildALRDeps for the whole graph")
+		_, _, err := s.builder.BuildALRDeps(ctx, state.Input, append(slices.Clone(buildDeps), state.FlatVars.Depends...))
+		if err != nil {
+			return err
+		}
+	}
+
+	slog.Debug("installBuildDeps")
+	alrBuildDeps, err := s.installBuildDeps(ctx, state.Input, buildDeps)
 	if err != nil {
 		return err
 	}
 
+	// Optional dependencies are chosen interactively, so a dry run can't
+	// know them in advance.
+	if state.Plan == nil {
+		slog.Debug("installOptDeps")
+		_, err = s.installOptDeps(ctx, state.Input, state.FlatVars.OptDepends)
+		if err != nil {
+			return err
+		}
+	}
+
 	depNames := make(map[string]struct{})
 	for _, dep := range alrBuildDeps {
 		depNames[dep.Name] = struct{}{}
@@ -72,7 +101,7 @@ func (s *installDepsStep) Run(ctx context.Context, state *BuildState) error {
 	// We filter so as not to re-build what has already been built at the `installBuildDeps` stage.
 	var filteredDepends []string
 	for _, d := range state.FlatVars.Depends {
-		if _, found := depNames[d]; !found {
+		if _, found := depNames[depver.Parse(d).Name]; !found {
 			filteredDepends = append(filteredDepends, d)
 		}
 	}
@@ -83,7 +112,65 @@ func (s *installDepsStep) Run(ctx context.Context, state *BuildState) error {
 		return err
 	}
 
-	state.InstalledBuildDeps = installedBuildDeps
+	state.InstalledBuildDeps = buildDeps
+	state.RepoDeps = repoDeps
+	state.BuiltDeps = append(state.BuiltDeps, newBuiltDeps...)
+
+	return nil
+}
+
+// runCleanRoot leaves the host system alone: build dependencies are
+// only built if needed and then installed into the clean rootfs by
+// the script executor.
+func (s *installDepsStep) runCleanRoot(ctx context.Context, state *BuildState) error {
+	builtBuildDeps, repoBuildDeps, err := s.builder.BuildALRDeps(ctx, state.Input, state.FlatVars.BuildDepends)
+	if err != nil {
+		return err
+	}
+	state.Input.CleanRootDepends = repoBuildDeps
+	state.Input.CleanRootPackages = GetBuiltPaths(builtBuildDeps)
+
+	newBuiltDeps, repoDeps, err := s.builder.BuildALRDeps(ctx, state.Input, state.FlatVars.Depends)
+	if err != nil {
+		return err
+	}
+
+	state.RepoDeps = repoDeps
+	state.BuiltDeps = append(state.BuiltDeps, newBuiltDeps...)
+
+	return nil
+}
+
+// runCrossTarget installs the build dependencies resolved for the host,
+// building the Stapler ones in the host format. Runtime dependencies
+// are built for the target and optional ones are skipped, as they
+// don't belong to the host.
+func (s *installDepsStep) runCrossTarget(ctx context.Context, state *BuildState) error {
+	host, err := hostArgs(ctx, state.Input)
+	if err != nil {
+		return err
+	}
+
+	deps, err := hostBuildDepends(state.Packages, host.Info)
+	if err != nil {
+		return err
+	}
+	buildDeps, err := s.installerExecutor.RemoveAlreadyInstalled(ctx, deps)
+	if err != nil {
+		return err
+	}
+
+	slog.Debug("installBuildDeps", "host", host.Info.ID, "format", host.PkgFormat())
+	if _, err := s.installBuildDeps(ctx, host, buildDeps); err != nil {
+		return err
+	}
+
+	newBuiltDeps, repoDeps, err := s.builder.BuildALRDeps(ctx, state.Input, state.FlatVars.Depends)
+	if err != nil {
+		return err
+	}
+
+	state.InstalledBuildDeps = buildDeps
 	state.RepoDeps = repoDeps
 	state.BuiltDeps = append(state.BuiltDeps, newBuiltDeps...)
 
@@ -96,22 +183,11 @@ type InstallInput interface {
 	commonbuild.PkgFormatProvider
 }
 
-func (s *installDepsStep) installBuildDeps(ctx context.Context, input InstallInput, pkgs []string) ([]*commonbuild.BuiltDep, []string, error) {
-	var builtDeps []*commonbuild.BuiltDep
-	var deps []string
-	var err error
-	if len(pkgs) > 0 {
-		deps, err = s.installerExecutor.RemoveAlreadyInstalled(ctx, pkgs)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		builtDeps, err = s.installPkgs(ctx, input, deps)
-		if err != nil {
-			return nil, nil, err
-		}
+func (s *installDepsStep) installBuildDeps(ctx context.Context, input InstallInput, deps []string) ([]*commonbuild.BuiltDep, error) {
+	if len(deps) == 0 {
+		return nil, nil
 	}
-	return builtDeps, deps, nil
+	return s.installPkgs(ctx, input, deps)
 }
 
 func splitPkgAndDesc(pkgs []string) (names []string, mapping map[string]string) {
@@ -153,12 +229,14 @@ func (i *installDepsStep) installOptDeps(ctx context.Context, input InstallInput
 		}
 	}
 
+	i.builder.promptMu.Lock()
 	optDeps, err = cliprompts.ChooseOptDepends(
 		ctx,
 		optDepsWithDesc,
 		"install",
 		input.BuildOpts().Interactive,
 	)
+	i.builder.promptMu.Unlock()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.stplr.dev/stplr/internal/buildlog"
+)
+
+type openBuildLogStep struct {
+	logs *buildlog.Store
+}
+
+func OpenBuildLogStep(logs *buildlog.Store) *openBuildLogStep {
+	return &openBuildLogStep{logs: logs}
+}
+
+func (s *openBuildLogStep) Name() string {
+	return "open build log"
+}
+
+func (s *openBuildLogStep) Run(ctx context.Context, state *BuildState) error {
+	if s.logs == nil {
+		return nil
+	}
+
+	entry, err := s.logs.Create(state.Repository, state.BasePackage, state.Version, time.Now())
+	if err != nil {
+		// The build itself doesn't depend on the log
+		slog.Warn("failed to create build log", "err", err)
+		return nil
+	}
+
+	state.Log = entry
+	state.Input.LogPath = entry.OutputPath()
+
+	return nil
+}
+
+func (s *openBuildLogStep) DryRun(ctx context.Context, state *BuildState) error {
+	return nil
+}
@@ -65,8 +65,9 @@ func (b *prepareStep) Run(ctx context.Context, state *BuildState) error {
 		state.BasePackage,
 		state.Version,
 		SourcesInput{
-			Sources:   state.FlatVars.Sources,
-			Checksums: state.FlatVars.Checksums,
+			Sources:      state.FlatVars.Sources,
+			Checksums:    state.FlatVars.Checksums,
+			ValidPGPKeys: state.FlatVars.ValidPGPKeys,
 		},
 	)
 	if err != nil {
@@ -75,3 +76,7 @@ func (b *prepareStep) Run(ctx context.Context, state *BuildState) error {
 
 	return nil
 }
+
+func (b *prepareStep) DryRun(ctx context.Context, state *BuildState) error {
+	return nil
+}
@@ -20,16 +20,22 @@ package build
 
 import (
 	"context"
+	"fmt"
+	"os"
 
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/internal/sandboxpolicy"
 	"go.stplr.dev/stplr/internal/scripter"
 )
 
 type buildPackagesStep struct {
 	scriptExecutor scripter.ScriptExecutor
+	cfg            commonbuild.Config
 }
 
-func BuildPackagesStep(scriptExecutor scripter.ScriptExecutor) *buildPackagesStep {
-	return &buildPackagesStep{scriptExecutor: scriptExecutor}
+func BuildPackagesStep(scriptExecutor scripter.ScriptExecutor, cfg commonbuild.Config) *buildPackagesStep {
+	return &buildPackagesStep{scriptExecutor: scriptExecutor, cfg: cfg}
 }
 
 func (s *buildPackagesStep) Name() string {
@@ -37,6 +43,30 @@ func (s *buildPackagesStep) Name() string {
 }
 
 func (s *buildPackagesStep) Run(ctx context.Context, state *BuildState) error {
+	passphrase, err := pkgsig.Passphrase(s.cfg.Signing().For(state.Input.PkgFormat()))
+	if err != nil {
+		return fmt.Errorf("failed to read signing key passphrase: %w", err)
+	}
+	state.Input.SigningPassphrase = passphrase
+
+	if state.Input.BuildOpts().SBOM || s.cfg.SBOM() {
+		passphrase, err := pkgsig.Passphrase(s.cfg.Signing().Provenance)
+		if err != nil {
+			return fmt.Errorf("failed to read provenance key passphrase: %w", err)
+		}
+		state.Input.ProvenancePassphrase = passphrase
+	}
+
+	policy, err := sandboxpolicy.New(s.cfg.SandboxBinds(), s.cfg.SandboxEnvPassthrough())
+	if err != nil {
+		return err
+	}
+	fullNames := make([]string, 0, len(state.Packages))
+	for _, pkg := range state.Packages {
+		fullNames = append(fullNames, state.Repository+"/"+pkg.Name)
+	}
+	state.Input.SandboxEnv = policy.Environ(fullNames, os.Environ())
+
 	res, err := s.scriptExecutor.ExecuteSecondPass(
 		ctx,
 		state.Input,
@@ -54,3 +84,21 @@ func (s *buildPackagesStep) Run(ctx context.Context, state *BuildState) error {
 
 	return nil
 }
+
+func (s *buildPackagesStep) DryRun(ctx context.Context, state *BuildState) error {
+	names := make([]string, 0, len(state.Packages))
+	for _, pkg := range state.Packages {
+		names = append(names, pkg.Name)
+		state.BuiltDeps = append(state.BuiltDeps, &commonbuild.BuiltDep{Name: pkg.Name})
+	}
+
+	state.Plan.addBuild(PlannedBuild{
+		Repository:  state.Repository,
+		BasePackage: state.BasePackage,
+		Version:     state.Version,
+		Packages:    names,
+		Sources:     state.FlatVars.Sources,
+	})
+
+	return nil
+}
@@ -20,22 +20,18 @@ package build
 
 import (
 	"context"
-
-	"github.com/leonelquinteros/gotext"
-
-	"go.stplr.dev/stplr/internal/cliprompts"
-	"go.stplr.dev/stplr/internal/commonbuild"
-	"go.stplr.dev/stplr/internal/installer"
-	"go.stplr.dev/stplr/internal/manager"
 )
 
 type postStep struct {
-	installerExecutor installer.InstallerExecutor
-	downloader        SourceDownloaderExecutor
+	downloader SourceDownloaderExecutor
+	builder    *Builder
 }
 
-func PostStep(installerExecutor installer.InstallerExecutor, downloader SourceDownloaderExecutor) *postStep {
-	return &postStep{installerExecutor, downloader}
+func PostStep(
+	downloader SourceDownloaderExecutor,
+	builder *Builder,
+) *postStep {
+	return &postStep{downloader, builder}
 }
 
 func (s *postStep) Name() string {
@@ -43,41 +39,19 @@ func (s *postStep) Name() string {
 }
 
 func (s *postStep) Run(ctx context.Context, state *BuildState) error {
-	err := s.removeOldCache(ctx, state)
+	err := s.downloader.RemoveOldSourcesFromCache(ctx, state.Input.Repository(), state.BasePackage, state.Version)
 	if err != nil {
 		return err
 	}
-	err = s.removeBuildDeps(ctx, state.Input, state.InstalledBuildDeps)
-	return err
-}
-
-func (s *postStep) removeOldCache(ctx context.Context, state *BuildState) error {
-	err := s.downloader.RemoveOldSourcesFromCache(ctx, state.Input.Repository(), state.BasePackage, state.Version)
-	return err
+	// other builds of this run may still need the build dependencies
+	// and the caches, so the builder removes the build dependencies and
+	// prunes the caches once the whole run is done
+	s.builder.deferBuildDeps(state.InstalledBuildDeps)
+	s.builder.keepBaseDir(state.BasePackage)
+	return nil
 }
 
-func (s *postStep) removeBuildDeps(ctx context.Context, input interface {
-	commonbuild.BuildOptsProvider
-}, deps []string,
-) error {
-	if len(deps) > 0 {
-		remove, err := cliprompts.YesNoPrompt(ctx, gotext.Get("Would you like to remove the build dependencies?"), input.BuildOpts().Interactive, false)
-		if err != nil {
-			return err
-		}
-
-		if remove {
-			err = s.installerExecutor.Remove(
-				ctx,
-				deps,
-				&manager.Opts{
-					NoConfirm: !input.BuildOpts().Interactive,
-				},
-			)
-			if err != nil {
-				return err
-			}
-		}
-	}
+func (s *postStep) DryRun(ctx context.Context, state *BuildState) error {
+	state.Plan.addRemoveBuildDeps(state.InstalledBuildDeps)
 	return nil
 }
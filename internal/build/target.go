@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"context"
+	"errors"
+
+	"go.stplr.dev/stplr/internal/manager"
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// distroFormats maps distro IDs to their native package format.
+// ID_LIKE is checked too, so only base distros are listed.
+var distroFormats = []struct {
+	id     string
+	format string
+}{
+	{"debian", "deb"},
+	{"ubuntu", "deb"},
+	{"fedora", "rpm"},
+	{"rhel", "rpm"},
+	{"altlinux", "rpm"},
+	{"suse", "rpm"},
+	{"opensuse", "rpm"},
+	{"alpine", "apk"},
+	{"arch", "archlinux"},
+}
+
+// FormatForDistro returns the native package format of info
+// or "" if it is unknown.
+func FormatForDistro(info *distro.OSRelease) string {
+	for _, f := range distroFormats {
+		if distro.IsIdEqualOrLike(info, f.id) {
+			return f.format
+		}
+	}
+	return ""
+}
+
+// hostArgs returns the arguments for building the packages that are
+// installed on the host during a cross-target build.
+func hostArgs(ctx context.Context, input InstallInput) (*BuildArgs, error) {
+	info, err := distro.ParseOSRelease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mgr := manager.Detect()
+	if mgr == nil {
+		return nil, errors.New("unable to detect a supported package manager on the system")
+	}
+
+	opts := *input.BuildOpts()
+	opts.CrossTarget = false
+
+	return &BuildArgs{
+		Opts:       &opts,
+		Info:       info,
+		PkgFormat_: mgr.Format(),
+	}, nil
+}
+
+// hostBuildDepends resolves the build dependencies of pkgs for the host
+// instead of the target.
+func hostBuildDepends(pkgs []*staplerfile.Package, info *distro.OSRelease) ([]string, error) {
+	r := staplerfile.NewResolver(info)
+	if err := r.Init(); err != nil {
+		return nil, err
+	}
+
+	var deps []string
+	for _, pkg := range pkgs {
+		f := pkg.BuildDepends
+		f.Resolve(r.Names())
+		deps = append(deps, f.Resolved()...)
+	}
+	return removeDuplicates(deps), nil
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+func TestFormatForDistro(t *testing.T) {
+	assert.Equal(t, "deb", FormatForDistro(&distro.OSRelease{ID: "ubuntu"}))
+	assert.Equal(t, "rpm", FormatForDistro(&distro.OSRelease{ID: "rocky", Like: []string{"rhel", "centos", "fedora"}}))
+	assert.Equal(t, "apk", FormatForDistro(&distro.OSRelease{ID: "alpine"}))
+	assert.Equal(t, "archlinux", FormatForDistro(&distro.OSRelease{ID: "manjaro", Like: []string{"arch"}}))
+	assert.Empty(t, FormatForDistro(&distro.OSRelease{ID: "unknown"}))
+}
+
+func TestHostBuildDepends(t *testing.T) {
+	pkg := &staplerfile.Package{
+		BuildDepends: staplerfile.OverridableFromMap(map[string][]string{
+			"":       {"gcc"},
+			"debian": {"build-essential"},
+		}),
+	}
+	// resolved for the target
+	pkg.BuildDepends.SetResolved([]string{"build-essential"})
+
+	deps, err := hostBuildDepends([]*staplerfile.Package{pkg}, &distro.OSRelease{ID: "fedora"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gcc"}, deps)
+	assert.Equal(t, []string{"build-essential"}, pkg.BuildDepends.Resolved())
+}
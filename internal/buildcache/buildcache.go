@@ -0,0 +1,381 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package buildcache keys built packages by the inputs of their build.
+// A manifest with the hashes of the inputs is stored next to every
+// built package, and a cached package is only reused if the manifest
+// of the current inputs matches it.
+package buildcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// manifestVersion is bumped when the way the inputs are hashed changes,
+// so that older manifests don't match.
+const manifestVersion = 3
+
+const manifestSuffix = ".cache.json"
+
+// Input names, in the order they are reported by [Manifest.Changed].
+const (
+	InputScript  = "script"
+	InputSources = "sources"
+	InputVars    = "vars"
+	InputOptions = "options"
+)
+
+type Manifest struct {
+	Version int    `json:"version"`
+	Key     string `json:"key"`
+	// Package and PkgVersion tell what was built,
+	// they are not a part of the key.
+	Package    string            `json:"package"`
+	PkgVersion string            `json:"pkg_version"`
+	Inputs     map[string]string `json:"inputs"`
+	// DebugInfo tells if a debug info package was built along with
+	// the package, it is reused only together with it.
+	DebugInfo bool `json:"debug_info,omitempty"`
+}
+
+type Input interface {
+	commonbuild.OSReleaser
+	commonbuild.PkgFormatter
+	commonbuild.RepositoryGetter
+	commonbuild.BuildOptsProvider
+	ScriptPath() string
+}
+
+// New returns the manifest of building pkg from the script of input.
+func New(cfg commonbuild.Config, input Input, pkg *staplerfile.Package) (*Manifest, error) {
+	script, err := hashScript(input.ScriptPath(), pkg)
+	if err != nil {
+		return nil, fmt.Errorf("hashing script: %w", err)
+	}
+	sources, err := hashJSON(map[string]any{
+		"sources":   pkg.Sources.Resolved(),
+		"checksums": pkg.Checksums.Resolved(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	vars, err := hashVars(pkg)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := buildOptions(cfg, input, pkg)
+	if err != nil {
+		return nil, err
+	}
+	options, err := hashJSON(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Version:    manifestVersion,
+		Package:    pkg.Name,
+		PkgVersion: fmt.Sprintf("%d:%s-%d", pkg.Epoch, pkg.Version, pkg.Release),
+		Inputs: map[string]string{
+			InputScript:  script,
+			InputSources: sources,
+			InputVars:    vars,
+			InputOptions: options,
+		},
+	}
+
+	h := sha256.New()
+	for _, name := range inputNames {
+		fmt.Fprintf(h, "%s=%s\n", name, m.Inputs[name])
+	}
+	m.Key = hex.EncodeToString(h.Sum(nil))
+	return m, nil
+}
+
+var inputNames = []string{InputScript, InputSources, InputVars, InputOptions}
+
+// Changed returns the names of the inputs that differ between m and
+// cached. All inputs are reported if the manifests are of different
+// versions.
+func (m *Manifest) Changed(cached *Manifest) []string {
+	if cached.Version != m.Version {
+		return slices.Clone(inputNames)
+	}
+	var changed []string
+	for _, name := range inputNames {
+		if m.Inputs[name] != cached.Inputs[name] {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// Path returns the path of the manifest of the package at pkgPath.
+func Path(pkgPath string) string {
+	return pkgPath + manifestSuffix
+}
+
+// Read reads the manifest of the package at pkgPath. The error
+// satisfies errors.Is(err, os.ErrNotExist) if there is none.
+func Read(pkgPath string) (*Manifest, error) {
+	data, err := os.ReadFile(Path(pkgPath))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(pkgPath), err)
+	}
+	return &m, nil
+}
+
+// Write stores m as the manifest of the package at pkgPath.
+func (m *Manifest) Write(pkgPath string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(Path(pkgPath), append(data, '\n'), 0o644)
+}
+
+// hashScript hashes the script and the files next to it that pkg takes
+// from there. Other files, such as the rest of a checkout the script
+// is in, don't change what is built and are left out.
+func hashScript(script string, pkg *staplerfile.Package) (string, error) {
+	h := sha256.New()
+	if err := hashFile(h, script); err != nil {
+		return "", err
+	}
+	files, err := pkg.LocalFiles()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Dir(script)
+	for _, rel := range files {
+		fmt.Fprintf(h, "%s\n", filepath.ToSlash(rel))
+		if err := writePath(h, filepath.Join(dir, rel)); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashTree writes the relative paths and the contents of the files in
+// dir to w, except for hidden files and directories. Symlinks to files
+// are hashed as the files they point to.
+func hashTree(w io.Writer, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dir {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			fi, err := os.Stat(path)
+			if err != nil || !fi.Mode().IsRegular() {
+				return nil
+			}
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\n", filepath.ToSlash(rel))
+		return hashFile(w, path)
+	})
+}
+
+// hashPath hashes the file or the directory tree at path.
+func hashPath(path string) (string, error) {
+	h := sha256.New()
+	if err := writePath(h, path); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writePath writes the file or the directory tree at path to w.
+func writePath(w io.Writer, path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return hashTree(w, path)
+	}
+	return hashFile(w, path)
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// hashVars hashes the resolved variables of pkg, except for the
+// sources, which are hashed on their own.
+func hashVars(pkg *staplerfile.Package) (string, error) {
+	data, err := json.Marshal(staplerfile.PackageToResolved(pkg))
+	if err != nil {
+		return "", err
+	}
+	var vars map[string]any
+	if err := json.Unmarshal(data, &vars); err != nil {
+		return "", err
+	}
+	delete(vars, "sources")
+	delete(vars, "checksums")
+	return hashJSON(vars)
+}
+
+// buildOptions returns the settings that change what a build produces.
+// Settings that only change how the build runs, such as the log path
+// or the signing passphrases, are left out. The signing keys are only
+// told by their fingerprints.
+func buildOptions(cfg commonbuild.Config, input Input, pkg *staplerfile.Package) (map[string]any, error) {
+	opts := input.BuildOpts()
+	reproducible := opts.Reproducible || cfg.Reproducible()
+	sbom := opts.SBOM || cfg.SBOM()
+
+	options := map[string]any{
+		"format":          input.PkgFormat(),
+		"repository":      input.Repository(),
+		"nosuffix":        opts.NoSuffix,
+		"nocheck":         opts.NoCheck || cfg.NoCheck(),
+		"reproducible":    reproducible,
+		"sbom":            sbom,
+		"firejail":        !opts.DisableFirejail,
+		"package_options": cfg.PackageOptions(),
+		"debuginfo":       cfg.DebugInfo(),
+		"build_cache":     cfg.BuildCache() || pkg.BuildCache.Resolved(),
+	}
+	if info := input.OSRelease(); info != nil {
+		options["distro"] = info.ID
+		options["distro_version"] = info.VersionID
+	}
+
+	signing := cfg.Signing()
+	signingKey, err := pkgsig.KeyID(signing.For(input.PkgFormat()), input.PkgFormat())
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	options["signing_key"] = signingKey
+	if sbom {
+		provenanceKey, err := pkgsig.KeyID(signing.Provenance, "")
+		if err != nil {
+			return nil, fmt.Errorf("reading provenance signing key: %w", err)
+		}
+		options["provenance_key"] = provenanceKey
+	}
+
+	if image := opts.CleanRootImage; image != "" {
+		digest, err := hashPath(image)
+		if err != nil {
+			return nil, fmt.Errorf("hashing clean root image: %w", err)
+		}
+		options["clean_root_image"] = image
+		options["clean_root_image_digest"] = digest
+	}
+	return options, nil
+}
+
+// hashJSON hashes the JSON encoding of v. Empty values are dropped
+// first, so that nil and empty lists hash the same.
+func hashJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return "", err
+	}
+	data, err = json.Marshal(dropEmpty(generic))
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func dropEmpty(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			val = dropEmpty(val)
+			if isEmpty(val) {
+				delete(v, k)
+			} else {
+				v[k] = val
+			}
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = dropEmpty(v[i])
+		}
+		return v
+	}
+	return v
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
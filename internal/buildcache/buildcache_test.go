@@ -0,0 +1,267 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buildcache_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/buildcache"
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type testConfig struct {
+	commonbuild.Config
+	packageOptions []string
+	signing        types.Signing
+	buildCache     bool
+}
+
+func (c *testConfig) NoCheck() bool            { return false }
+func (c *testConfig) Reproducible() bool       { return false }
+func (c *testConfig) SBOM() bool               { return false }
+func (c *testConfig) DebugInfo() bool          { return false }
+func (c *testConfig) PackageOptions() []string { return c.packageOptions }
+func (c *testConfig) Signing() types.Signing   { return c.signing }
+func (c *testConfig) BuildCache() bool         { return c.buildCache }
+
+func testInput(t *testing.T) *commonbuild.BuildInput {
+	t.Helper()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "Staplerfile")
+	require.NoError(t, os.WriteFile(script, []byte("name=foo\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fix.patch"), []byte("patch\n"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "patches"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "patches", "01-fix.patch"), []byte("patch\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "postinstall.sh"), []byte("true\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("readme\n"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref\n"), 0o644))
+	return &commonbuild.BuildInput{
+		Script:      script,
+		Opts:        &types.BuildOpts{},
+		Info_:       &distro.OSRelease{ID: "altlinux", VersionID: "11"},
+		PkgFormat_:  "rpm",
+		Repository_: "default",
+	}
+}
+
+func testPackage() *staplerfile.Package {
+	pkg := &staplerfile.Package{Name: "foo", Version: "1.0", Release: 1}
+	pkg.Sources.SetResolved([]string{"https://example.com/foo.tar.gz", "local:///fix.patch", "local:///patches/01-fix.patch?~name=fix2.patch"})
+	pkg.Checksums.SetResolved([]string{"sha256:abc", "SKIP", "SKIP"})
+	pkg.Scripts.SetResolved(staplerfile.Scripts{PostInstall: "postinstall.sh"})
+	pkg.Depends.SetResolved([]string{"bar"})
+	return pkg
+}
+
+func TestManifestChanged(t *testing.T) {
+	cfg := &testConfig{}
+	input := testInput(t)
+
+	cached, err := buildcache.New(cfg, input, testPackage())
+	require.NoError(t, err)
+
+	same, err := buildcache.New(cfg, input, testPackage())
+	require.NoError(t, err)
+	assert.Equal(t, cached.Key, same.Key)
+	assert.Empty(t, same.Changed(cached))
+
+	for _, tc := range []struct {
+		name    string
+		change  func(input *commonbuild.BuildInput, pkg *staplerfile.Package, cfg *testConfig)
+		changed []string
+	}{
+		{
+			name: "script",
+			change: func(input *commonbuild.BuildInput, _ *staplerfile.Package, _ *testConfig) {
+				require.NoError(t, os.WriteFile(input.Script, []byte("name=foo\nbuild() { :; }\n"), 0o644))
+			},
+			changed: []string{buildcache.InputScript},
+		},
+		{
+			name: "local source",
+			change: func(input *commonbuild.BuildInput, _ *staplerfile.Package, _ *testConfig) {
+				patch := filepath.Join(filepath.Dir(input.Script), "fix.patch")
+				require.NoError(t, os.WriteFile(patch, []byte("other\n"), 0o644))
+			},
+			changed: []string{buildcache.InputScript},
+		},
+		{
+			name: "local source in a subdirectory",
+			change: func(input *commonbuild.BuildInput, _ *staplerfile.Package, _ *testConfig) {
+				patch := filepath.Join(filepath.Dir(input.Script), "patches", "01-fix.patch")
+				require.NoError(t, os.WriteFile(patch, []byte("other\n"), 0o644))
+			},
+			changed: []string{buildcache.InputScript},
+		},
+		{
+			name: "install script",
+			change: func(input *commonbuild.BuildInput, _ *staplerfile.Package, _ *testConfig) {
+				script := filepath.Join(filepath.Dir(input.Script), "postinstall.sh")
+				require.NoError(t, os.WriteFile(script, []byte("false\n"), 0o644))
+			},
+			changed: []string{buildcache.InputScript},
+		},
+		{
+			name: "checksums",
+			change: func(_ *commonbuild.BuildInput, pkg *staplerfile.Package, _ *testConfig) {
+				pkg.Checksums.SetResolved([]string{"sha256:def", "SKIP", "SKIP"})
+			},
+			changed: []string{buildcache.InputSources},
+		},
+		{
+			name: "depends",
+			change: func(_ *commonbuild.BuildInput, pkg *staplerfile.Package, _ *testConfig) {
+				pkg.Depends.SetResolved([]string{"bar", "baz"})
+			},
+			changed: []string{buildcache.InputVars},
+		},
+		{
+			name: "target distro",
+			change: func(input *commonbuild.BuildInput, _ *staplerfile.Package, _ *testConfig) {
+				input.Info_ = &distro.OSRelease{ID: "fedora", VersionID: "40"}
+			},
+			changed: []string{buildcache.InputOptions},
+		},
+		{
+			name: "package options",
+			change: func(_ *commonbuild.BuildInput, _ *staplerfile.Package, cfg *testConfig) {
+				cfg.packageOptions = []string{"strip"}
+			},
+			changed: []string{buildcache.InputOptions},
+		},
+		{
+			name: "signing key",
+			change: func(_ *commonbuild.BuildInput, _ *staplerfile.Package, cfg *testConfig) {
+				cfg.signing.RPM.KeyFile = writeSigningKey(t)
+			},
+			changed: []string{buildcache.InputOptions},
+		},
+		{
+			name: "build cache",
+			change: func(_ *commonbuild.BuildInput, _ *staplerfile.Package, cfg *testConfig) {
+				cfg.buildCache = true
+			},
+			changed: []string{buildcache.InputOptions},
+		},
+		{
+			name: "clean root image",
+			change: func(input *commonbuild.BuildInput, _ *staplerfile.Package, _ *testConfig) {
+				image := filepath.Join(t.TempDir(), "base.tar")
+				require.NoError(t, os.WriteFile(image, []byte("image"), 0o644))
+				input.Opts.CleanRootImage = image
+			},
+			changed: []string{buildcache.InputOptions},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			input := testInput(t)
+			cfg := &testConfig{}
+			cached, err := buildcache.New(cfg, input, testPackage())
+			require.NoError(t, err)
+
+			pkg := testPackage()
+			tc.change(input, pkg, cfg)
+			current, err := buildcache.New(cfg, input, pkg)
+			require.NoError(t, err)
+			assert.NotEqual(t, cached.Key, current.Key)
+			assert.Equal(t, tc.changed, current.Changed(cached))
+		})
+	}
+}
+
+// writeSigningKey writes an OpenPGP private key and returns its path.
+func writeSigningKey(t *testing.T) string {
+	t.Helper()
+
+	e, err := openpgp.NewEntity("builder", "", "builder@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	path := filepath.Join(t.TempDir(), "builder.asc")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	return path
+}
+
+func TestManifestIgnoresUnusedFiles(t *testing.T) {
+	cfg := &testConfig{}
+	input := testInput(t)
+
+	a, err := buildcache.New(cfg, input, testPackage())
+	require.NoError(t, err)
+	dir := filepath.Dir(input.Script)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("other\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("other\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo.rpm"), []byte("package\n"), 0o644))
+	b, err := buildcache.New(cfg, input, testPackage())
+	require.NoError(t, err)
+	assert.Equal(t, a.Key, b.Key)
+}
+
+func TestManifestIgnoresEmptyValues(t *testing.T) {
+	cfg := &testConfig{}
+	input := testInput(t)
+
+	withNil := testPackage()
+	withEmpty := testPackage()
+	withEmpty.Provides = []string{}
+	withEmpty.OptDepends.SetResolved([]string{})
+
+	a, err := buildcache.New(cfg, input, withNil)
+	require.NoError(t, err)
+	b, err := buildcache.New(cfg, input, withEmpty)
+	require.NoError(t, err)
+	assert.Equal(t, a.Key, b.Key)
+}
+
+func TestManifestReadWrite(t *testing.T) {
+	pkgPath := filepath.Join(t.TempDir(), "foo-1.0-alt1.x86_64.rpm")
+
+	_, err := buildcache.Read(pkgPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	m, err := buildcache.New(&testConfig{}, testInput(t), testPackage())
+	require.NoError(t, err)
+	require.NoError(t, m.Write(pkgPath))
+	assert.FileExists(t, buildcache.Path(pkgPath))
+
+	read, err := buildcache.Read(pkgPath)
+	require.NoError(t, err)
+	assert.Equal(t, m, read)
+
+	read.Version = 0
+	assert.Len(t, m.Changed(read), 4)
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buildlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+)
+
+const (
+	metaFile   = "meta.json"
+	outputFile = "output.log"
+
+	timestampLayout = "20060102T150405.000Z"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+type StepTiming struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+}
+
+type Meta struct {
+	Repository string       `json:"repository"`
+	Package    string       `json:"package"`
+	Version    string       `json:"version"`
+	Started    time.Time    `json:"started"`
+	Finished   time.Time    `json:"finished"`
+	Status     Status       `json:"status"`
+	Error      string       `json:"error,omitempty"`
+	Steps      []StepTiming `json:"steps"`
+}
+
+// Entry is a single build log. It is stored in its own directory
+// <repo>/<package>/<version>/<timestamp> under the store root.
+type Entry struct {
+	Meta
+	Dir string `json:"-"`
+}
+
+func (e *Entry) OutputPath() string {
+	return filepath.Join(e.Dir, outputFile)
+}
+
+// Files returns the paths of all files of the log.
+func (e *Entry) Files() []string {
+	return []string{
+		filepath.Join(e.Dir, metaFile),
+		e.OutputPath(),
+	}
+}
+
+// FullName returns the package name in the repo/package form.
+func (e *Entry) FullName() string {
+	return e.Repository + "/" + e.Package
+}
+
+type Store struct {
+	fs  afero.Fs
+	dir string
+}
+
+func NewStore(fs afero.Fs, dir string) *Store {
+	return &Store{fs: fs, dir: dir}
+}
+
+// Dir returns the default log directory inside the cache dir.
+func Dir(cacheDir string) string {
+	return filepath.Join(cacheDir, "logs")
+}
+
+// Create starts a new log with an empty output file.
+func (s *Store) Create(repo, pkg, version string, started time.Time) (*Entry, error) {
+	e := &Entry{
+		Meta: Meta{
+			Repository: repo,
+			Package:    pkg,
+			Version:    version,
+			Started:    started,
+			Status:     StatusRunning,
+			Steps:      []StepTiming{},
+		},
+		Dir: filepath.Join(s.dir, repo, pkg, version, started.UTC().Format(timestampLayout)),
+	}
+
+	if err := s.fs.MkdirAll(e.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log dir: %w", err)
+	}
+
+	f, err := s.fs.Create(e.OutputPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := s.Save(e); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Save writes the metadata of e.
+func (s *Store) Save(e *Entry) error {
+	data, err := json.MarshalIndent(e.Meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return afero.WriteFile(s.fs, filepath.Join(e.Dir, metaFile), data, 0o644)
+}
+
+// Finish records the final status of the build.
+func (s *Store) Finish(e *Entry, finished time.Time, buildErr error) error {
+	e.Finished = finished
+	if buildErr != nil {
+		e.Status = StatusFailed
+		e.Error = buildErr.Error()
+	} else {
+		e.Status = StatusSucceeded
+	}
+	return s.Save(e)
+}
+
+// List returns the logs of pkg, newest first. pkg is either a package
+// name or repo/package. An empty pkg lists all logs.
+func (s *Store) List(pkg string) ([]*Entry, error) {
+	repoGlob, pkgGlob := "*", "*"
+	if pkg != "" {
+		pkgGlob = pkg
+		if repo, name, ok := strings.Cut(pkg, "/"); ok {
+			repoGlob, pkgGlob = repo, name
+		}
+	}
+
+	pattern := filepath.Join(s.dir, repoGlob, pkgGlob, "*", "*", metaFile)
+	matches, err := afero.Glob(s.fs, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*Entry, 0, len(matches))
+	for _, m := range matches {
+		e, err := s.read(filepath.Dir(m))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	slices.SortFunc(entries, func(a, b *Entry) int {
+		return b.Started.Compare(a.Started)
+	})
+
+	return entries, nil
+}
+
+// Latest returns up to n newest logs of all packages.
+func (s *Store) Latest(n int) ([]*Entry, error) {
+	entries, err := s.List("")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries, nil
+}
+
+func (s *Store) read(dir string) (*Entry, error) {
+	data, err := afero.ReadFile(s.fs, filepath.Join(dir, metaFile))
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Entry{Dir: dir}
+	if err := json.Unmarshal(data, &e.Meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", dir, err)
+	}
+
+	return e, nil
+}
+
+// ReadOutput returns the combined output of the build.
+func (s *Store) ReadOutput(e *Entry) (string, error) {
+	data, err := afero.ReadFile(s.fs, e.OutputPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package buildlog_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/buildlog"
+)
+
+func TestStore(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	s := buildlog.NewStore(fs, "/logs")
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first, err := s.Create("default", "foo", "1.0", start)
+	require.NoError(t, err)
+	assert.Equal(t, "/logs/default/foo/1.0/20260102T030405.000Z", first.Dir)
+	require.NoError(t, afero.WriteFile(fs, first.OutputPath(), []byte("building foo\n"), 0o644))
+	first.Steps = []buildlog.StepTiming{{Name: "build packages", Duration: time.Second}}
+	require.NoError(t, s.Finish(first, start.Add(time.Minute), nil))
+
+	second, err := s.Create("default", "foo", "1.1", start.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, s.Finish(second, start.Add(2*time.Hour), errors.New("boom")))
+
+	_, err = s.Create("other", "bar", "2.0", start.Add(30*time.Minute))
+	require.NoError(t, err)
+
+	entries, err := s.List("foo")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "1.1", entries[0].Version)
+	assert.Equal(t, buildlog.StatusFailed, entries[0].Status)
+	assert.Equal(t, "boom", entries[0].Error)
+	assert.Equal(t, buildlog.StatusSucceeded, entries[1].Status)
+	assert.Equal(t, first.Steps, entries[1].Steps)
+
+	out, err := s.ReadOutput(entries[1])
+	require.NoError(t, err)
+	assert.Equal(t, "building foo\n", out)
+
+	entries, err = s.List("other/bar")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, buildlog.StatusRunning, entries[0].Status)
+	assert.Equal(t, "other/bar", entries[0].FullName())
+
+	entries, err = s.List("default/bar")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	latest, err := s.Latest(2)
+	require.NoError(t, err)
+	require.Len(t, latest, 2)
+	assert.Equal(t, "1.1", latest[0].Version)
+	assert.Equal(t, "bar", latest[1].Package)
+}
+
+func TestStoreListMissingDir(t *testing.T) {
+	entries, err := buildlog.NewStore(afero.NewMemMapFs(), "/logs").List("foo")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,389 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package cachegc lists and prunes the caches of Stapler: the built
+// packages and build directories in PkgsDir, the downloaded sources,
+// the repo clones in RepoDir and the compiler caches in BuildCacheDir.
+package cachegc
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.stplr.dev/stplr/internal/buildcache"
+	"go.stplr.dev/stplr/internal/config"
+	"go.stplr.dev/stplr/pkg/dl/cache"
+	"go.stplr.dev/stplr/pkg/dl/cache/local"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type Kind string
+
+const (
+	// KindPackage is a built package file with the files stored
+	// next to it, such as its signature and cache manifest.
+	KindPackage Kind = "package"
+	// KindBuildDir is the working directories of builds of a package.
+	KindBuildDir Kind = "build"
+	// KindSource is the downloaded sources of a package version.
+	KindSource Kind = "source"
+	// KindRepo is a repo clone.
+	KindRepo Kind = "repo"
+	// KindCompilerCache is the compiler caches of a package
+	// that are kept between its builds.
+	KindCompilerCache Kind = "compiler-cache"
+)
+
+// Entry is a unit of a cache that is removed as a whole.
+type Entry struct {
+	Kind       Kind
+	Repository string
+	Package    string
+	Version    string
+	Paths      []string
+	Size       int64
+	ModTime    time.Time
+	// Orphan is set for the clones of repos that are not configured.
+	Orphan bool
+
+	sourceIDs []cache.CacheID
+	// sizes are the sizes of Paths
+	sizes []int64
+}
+
+// Name is the repository and package or the repo the entry belongs to.
+func (e *Entry) Name() string {
+	if e.Package == "" {
+		return e.Repository
+	}
+	if e.Repository == "" {
+		return e.Package
+	}
+	return e.Repository + "/" + e.Package
+}
+
+type Config interface {
+	GetPaths() *config.Paths
+	Repos() []types.Repo
+}
+
+type Cache struct {
+	paths   *config.Paths
+	repos   []types.Repo
+	sources *local.LocalCache
+}
+
+func New(cfg Config) *Cache {
+	paths := cfg.GetPaths()
+	return &Cache{
+		paths:   paths,
+		repos:   cfg.Repos(),
+		sources: local.NewLocalCache(SourcesDir(paths.CacheDir)),
+	}
+}
+
+// SourcesDir is the directory of the downloaded sources.
+func SourcesDir(cacheDir string) string {
+	return filepath.Join(cacheDir, "dl")
+}
+
+// List returns the entries of all caches.
+func (c *Cache) List(ctx context.Context) ([]Entry, error) {
+	pkgs, err := listPackages(c.paths.PkgsDir)
+	if err != nil {
+		return nil, err
+	}
+	sources, err := c.listSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := c.listRepos()
+	if err != nil {
+		return nil, err
+	}
+	compilerCaches, err := listCompilerCaches(c.paths.BuildCacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := slices.Concat(pkgs, sources, repos, compilerCaches)
+	for i := range entries {
+		if err := stat(&entries[i]); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+func listPackages(pkgsDir string) ([]Entry, error) {
+	dirs, err := readDir(pkgsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		baseDir := filepath.Join(pkgsDir, d.Name())
+		children, err := readDir(baseDir)
+		if err != nil {
+			return nil, err
+		}
+
+		var files, buildDirs []string
+		for _, child := range children {
+			path := filepath.Join(baseDir, child.Name())
+			switch {
+			case child.IsDir():
+				buildDirs = append(buildDirs, path)
+			case child.Type().IsRegular():
+				files = append(files, path)
+			}
+		}
+
+		for _, paths := range groupSidecars(files) {
+			e := Entry{
+				Kind:    KindPackage,
+				Package: d.Name(),
+				Version: filepath.Base(paths[0]),
+				Paths:   paths,
+			}
+			if m, err := buildcache.Read(paths[0]); err == nil && m.Package != "" {
+				e.Package = m.Package
+				e.Version = m.PkgVersion
+			}
+			entries = append(entries, e)
+		}
+
+		if len(buildDirs) > 0 {
+			entries = append(entries, Entry{
+				Kind:    KindBuildDir,
+				Package: d.Name(),
+				Paths:   buildDirs,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// groupSidecars groups the package files with the files named after
+// them, such as foo.rpm.sig. The package file goes first.
+func groupSidecars(files []string) [][]string {
+	slices.Sort(files)
+
+	var groups [][]string
+	for _, file := range files {
+		i := slices.IndexFunc(groups, func(g []string) bool {
+			return strings.HasPrefix(file, g[0]+".")
+		})
+		if i < 0 {
+			groups = append(groups, []string{file})
+		} else {
+			groups[i] = append(groups[i], file)
+		}
+	}
+	return groups
+}
+
+func (c *Cache) listSources(ctx context.Context) ([]Entry, error) {
+	if _, err := os.Stat(filepath.Join(SourcesDir(c.paths.CacheDir), "db")); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err := c.sources.Connect(); err != nil {
+		return nil, err
+	}
+	records, err := c.sources.Records(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	index := make(map[[3]string]int)
+	for _, r := range records {
+		key := [3]string{r.Repository, r.Package, r.Version}
+		i, ok := index[key]
+		if !ok {
+			i = len(entries)
+			index[key] = i
+			entries = append(entries, Entry{
+				Kind:       KindSource,
+				Repository: r.Repository,
+				Package:    r.Package,
+				Version:    r.Version,
+			})
+		}
+		e := &entries[i]
+		e.sourceIDs = append(e.sourceIDs, r.ID)
+		if !slices.Contains(e.Paths, r.Dir) {
+			e.Paths = append(e.Paths, r.Dir)
+		}
+	}
+	return entries, nil
+}
+
+func (c *Cache) listRepos() ([]Entry, error) {
+	dirs, err := readDir(c.paths.RepoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		configured := slices.ContainsFunc(c.repos, func(r types.Repo) bool {
+			return r.Name == d.Name()
+		})
+		entries = append(entries, Entry{
+			Kind:       KindRepo,
+			Repository: d.Name(),
+			Paths:      []string{filepath.Join(c.paths.RepoDir, d.Name())},
+			Orphan:     !configured,
+		})
+	}
+	return entries, nil
+}
+
+func listCompilerCaches(buildCacheDir string) ([]Entry, error) {
+	if buildCacheDir == "" {
+		return nil, nil
+	}
+	dirs, err := readDir(buildCacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entries = append(entries, Entry{
+			Kind:    KindCompilerCache,
+			Package: d.Name(),
+			Paths:   []string{filepath.Join(buildCacheDir, d.Name())},
+		})
+	}
+	return entries, nil
+}
+
+// Remove removes entries from the caches.
+func (c *Cache) Remove(ctx context.Context, entries []Entry) error {
+	for _, e := range entries {
+		if e.Kind == KindSource {
+			for _, id := range e.sourceIDs {
+				if err := c.sources.Delete(ctx, id); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		for _, path := range e.Paths {
+			if err := removeAll(path); err != nil {
+				return err
+			}
+		}
+		if e.Kind == KindPackage || e.Kind == KindBuildDir {
+			// the base dir of the package goes with its last entry
+			_ = os.Remove(filepath.Dir(e.Paths[0]))
+		}
+	}
+	return nil
+}
+
+// Total returns the size of entries. Paths shared by
+// several entries are counted once.
+func Total(entries []Entry) int64 {
+	seen := make(map[string]bool)
+	var total int64
+	for _, e := range entries {
+		if e.sizes == nil {
+			total += e.Size
+			continue
+		}
+		for i, path := range e.Paths {
+			if !seen[path] {
+				seen[path] = true
+				total += e.sizes[i]
+			}
+		}
+	}
+	return total
+}
+
+func readDir(dir string) ([]fs.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return entries, err
+}
+
+// stat sets the size of e and the time it was last modified.
+func stat(e *Entry) error {
+	e.Size = 0
+	e.sizes = make([]int64, len(e.Paths))
+	for i, path := range e.Paths {
+		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				e.sizes[i] += fi.Size()
+				e.Size += fi.Size()
+			}
+			if fi.ModTime().After(e.ModTime) {
+				e.ModTime = fi.ModTime()
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeAll removes path like os.RemoveAll, making read-only
+// directories writable if needed.
+func removeAll(path string) error {
+	if err := os.RemoveAll(path); err == nil {
+		return nil
+	}
+	_ = filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			_ = os.Chmod(path, 0o755)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cachegc_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/cachegc"
+	"go.stplr.dev/stplr/internal/config"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type testConfig struct {
+	paths *config.Paths
+	repos []types.Repo
+}
+
+func (c *testConfig) GetPaths() *config.Paths { return c.paths }
+func (c *testConfig) Repos() []types.Repo     { return c.repos }
+
+func writeFile(t *testing.T, path string, size int, mtime time.Time) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	require.NoError(t, os.Chtimes(path, mtime, mtime))
+}
+
+func TestList(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &testConfig{
+		paths: &config.Paths{
+			CacheDir: cacheDir,
+			PkgsDir:  filepath.Join(cacheDir, "pkgs"),
+			RepoDir:  filepath.Join(cacheDir, "repo"),
+		},
+		repos: []types.Repo{{Name: "default"}},
+	}
+
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pkgs := cfg.paths.PkgsDir
+	writeFile(t, filepath.Join(pkgs, "foo/foo-1.0-1.x86_64.rpm"), 100, old)
+	writeFile(t, filepath.Join(pkgs, "foo/foo-1.0-1.x86_64.rpm.sig"), 10, old)
+	writeFile(t, filepath.Join(pkgs, "foo/foo-1.1-1.x86_64.rpm"), 200, old.Add(time.Hour))
+	writeFile(t, filepath.Join(pkgs, "foo/src/foo.tar.gz"), 1000, old)
+	writeFile(t, filepath.Join(cfg.paths.RepoDir, "default/foo/Staplerfile"), 5, old)
+	writeFile(t, filepath.Join(cfg.paths.RepoDir, "gone/foo/Staplerfile"), 5, old)
+
+	c := cachegc.New(cfg)
+	entries, err := c.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, entries, 5)
+
+	assert.Equal(t, cachegc.KindPackage, entries[0].Kind)
+	assert.Equal(t, "foo", entries[0].Package)
+	assert.Equal(t, "foo-1.0-1.x86_64.rpm", entries[0].Version)
+	assert.Len(t, entries[0].Paths, 2)
+	assert.Equal(t, int64(110), entries[0].Size)
+	assert.True(t, old.Equal(entries[0].ModTime))
+
+	assert.Equal(t, "foo-1.1-1.x86_64.rpm", entries[1].Version)
+	assert.Equal(t, cachegc.KindBuildDir, entries[2].Kind)
+	assert.Equal(t, int64(1000), entries[2].Size)
+
+	assert.Equal(t, cachegc.KindRepo, entries[3].Kind)
+	assert.False(t, entries[3].Orphan)
+	assert.Equal(t, "gone", entries[4].Repository)
+	assert.True(t, entries[4].Orphan)
+
+	assert.Equal(t, int64(1320), cachegc.Total(entries))
+
+	require.NoError(t, c.Remove(t.Context(), cachegc.Select(entries, cachegc.Policy{KeepLast: 1}, time.Now(), nil)))
+	assert.NoFileExists(t, filepath.Join(pkgs, "foo/foo-1.0-1.x86_64.rpm"))
+	assert.NoFileExists(t, filepath.Join(pkgs, "foo/foo-1.0-1.x86_64.rpm.sig"))
+	assert.FileExists(t, filepath.Join(pkgs, "foo/foo-1.1-1.x86_64.rpm"))
+	assert.NoDirExists(t, filepath.Join(cfg.paths.RepoDir, "gone"))
+	assert.DirExists(t, filepath.Join(cfg.paths.RepoDir, "default"))
+}
+
+func TestListCompilerCaches(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg := &testConfig{
+		paths: &config.Paths{
+			CacheDir:      cacheDir,
+			PkgsDir:       filepath.Join(cacheDir, "pkgs"),
+			RepoDir:       filepath.Join(cacheDir, "repo"),
+			BuildCacheDir: filepath.Join(cacheDir, "build-cache"),
+		},
+	}
+
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeFile(t, filepath.Join(cfg.paths.BuildCacheDir, "foo/ccache/a/b"), 300, old)
+	writeFile(t, filepath.Join(cfg.paths.BuildCacheDir, "foo/go-build/c"), 200, old.Add(time.Hour))
+
+	// the module cache of Go is read-only
+	modDir := filepath.Join(cfg.paths.BuildCacheDir, "foo/go-mod/example.com/mod@v1.0.0")
+	writeFile(t, filepath.Join(modDir, "go.mod"), 10, old)
+	require.NoError(t, os.Chmod(modDir, 0o555))
+
+	c := cachegc.New(cfg)
+	entries, err := c.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	assert.Equal(t, cachegc.KindCompilerCache, entries[0].Kind)
+	assert.Equal(t, "foo", entries[0].Package)
+	assert.Equal(t, int64(510), entries[0].Size)
+
+	require.NoError(t, c.Remove(t.Context(), cachegc.Select(entries, cachegc.Policy{MaxSize: 100}, time.Now(), nil)))
+	assert.NoDirExists(t, filepath.Join(cfg.paths.BuildCacheDir, "foo"))
+	assert.DirExists(t, cfg.paths.BuildCacheDir)
+}
+
+func TestSelect(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	entries := []cachegc.Entry{
+		{Kind: cachegc.KindPackage, Package: "foo", Version: "1.0", Paths: []string{"/p/foo-1.0"}, Size: 100, ModTime: now.Add(-30 * day)},
+		{Kind: cachegc.KindPackage, Package: "foo", Version: "1.1", Paths: []string{"/p/foo-1.1"}, Size: 100, ModTime: now.Add(-10 * day)},
+		{Kind: cachegc.KindPackage, Package: "foo", Version: "1.2", Paths: []string{"/p/foo-1.2"}, Size: 100, ModTime: now.Add(-1 * day)},
+		{Kind: cachegc.KindSource, Repository: "default", Package: "foo", Version: "1.2", Paths: []string{"/s/a"}, Size: 500, ModTime: now.Add(-20 * day)},
+		{Kind: cachegc.KindRepo, Repository: "default", Paths: []string{"/r/default"}, Size: 1000, ModTime: now.Add(-100 * day)},
+		{Kind: cachegc.KindBuildDir, Package: "foo", Paths: []string{"/p/src"}, ModTime: now.Add(-40 * day)},
+	}
+
+	versions := func(entries []cachegc.Entry) []string {
+		var res []string
+		for _, e := range entries {
+			res = append(res, string(e.Kind)+":"+e.Version)
+		}
+		return res
+	}
+
+	for _, tc := range []struct {
+		name   string
+		policy cachegc.Policy
+		keep   []string
+		want   []string
+	}{
+		{"none", cachegc.Policy{}, nil, nil},
+		{"older than", cachegc.Policy{OlderThan: 15 * day}, nil, []string{"package:1.0", "source:1.2", "build:"}},
+		{"older than keeps build dirs", cachegc.Policy{OlderThan: 15 * day}, []string{"/p"}, []string{"package:1.0", "source:1.2"}},
+		{"keep last", cachegc.Policy{KeepLast: 2}, nil, []string{"package:1.0"}},
+		{"max size", cachegc.Policy{MaxSize: 250}, nil, []string{"package:1.0", "source:1.2", "build:"}},
+		{"max size keeps", cachegc.Policy{MaxSize: 250}, []string{"/p/foo-1.0"}, []string{"source:1.2", "package:1.1", "build:"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := versions(cachegc.Select(entries, tc.policy, now, tc.keep))
+			assert.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
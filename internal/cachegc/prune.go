@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cachegc
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"go.stplr.dev/stplr/internal/units"
+)
+
+// Policy tells which entries to prune. The clones of configured
+// repos are never pruned, the clones of other repos always are.
+type Policy struct {
+	// OlderThan prunes the entries not modified for longer.
+	OlderThan time.Duration
+	// KeepLast prunes all but the last versions of every package.
+	KeepLast int
+	// MaxSize prunes the oldest entries until the rest fits.
+	MaxSize int64
+}
+
+// Select returns the entries that pruning by p removes at now.
+// Entries with a path in keep are never selected, nor are build
+// directories inside a directory in keep.
+func Select(entries []Entry, p Policy, now time.Time, keep []string) []Entry {
+	protected := make([]bool, len(entries))
+	for i, e := range entries {
+		protected[i] = slices.ContainsFunc(e.Paths, func(path string) bool {
+			return slices.ContainsFunc(keep, func(k string) bool {
+				return isKept(e.Kind, path, k)
+			})
+		})
+	}
+
+	selected := make([]bool, len(entries))
+	for i, e := range entries {
+		switch {
+		case protected[i]:
+		case e.Kind == KindRepo:
+			selected[i] = e.Orphan
+		case p.OlderThan > 0 && now.Sub(e.ModTime) > p.OlderThan:
+			selected[i] = true
+		}
+	}
+
+	if p.KeepLast > 0 {
+		for _, group := range versionGroups(entries) {
+			old := oldVersions(entries, group, p.KeepLast)
+			for _, i := range group {
+				if !protected[i] && slices.Contains(old, entries[i].Version) {
+					selected[i] = true
+				}
+			}
+		}
+	}
+
+	if p.MaxSize > 0 {
+		var total int64
+		var candidates []int
+		for i, e := range entries {
+			if e.Kind == KindRepo || selected[i] {
+				continue
+			}
+			total += e.Size
+			if !protected[i] {
+				candidates = append(candidates, i)
+			}
+		}
+		slices.SortStableFunc(candidates, func(a, b int) int {
+			return entries[a].ModTime.Compare(entries[b].ModTime)
+		})
+		for _, i := range candidates {
+			if total <= p.MaxSize {
+				break
+			}
+			selected[i] = true
+			total -= entries[i].Size
+		}
+	}
+
+	var res []Entry
+	for i, e := range entries {
+		if selected[i] {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// isKept reports whether keeping k keeps path of an entry of kind.
+func isKept(kind Kind, path, k string) bool {
+	path, k = filepath.Clean(path), filepath.Clean(k)
+	if path == k {
+		return true
+	}
+	return kind == KindBuildDir && strings.HasPrefix(path, k+string(filepath.Separator))
+}
+
+// versionGroups groups the indexes of the versioned
+// entries by their kind and package.
+func versionGroups(entries []Entry) [][]int {
+	var groups [][]int
+	index := make(map[[3]string]int)
+	for i, e := range entries {
+		if e.Kind != KindPackage && e.Kind != KindSource {
+			continue
+		}
+		key := [3]string{string(e.Kind), e.Repository, e.Package}
+		g, ok := index[key]
+		if !ok {
+			g = len(groups)
+			index[key] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// oldVersions returns the versions of group but the keep
+// most recently modified ones.
+func oldVersions(entries []Entry, group []int, keep int) []string {
+	latest := make(map[string]time.Time)
+	for _, i := range group {
+		e := entries[i]
+		if t, ok := latest[e.Version]; !ok || e.ModTime.After(t) {
+			latest[e.Version] = e.ModTime
+		}
+	}
+
+	versions := make([]string, 0, len(latest))
+	for v := range latest {
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, func(a, b string) int {
+		return cmp.Or(latest[b].Compare(latest[a]), cmp.Compare(a, b))
+	})
+	if len(versions) <= keep {
+		return nil
+	}
+	return versions[keep:]
+}
+
+// IsZero reports whether p only prunes the clones of removed repos.
+func (p Policy) IsZero() bool {
+	return p == Policy{}
+}
+
+// ConfigPolicy returns the policy of the cache size and age limits
+// of the configuration. Empty limits are not applied.
+func ConfigPolicy(maxSize, maxAge string) (Policy, error) {
+	var p Policy
+	var err error
+	if maxSize != "" {
+		if p.MaxSize, err = units.ParseSize(maxSize); err != nil {
+			return Policy{}, err
+		}
+	}
+	if maxAge != "" {
+		if p.OlderThan, err = units.ParseAge(maxAge); err != nil {
+			return Policy{}, err
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package cleanroot prepares throwaway root filesystems for clean-root
+// builds, which only see the base image and the declared build
+// dependencies instead of the host system.
+package cleanroot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Prepare unpacks image into a new directory under dir and returns it.
+// image is a plain rootfs tarball, an OCI image layout directory or
+// a tarball of one.
+func Prepare(image, dir string) (string, error) {
+	rootfs, err := os.MkdirTemp(dir, "rootfs-*")
+	if err != nil {
+		return "", err
+	}
+
+	if err := unpack(image, rootfs); err != nil {
+		_ = Remove(rootfs)
+		return "", fmt.Errorf("unpacking base image %q: %w", image, err)
+	}
+
+	tmp := filepath.Join(rootfs, "tmp")
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		_ = Remove(rootfs)
+		return "", err
+	}
+	if err := os.Chmod(tmp, os.ModeSticky|0o777); err != nil {
+		_ = Remove(rootfs)
+		return "", err
+	}
+
+	return rootfs, nil
+}
+
+// Remove deletes a rootfs created by Prepare.
+func Remove(rootfs string) error {
+	// package managers leave directories without the write bit
+	_ = filepath.WalkDir(rootfs, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			_ = os.Chmod(path, 0o755)
+		}
+		return nil
+	})
+	return os.RemoveAll(rootfs)
+}
+
+func unpack(image, rootfs string) error {
+	fi, err := os.Stat(image)
+	if err != nil {
+		return err
+	}
+
+	if fi.IsDir() {
+		if !isLayout(image) {
+			return fmt.Errorf("%q is not an OCI image layout", image)
+		}
+		return applyLayout(image, rootfs)
+	}
+
+	f, err := os.Open(image)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := extract(f, rootfs, false); err != nil {
+		return err
+	}
+
+	if !isLayout(rootfs) {
+		return nil
+	}
+
+	// The tarball holds an OCI layout, not the rootfs itself.
+	layout, err := os.MkdirTemp(filepath.Dir(rootfs), "layout-*")
+	if err != nil {
+		return err
+	}
+	defer Remove(layout) //nolint:errcheck
+
+	entries, err := os.ReadDir(rootfs)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Rename(filepath.Join(rootfs, e.Name()), filepath.Join(layout, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	return applyLayout(layout, rootfs)
+}
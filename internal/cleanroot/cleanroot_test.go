@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cleanroot_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/cleanroot"
+)
+
+type entry struct {
+	name     string
+	body     string
+	typeflag byte
+	linkname string
+}
+
+func makeTar(t *testing.T, entries []entry, compress bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buf)
+		w = gz
+	}
+
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Typeflag: e.typeflag, Linkname: e.linkname, Mode: 0o644}
+		switch e.typeflag {
+		case tar.TypeDir:
+			hdr.Mode = 0o755
+		case tar.TypeReg:
+			hdr.Size = int64(len(e.body))
+		}
+		require.NoError(t, tw.WriteHeader(hdr))
+		if e.typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(e.body))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	if gz != nil {
+		require.NoError(t, gz.Close())
+	}
+	return buf.Bytes()
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestPreparePlainTar(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "base.tar.gz")
+	writeFile(t, image, makeTar(t, []entry{
+		{name: "usr/", typeflag: tar.TypeDir},
+		{name: "usr/bin/", typeflag: tar.TypeDir},
+		{name: "usr/bin/sh", body: "shell", typeflag: tar.TypeReg},
+		{name: "bin", typeflag: tar.TypeSymlink, linkname: "usr/bin"},
+		{name: "etc/os-release", body: "ID=test\n", typeflag: tar.TypeReg},
+		{name: "etc/escape", typeflag: tar.TypeSymlink, linkname: "/"},
+		// these must stay inside the rootfs
+		{name: "../outside", body: "x", typeflag: tar.TypeReg},
+		{name: "etc/escape/etc/passwd", body: "x", typeflag: tar.TypeReg},
+	}, true))
+
+	rootfs, err := cleanroot.Prepare(image, dir)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cleanroot.Remove(rootfs) })
+
+	assert.Equal(t, "shell", readFile(t, filepath.Join(rootfs, "bin/sh")))
+	assert.Equal(t, "ID=test\n", readFile(t, filepath.Join(rootfs, "etc/os-release")))
+	assert.Equal(t, "x", readFile(t, filepath.Join(rootfs, "outside")))
+	assert.Equal(t, "x", readFile(t, filepath.Join(rootfs, "etc/passwd")))
+	assert.NoFileExists(t, filepath.Join(dir, "outside"))
+
+	fi, err := os.Stat(filepath.Join(rootfs, "tmp"))
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeSticky|0o777, fi.Mode()&(os.ModeSticky|os.ModePerm))
+
+	require.NoError(t, cleanroot.Remove(rootfs))
+	assert.NoDirExists(t, rootfs)
+}
+
+type layoutBuilder struct {
+	t   *testing.T
+	dir string
+}
+
+func (b *layoutBuilder) blob(data []byte) string {
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	writeFile(b.t, filepath.Join(b.dir, "blobs", "sha256", hex.EncodeToString(sum[:])), data)
+	return digest
+}
+
+func (b *layoutBuilder) json(v any) string {
+	data, err := json.Marshal(v)
+	require.NoError(b.t, err)
+	return b.blob(data)
+}
+
+func newLayout(t *testing.T, dir string, layers ...[]byte) {
+	b := &layoutBuilder{t: t, dir: dir}
+
+	var layerDescs []map[string]string
+	for _, l := range layers {
+		layerDescs = append(layerDescs, map[string]string{
+			"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip",
+			"digest":    b.blob(l),
+		})
+	}
+	manifest := b.json(map[string]any{"schemaVersion": 2, "layers": layerDescs})
+
+	data, err := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"manifests": []map[string]string{{
+			"mediaType": "application/vnd.oci.image.manifest.v1+json",
+			"digest":    manifest,
+		}},
+	})
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, "index.json"), data)
+	writeFile(t, filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion":"1.0.0"}`))
+}
+
+func TestPrepareOCILayout(t *testing.T) {
+	dir := t.TempDir()
+	layout := filepath.Join(dir, "layout")
+	newLayout(t, layout,
+		makeTar(t, []entry{
+			{name: "etc/", typeflag: tar.TypeDir},
+			{name: "etc/keep", body: "keep", typeflag: tar.TypeReg},
+			{name: "etc/removed", body: "removed", typeflag: tar.TypeReg},
+			{name: "var/cache/old", body: "old", typeflag: tar.TypeReg},
+		}, true),
+		makeTar(t, []entry{
+			{name: "etc/.wh.removed", typeflag: tar.TypeReg},
+			{name: "var/cache/.wh..wh..opq", typeflag: tar.TypeReg},
+			{name: "var/cache/new", body: "new", typeflag: tar.TypeReg},
+		}, false),
+	)
+
+	check := func(rootfs string) {
+		assert.Equal(t, "keep", readFile(t, filepath.Join(rootfs, "etc/keep")))
+		assert.NoFileExists(t, filepath.Join(rootfs, "etc/removed"))
+		assert.NoFileExists(t, filepath.Join(rootfs, "var/cache/old"))
+		assert.Equal(t, "new", readFile(t, filepath.Join(rootfs, "var/cache/new")))
+		assert.NoFileExists(t, filepath.Join(rootfs, "index.json"))
+	}
+
+	t.Run("directory", func(t *testing.T) {
+		rootfs, err := cleanroot.Prepare(layout, dir)
+		require.NoError(t, err)
+		defer cleanroot.Remove(rootfs) //nolint:errcheck
+		check(rootfs)
+	})
+
+	t.Run("tarball", func(t *testing.T) {
+		var entries []entry
+		require.NoError(t, filepath.WalkDir(layout, func(path string, d os.DirEntry, err error) error {
+			require.NoError(t, err)
+			rel, err := filepath.Rel(layout, path)
+			require.NoError(t, err)
+			if d.IsDir() {
+				if rel != "." {
+					entries = append(entries, entry{name: rel + "/", typeflag: tar.TypeDir})
+				}
+				return nil
+			}
+			entries = append(entries, entry{name: rel, body: readFile(t, path), typeflag: tar.TypeReg})
+			return nil
+		}))
+		image := filepath.Join(dir, "image.tar")
+		writeFile(t, image, makeTar(t, entries, false))
+
+		rootfs, err := cleanroot.Prepare(image, dir)
+		require.NoError(t, err)
+		defer cleanroot.Remove(rootfs) //nolint:errcheck
+		check(rootfs)
+	})
+
+	t.Run("corrupted blob", func(t *testing.T) {
+		var idx struct {
+			Manifests []struct{ Digest string }
+		}
+		require.NoError(t, json.Unmarshal([]byte(readFile(t, filepath.Join(layout, "index.json"))), &idx))
+
+		broken := filepath.Join(dir, "broken")
+		require.NoError(t, os.CopyFS(broken, os.DirFS(layout)))
+		manifest := filepath.Join(broken, "blobs", "sha256", idx.Manifests[0].Digest[len("sha256:"):])
+		require.NoError(t, os.Chmod(manifest, 0o644))
+		writeFile(t, manifest, []byte(`{"layers":[]}`))
+
+		_, err := cleanroot.Prepare(broken, dir)
+		require.ErrorContains(t, err, "digest mismatch")
+	})
+}
+
+type fakeRunner struct {
+	calls [][]string
+}
+
+func (r *fakeRunner) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	r.calls = append(r.calls, args)
+	return nil
+}
+
+func TestInstall(t *testing.T) {
+	rootfs := t.TempDir()
+	writeFile(t, filepath.Join(rootfs, "usr/bin/apt-get"), nil)
+
+	pkg := filepath.Join(t.TempDir(), "libfoo_1.0_amd64.deb")
+	writeFile(t, pkg, []byte("deb"))
+
+	r := &fakeRunner{}
+	err := cleanroot.Install(context.Background(), r, rootfs, []string{"gcc", "make>=4.0"}, []string{pkg}, io.Discard)
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{
+		{"apt-get", "update"},
+		{"apt-get", "install", "-y", "--no-install-recommends", "gcc", "make"},
+		{"apt-get", "install", "-y", "--no-install-recommends", "/tmp/stplr-deps/libfoo_1.0_amd64.deb"},
+	}, r.calls)
+	assert.NoDirExists(t, filepath.Join(rootfs, "tmp/stplr-deps"))
+
+	err = cleanroot.Install(context.Background(), r, t.TempDir(), []string{"gcc"}, nil, io.Discard)
+	require.Error(t, err)
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cleanroot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = ".wh..wh..opq"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	bzip2Magic = []byte{'B', 'Z', 'h'}
+)
+
+// decompress detects the compression of r by its magic bytes.
+func decompress(r io.Reader) (io.Reader, func(), error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(6)
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() { _ = zr.Close() }, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	case bytes.HasPrefix(magic, xzMagic):
+		zr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() {}, nil
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2.NewReader(br), func() {}, nil
+	}
+
+	return br, func() {}, nil
+}
+
+// extract unpacks the tar archive r into dest. Paths can't escape dest,
+// even through symlinks in the archive. If whiteouts is true, OCI
+// whiteout files remove the files of the layers below.
+func extract(r io.Reader, dest string, whiteouts bool) error {
+	dr, closeReader, err := decompress(r)
+	if err != nil {
+		return err
+	}
+	defer closeReader()
+
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean("/" + hdr.Name)
+		if name == "/" {
+			continue
+		}
+
+		parent, err := securejoin.SecureJoin(dest, filepath.Dir(name))
+		if err != nil {
+			return err
+		}
+		base := filepath.Base(name)
+
+		if whiteouts && strings.HasPrefix(base, whiteoutPrefix) {
+			if err := applyWhiteout(parent, base); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return err
+		}
+		if err := extractEntry(tr, hdr, dest, filepath.Join(parent, base)); err != nil {
+			return fmt.Errorf("extracting %q: %w", hdr.Name, err)
+		}
+	}
+}
+
+func applyWhiteout(dir, base string) error {
+	if base == whiteoutOpaque {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		for _, e := range entries {
+			if err := Remove(filepath.Join(dir, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return Remove(filepath.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+}
+
+func extractEntry(tr *tar.Reader, hdr *tar.Header, root, path string) error {
+	mode := os.FileMode(hdr.Mode).Perm() //nolint:gosec
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		fi, err := os.Lstat(path)
+		if err == nil && !fi.IsDir() {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return err
+		}
+		// keep directories writable, so that they can be
+		// filled and removed without privileges
+		return os.Chmod(path, mode|0o700)
+
+	case tar.TypeReg:
+		if err := removeExisting(path); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, tr); err != nil { //nolint:gosec
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		return os.Chmod(path, mode)
+
+	case tar.TypeSymlink:
+		if err := removeExisting(path); err != nil {
+			return err
+		}
+		return os.Symlink(hdr.Linkname, path)
+
+	case tar.TypeLink:
+		target, err := securejoin.SecureJoin(root, filepath.Clean("/"+hdr.Linkname))
+		if err != nil {
+			return err
+		}
+		if err := removeExisting(path); err != nil {
+			return err
+		}
+		return os.Link(target, path)
+	}
+
+	// Device nodes and fifos can't be created without privileges,
+	// the sandbox provides its own /dev.
+	return nil
+}
+
+func removeExisting(path string) error {
+	fi, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return Remove(path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cleanroot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	securejoin "github.com/cyphar/filepath-securejoin"
+
+	"go.stplr.dev/stplr/internal/depver"
+	"go.stplr.dev/stplr/internal/osutils"
+)
+
+// Runner runs a command as root inside the rootfs.
+type Runner interface {
+	Run(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+type packageManager struct {
+	binary       string
+	refresh      []string
+	install      []string
+	installLocal []string
+}
+
+// packageManagers are tried in order, the first one present in the
+// rootfs is used.
+var packageManagers = []packageManager{
+	{
+		binary:       "/usr/bin/apt-get",
+		refresh:      []string{"apt-get", "update"},
+		install:      []string{"apt-get", "install", "-y", "--no-install-recommends"},
+		installLocal: []string{"apt-get", "install", "-y", "--no-install-recommends"},
+	},
+	{
+		binary:       "/usr/bin/dnf",
+		install:      []string{"dnf", "install", "-y"},
+		installLocal: []string{"dnf", "install", "-y"},
+	},
+	{
+		binary:       "/usr/bin/yum",
+		install:      []string{"yum", "install", "-y"},
+		installLocal: []string{"yum", "install", "-y"},
+	},
+	{
+		binary:       "/usr/bin/zypper",
+		refresh:      []string{"zypper", "--non-interactive", "refresh"},
+		install:      []string{"zypper", "--non-interactive", "install"},
+		installLocal: []string{"zypper", "--non-interactive", "install", "--allow-unsigned-rpm"},
+	},
+	{
+		binary:       "/sbin/apk",
+		refresh:      []string{"apk", "update"},
+		install:      []string{"apk", "add"},
+		installLocal: []string{"apk", "add", "--allow-untrusted"},
+	},
+	{
+		binary:       "/usr/bin/pacman",
+		refresh:      []string{"pacman", "-Sy"},
+		install:      []string{"pacman", "-S", "--noconfirm", "--needed"},
+		installLocal: []string{"pacman", "-U", "--noconfirm"},
+	},
+}
+
+func detectPackageManager(rootfs string) (packageManager, error) {
+	for _, pm := range packageManagers {
+		path, err := securejoin.SecureJoin(rootfs, pm.binary)
+		if err != nil {
+			continue
+		}
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return pm, nil
+		}
+	}
+	return packageManager{}, errors.New("no supported package manager found in the base image")
+}
+
+// localPackagesDir is where local packages are copied to in the rootfs.
+const localPackagesDir = "/tmp/stplr-deps"
+
+// Install installs the repository packages depends and the local
+// package files into rootfs using its own package manager.
+func Install(ctx context.Context, r Runner, rootfs string, depends, packages []string, out io.Writer) error {
+	if len(depends) == 0 && len(packages) == 0 {
+		return nil
+	}
+
+	pm, err := detectPackageManager(rootfs)
+	if err != nil {
+		return err
+	}
+
+	run := func(args []string) error {
+		if err := r.Run(ctx, args, out, out); err != nil {
+			return fmt.Errorf("running %q: %w", args[0], err)
+		}
+		return nil
+	}
+
+	if pm.refresh != nil {
+		if err := run(pm.refresh); err != nil {
+			return err
+		}
+	}
+
+	if len(depends) > 0 {
+		if err := run(append(slices.Clone(pm.install), depver.Names(depends)...)); err != nil {
+			return err
+		}
+	}
+
+	if len(packages) > 0 {
+		dir := filepath.Join(rootfs, localPackagesDir)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		args := slices.Clone(pm.installLocal)
+		for _, pkg := range packages {
+			name := filepath.Base(pkg)
+			if err := osutils.CopyFile(pkg, filepath.Join(dir, name)); err != nil {
+				return err
+			}
+			args = append(args, filepath.Join(localPackagesDir, name))
+		}
+		if err := run(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cleanroot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	mediaTypeIndex      = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+type index struct {
+	Manifests []descriptor `json:"manifests"`
+}
+
+type manifest struct {
+	Layers []descriptor `json:"layers"`
+}
+
+func isLayout(dir string) bool {
+	for _, name := range []string{"oci-layout", "index.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// applyLayout unpacks the layers of the image in the OCI layout dir
+// into rootfs. For multi-platform images the one matching the host
+// architecture is used.
+func applyLayout(dir, rootfs string) error {
+	var idx index
+	if err := readJSON(filepath.Join(dir, "index.json"), &idx); err != nil {
+		return err
+	}
+
+	desc, err := chooseManifest(dir, idx)
+	if err != nil {
+		return err
+	}
+
+	var m manifest
+	if err := readBlobJSON(dir, desc.Digest, &m); err != nil {
+		return err
+	}
+
+	for _, layer := range m.Layers {
+		if err := applyLayer(dir, layer.Digest, rootfs); err != nil {
+			return fmt.Errorf("applying layer %s: %w", layer.Digest, err)
+		}
+	}
+
+	return nil
+}
+
+func chooseManifest(dir string, idx index) (descriptor, error) {
+	for range 8 {
+		desc, ok := matchPlatform(idx.Manifests)
+		if !ok {
+			return descriptor{}, errors.New("no image for this platform in the layout")
+		}
+		if desc.MediaType != mediaTypeIndex && desc.MediaType != mediaTypeDockerList {
+			return desc, nil
+		}
+		idx = index{}
+		if err := readBlobJSON(dir, desc.Digest, &idx); err != nil {
+			return descriptor{}, err
+		}
+	}
+	return descriptor{}, errors.New("image indexes are nested too deep")
+}
+
+func matchPlatform(descs []descriptor) (descriptor, bool) {
+	for _, d := range descs {
+		if d.Platform == nil || d.Platform.Architecture == runtime.GOARCH {
+			return d, true
+		}
+	}
+	return descriptor{}, false
+}
+
+func blobPath(dir, digest string) (string, error) {
+	algo, hash, ok := strings.Cut(digest, ":")
+	if !ok || algo == "" || hash == "" || strings.ContainsAny(algo+hash, `/\.`) {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return filepath.Join(dir, "blobs", algo, hash), nil
+}
+
+// openBlob opens a blob and returns a function that checks its digest
+// after it was read to the end.
+func openBlob(dir, digest string) (io.ReadCloser, func() error, error) {
+	path, err := blobPath(dir, digest)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !strings.HasPrefix(digest, "sha256:") {
+		return f, func() error { return nil }, nil
+	}
+
+	h := sha256.New()
+	r := struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(f, h), f}
+	verify := func() error {
+		// drain the tar padding the reader may have left
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			return err
+		}
+		if got := "sha256:" + hex.EncodeToString(h.Sum(nil)); got != digest {
+			return fmt.Errorf("digest mismatch: got %s, want %s", got, digest)
+		}
+		return nil
+	}
+	return r, verify, nil
+}
+
+func readBlobJSON(dir, digest string, v any) error {
+	r, verify, err := openBlob(dir, digest)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := json.NewDecoder(r).Decode(v); err != nil {
+		return err
+	}
+	return verify()
+}
+
+func applyLayer(dir, digest, rootfs string) error {
+	r, verify, err := openBlob(dir, digest)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := extract(r, rootfs, true); err != nil {
+		return err
+	}
+	return verify()
+}
+
+func readJSON(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
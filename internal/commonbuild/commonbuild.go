@@ -34,6 +34,33 @@ type BuildInput struct {
 	Script      string
 	Repository_ string
 	Packages_   []string
+	// LogPath is the file the output of the build functions is
+	// appended to. Empty means no log.
+	LogPath string
+	// SourceDateEpoch is exported to the build as SOURCE_DATE_EPOCH
+	// and used for file times in reproducible builds.
+	SourceDateEpoch int64
+	// SigningPassphrase unlocks the signing key of the package format.
+	// The builder user can't read the passphrase sources itself.
+	SigningPassphrase string
+	// SourceURL and SourceCommit tell where the script came from.
+	// They are recorded in the build provenance.
+	SourceURL    string
+	SourceCommit string
+	// ProvenancePassphrase unlocks the provenance signing key.
+	ProvenancePassphrase string
+	// CleanRootDepends and CleanRootPackages are installed into the
+	// rootfs of a clean-root build, from the distro repositories and
+	// from local package files.
+	CleanRootDepends  []string
+	CleanRootPackages []string
+	// LintLevels are the lint settings of the repository,
+	// see pkglint.ParseLevels.
+	LintLevels map[string]string
+	// SandboxEnv are the variables of the environment of stplr that
+	// sandboxEnvPassthrough allows for the packages, as the builder
+	// doesn't get the environment of the user.
+	SandboxEnv []string
 }
 
 func (bi *BuildInput) GobEncode() ([]byte, error) {
@@ -61,6 +88,36 @@ func (bi *BuildInput) GobEncode() ([]byte, error) {
 	if err := encoder.Encode(bi.Packages_); err != nil {
 		return nil, err
 	}
+	if err := encoder.Encode(bi.LogPath); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.SourceDateEpoch); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.SigningPassphrase); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.SourceURL); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.SourceCommit); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.ProvenancePassphrase); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.CleanRootDepends); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.CleanRootPackages); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.LintLevels); err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(bi.SandboxEnv); err != nil {
+		return nil, err
+	}
 
 	return w.Bytes(), nil
 }
@@ -90,6 +147,36 @@ func (bi *BuildInput) GobDecode(data []byte) error {
 	if err := decoder.Decode(&bi.Packages_); err != nil {
 		return err
 	}
+	if err := decoder.Decode(&bi.LogPath); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.SourceDateEpoch); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.SigningPassphrase); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.SourceURL); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.SourceCommit); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.ProvenancePassphrase); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.CleanRootDepends); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.CleanRootPackages); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.LintLevels); err != nil {
+		return err
+	}
+	if err := decoder.Decode(&bi.SandboxEnv); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -113,3 +200,7 @@ func (b *BuildInput) PkgFormat() string {
 func (b *BuildInput) Packages() []string {
 	return b.Packages_
 }
+
+func (b *BuildInput) ScriptPath() string {
+	return b.Script
+}
@@ -27,6 +27,9 @@ import (
 type BuiltDep struct {
 	Name string
 	Path string
+	// DebugInfo is set for split debug info packages,
+	// which are not installed along with the package.
+	DebugInfo bool
 }
 
 type BuildOptsProvider interface {
@@ -50,6 +53,22 @@ type Config interface {
 	PagerStyle() string
 	FirejailExclude() []string
 	HideFirejailExcludeWarning() bool
+	NoCheck() bool
+	Reproducible() bool
+	SBOM() bool
+	Signing() types.Signing
+	PackageOptions() []string
+	DebugInfo() bool
+	BuildMemoryMax() string
+	BuildCPUMax() string
+	BuildPidsMax() string
+	BuildIOWeight() string
+	BuildTimeout() string
+	BuildCache() bool
+	SandboxSeccompProfile() string
+	SandboxCapabilities() []string
+	SandboxBinds() []string
+	SandboxEnvPassthrough() []string
 }
 
 type FunctionsOutput struct {
@@ -53,6 +53,12 @@ func GetSrcDir(cfg Config, basePkg string) string {
 	return filepath.Join(GetBaseDir(cfg, basePkg), "src")
 }
 
+// GetBuildCacheDir returns the directory the compiler
+// caches of basePkg are kept in between builds.
+func GetBuildCacheDir(cfg Config, basePkg string) string {
+	return filepath.Join(cfg.GetPaths().BuildCacheDir, basePkg)
+}
+
 func GetScriptDir(scriptPath string) string {
 	return filepath.Dir(scriptPath)
 }
@@ -30,6 +30,24 @@ const (
 	FORBID_BUILD_COMMAND          = "forbidBuildCommand"
 	FIREJAIL_EXCLUDE              = "firejailExclude"
 	HIDE_FIREJAIL_EXCLUDE_WARNING = "hideFirejailExcludeWarning"
+	NO_CHECK                      = "noCheck"
+	REPRODUCIBLE                  = "reproducible"
+	SBOM                          = "sbom"
+	CLEAN_ROOT_IMAGE              = "cleanRootImage"
+	PACKAGE_OPTIONS               = "packageOptions"
+	DEBUG_INFO                    = "debugInfo"
+	CACHE_MAX_SIZE                = "cacheMaxSize"
+	CACHE_MAX_AGE                 = "cacheMaxAge"
+	BUILD_MEMORY_MAX              = "buildMemoryMax"
+	BUILD_CPU_MAX                 = "buildCpuMax"
+	BUILD_PIDS_MAX                = "buildPidsMax"
+	BUILD_IO_WEIGHT               = "buildIoWeight"
+	BUILD_TIMEOUT                 = "buildTimeout"
+	SANDBOX_SECCOMP_PROFILE       = "sandboxSeccompProfile"
+	SANDBOX_CAPABILITIES          = "sandboxCapabilities"
+	BUILD_CACHE                   = "buildCache"
+	SANDBOX_BINDS                 = "sandboxBinds"
+	SANDBOX_ENV_PASSTHROUGH       = "sandboxEnvPassthrough"
 )
 
 const (
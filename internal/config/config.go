@@ -141,6 +141,7 @@ func (c *ALRConfig) Load() error {
 	c.paths.CacheDir = constants.SystemCachePath
 	c.paths.RepoDir = filepath.Join(c.paths.CacheDir, "repo")
 	c.paths.PkgsDir = filepath.Join(c.paths.CacheDir, "pkgs")
+	c.paths.BuildCacheDir = filepath.Join(c.paths.CacheDir, "build-cache")
 	c.paths.DBPath = filepath.Join(c.paths.CacheDir, "db")
 
 	return nil
@@ -264,6 +265,25 @@ func (c *ALRConfig) FirejailExclude() []string        { return c.cfg.FirejailExc
 func (c *ALRConfig) HideFirejailExcludeWarning() bool { return c.cfg.HideFirejailExcludeWarning }
 func (c *ALRConfig) ForbidSkipInChecksums() bool      { return c.cfg.ForbidSkipInChecksums }
 func (c *ALRConfig) ForbidBuildCommand() bool         { return c.cfg.ForbidBuildCommand }
+func (c *ALRConfig) NoCheck() bool                    { return c.cfg.NoCheck }
+func (c *ALRConfig) Reproducible() bool               { return c.cfg.Reproducible }
+func (c *ALRConfig) SBOM() bool                       { return c.cfg.SBOM }
+func (c *ALRConfig) CleanRootImage() string           { return c.cfg.CleanRootImage }
+func (c *ALRConfig) CacheMaxSize() string             { return c.cfg.CacheMaxSize }
+func (c *ALRConfig) CacheMaxAge() string              { return c.cfg.CacheMaxAge }
+func (c *ALRConfig) PackageOptions() []string         { return c.cfg.PackageOptions }
+func (c *ALRConfig) DebugInfo() bool                  { return c.cfg.DebugInfo }
+func (c *ALRConfig) BuildMemoryMax() string           { return c.cfg.BuildMemoryMax }
+func (c *ALRConfig) BuildCPUMax() string              { return c.cfg.BuildCPUMax }
+func (c *ALRConfig) BuildPidsMax() string             { return c.cfg.BuildPidsMax }
+func (c *ALRConfig) BuildIOWeight() string            { return c.cfg.BuildIOWeight }
+func (c *ALRConfig) BuildTimeout() string             { return c.cfg.BuildTimeout }
+func (c *ALRConfig) SandboxSeccompProfile() string    { return c.cfg.SandboxSeccompProfile }
+func (c *ALRConfig) SandboxCapabilities() []string    { return c.cfg.SandboxCapabilities }
+func (c *ALRConfig) BuildCache() bool                 { return c.cfg.BuildCache }
+func (c *ALRConfig) SandboxBinds() []string           { return c.cfg.SandboxBinds }
+func (c *ALRConfig) SandboxEnvPassthrough() []string  { return c.cfg.SandboxEnvPassthrough }
+func (c *ALRConfig) Signing() types.Signing           { return c.cfg.Signing }
 func (c *ALRConfig) GetPaths() *Paths                 { return c.paths }
 
 // TODO: refactor
@@ -275,6 +295,7 @@ func PatchToUserDirs(c *ALRConfig) error {
 	}
 	paths.CacheDir = filepath.Join(userCacheDir, "stplr")
 	paths.PkgsDir = filepath.Join(paths.CacheDir, "pkgs")
+	paths.BuildCacheDir = filepath.Join(paths.CacheDir, "build-cache")
 
 	return nil
 }
@@ -291,20 +312,40 @@ func AllowedKeys() []string {
 		common.FORBID_BUILD_COMMAND,
 		common.FIREJAIL_EXCLUDE,
 		common.HIDE_FIREJAIL_EXCLUDE_WARNING,
+		common.NO_CHECK,
+		common.REPRODUCIBLE,
+		common.SBOM,
+		common.CLEAN_ROOT_IMAGE,
+		common.PACKAGE_OPTIONS,
+		common.DEBUG_INFO,
+		common.CACHE_MAX_SIZE,
+		common.CACHE_MAX_AGE,
+		common.BUILD_MEMORY_MAX,
+		common.BUILD_CPU_MAX,
+		common.BUILD_PIDS_MAX,
+		common.BUILD_IO_WEIGHT,
+		common.BUILD_TIMEOUT,
+		common.SANDBOX_SECCOMP_PROFILE,
+		common.SANDBOX_CAPABILITIES,
+		common.BUILD_CACHE,
+		common.SANDBOX_BINDS,
+		common.SANDBOX_ENV_PASSTHROUGH,
 	}
 }
 
 func ConvertValue(key, v string) (any, error) {
 	switch key {
 	case common.AUTO_PULL, common.USE_ROOT_CMD,
-		common.FORBID_SKIP_IN_CHECKSUMS, common.FORBID_BUILD_COMMAND, common.HIDE_FIREJAIL_EXCLUDE_WARNING:
+		common.FORBID_SKIP_IN_CHECKSUMS, common.FORBID_BUILD_COMMAND, common.HIDE_FIREJAIL_EXCLUDE_WARNING,
+		common.NO_CHECK, common.REPRODUCIBLE, common.SBOM, common.DEBUG_INFO,
+		common.BUILD_CACHE:
 		val, err := strconv.ParseBool(v)
 		if err != nil {
 			return nil, fmt.Errorf("expected boolean value, got: %s", v)
 		}
 		return val, nil
 
-	case common.IGNORE_PKG_UPDATES, common.FIREJAIL_EXCLUDE:
+	case common.IGNORE_PKG_UPDATES, common.FIREJAIL_EXCLUDE, common.PACKAGE_OPTIONS, common.SANDBOX_CAPABILITIES, common.SANDBOX_BINDS, common.SANDBOX_ENV_PASSTHROUGH:
 		if v == "" {
 			return []string{}, nil
 		}
@@ -314,7 +355,10 @@ func ConvertValue(key, v string) (any, error) {
 		}
 		return updates, nil
 
-	case common.ROOT_CMD, common.PAGER_STYLE, common.LOG_LEVEL:
+	case common.ROOT_CMD, common.PAGER_STYLE, common.LOG_LEVEL, common.CLEAN_ROOT_IMAGE,
+		common.CACHE_MAX_SIZE, common.CACHE_MAX_AGE,
+		common.BUILD_MEMORY_MAX, common.BUILD_CPU_MAX, common.BUILD_PIDS_MAX, common.BUILD_IO_WEIGHT, common.BUILD_TIMEOUT,
+		common.SANDBOX_SECCOMP_PROFILE:
 		return v, nil
 
 	default:
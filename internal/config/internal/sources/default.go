@@ -47,6 +47,7 @@ func (c *DefaultConfig) Load() (*koanf.Koanf, error) {
 		common.USE_ROOT_CMD:       constants.ConfigDefaultUseRootCmd,
 		common.PAGER_STYLE:        "native",
 		common.IGNORE_PKG_UPDATES: []string{},
+		common.PACKAGE_OPTIONS:    []string{},
 		common.LOG_LEVEL:          "info",
 		common.AUTO_PULL:          true,
 		common.REPO:               []types.Repo{},
@@ -31,5 +31,6 @@ type Paths struct {
 	CacheDir         string
 	RepoDir          string
 	PkgsDir          string
+	BuildCacheDir    string
 	DBPath           string
 }
@@ -31,6 +31,8 @@ import (
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/constants"
 	"go.stplr.dev/stplr/internal/osutils"
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/internal/sbom"
 	"go.stplr.dev/stplr/internal/utils"
 	"go.stplr.dev/stplr/pkg/distro"
 	"go.stplr.dev/stplr/pkg/staplerfile"
@@ -111,6 +113,16 @@ func (e *Copier) CopyOut(ctx context.Context, pkgs []commonbuild.BuiltDep) error
 		if err := e.copyOut(ctx, pkg.Path, filepath.Join(e.wd, name), 0, 0); err != nil {
 			return err
 		}
+		// detached signature of an archlinux package, SBOM and provenance
+		extra := append([]string{pkgsig.SignatureFile(pkg.Path)}, sbom.Files(pkg.Path)...)
+		for _, file := range extra {
+			if _, err := os.Stat(file); err != nil {
+				continue
+			}
+			if err := e.copyOut(ctx, file, filepath.Join(e.wd, filepath.Base(file)), 0, 0); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
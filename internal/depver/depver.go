@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package depver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.elara.ws/vercmp"
+)
+
+type Op string
+
+const (
+	OpAny Op = ""
+	OpEq  Op = "="
+	OpLt  Op = "<"
+	OpLe  Op = "<="
+	OpGe  Op = ">="
+	OpGt  Op = ">"
+)
+
+// Dep is a dependency with an optional version constraint,
+// e.g. "foo>=1.2".
+type Dep struct {
+	Name    string
+	Op      Op
+	Version string
+}
+
+// Parse parses a dependency. Besides "foo>=1.2" it accepts "foo >= 1.2"
+// and the Debian form "foo (>= 1.2)". Strings that don't look like
+// a constraint are returned as a bare name.
+func Parse(s string) Dep {
+	s = strings.TrimSpace(s)
+
+	i := strings.IndexAny(s, "<>=")
+	if i <= 0 {
+		return Dep{Name: s}
+	}
+
+	name := strings.TrimSpace(s[:i])
+	rest := s[i:]
+	if before, ok := strings.CutSuffix(name, "("); ok && strings.HasSuffix(rest, ")") {
+		name = strings.TrimSpace(before)
+		rest = strings.TrimSuffix(rest, ")")
+	}
+
+	var op Op
+	switch {
+	case strings.HasPrefix(rest, "<<"):
+		op, rest = OpLt, rest[2:]
+	case strings.HasPrefix(rest, ">>"):
+		op, rest = OpGt, rest[2:]
+	case strings.HasPrefix(rest, "<="), strings.HasPrefix(rest, "=<"):
+		op, rest = OpLe, rest[2:]
+	case strings.HasPrefix(rest, ">="), strings.HasPrefix(rest, "=>"):
+		op, rest = OpGe, rest[2:]
+	case strings.HasPrefix(rest, "=="):
+		op, rest = OpEq, rest[2:]
+	default:
+		op, rest = Op(rest[:1]), rest[1:]
+	}
+
+	version := strings.TrimSpace(rest)
+	if name == "" || version == "" || strings.ContainsAny(name, " \t") || strings.ContainsAny(version, " \t<>=()") {
+		return Dep{Name: s}
+	}
+
+	return Dep{Name: name, Op: op, Version: version}
+}
+
+// Names returns deps without their version constraints.
+func Names(deps []string) []string {
+	out := make([]string, 0, len(deps))
+	for _, d := range deps {
+		out = append(out, Parse(d).Name)
+	}
+	return out
+}
+
+func (d Dep) String() string {
+	return d.Name + string(d.Op) + d.Version
+}
+
+// Format returns the dependency in the native syntax of pkgFormat.
+func (d Dep) Format(pkgFormat string) string {
+	if d.Op == OpAny {
+		return d.Name
+	}
+
+	switch pkgFormat {
+	case "deb":
+		op := string(d.Op)
+		switch d.Op {
+		case OpLt:
+			op = "<<"
+		case OpGt:
+			op = ">>"
+		}
+		return fmt.Sprintf("%s (%s %s)", d.Name, op, d.Version)
+	case "rpm":
+		return fmt.Sprintf("%s %s %s", d.Name, d.Op, d.Version)
+	default:
+		// apk and archlinux use the same syntax as Staplerfiles
+		return d.String()
+	}
+}
+
+// Satisfies reports whether version meets the constraint. Epoch and
+// release are only compared when the constraint specifies them, so
+// "foo=1.2" is satisfied by "1:1.2-3".
+func (d Dep) Satisfies(version string) bool {
+	if d.Op == OpAny {
+		return true
+	}
+
+	cmp := compare(version, d.Version)
+
+	switch d.Op {
+	case OpEq:
+		return cmp == 0
+	case OpLt:
+		return cmp < 0
+	case OpLe:
+		return cmp <= 0
+	case OpGe:
+		return cmp >= 0
+	case OpGt:
+		return cmp > 0
+	}
+
+	return false
+}
+
+type evr struct {
+	epoch   int
+	version string
+	release string
+}
+
+func splitEVR(s string) (v evr, hasEpoch, hasRelease bool) {
+	if e, rest, ok := strings.Cut(s, ":"); ok {
+		if n, err := strconv.Atoi(e); err == nil {
+			v.epoch = n
+			hasEpoch = true
+			s = rest
+		}
+	}
+	if i := strings.LastIndex(s, "-"); i != -1 {
+		v.release = s[i+1:]
+		hasRelease = true
+		s = s[:i]
+	}
+	v.version = s
+	return v, hasEpoch, hasRelease
+}
+
+// compare compares an actual version with a wanted one, ignoring
+// the parts that wanted doesn't have.
+func compare(actual, wanted string) int {
+	a, _, _ := splitEVR(actual)
+	w, hasEpoch, hasRelease := splitEVR(wanted)
+
+	if hasEpoch && a.epoch != w.epoch {
+		if a.epoch > w.epoch {
+			return 1
+		}
+		return -1
+	}
+
+	if cmp := vercmp.Compare(a.version, w.version); cmp != 0 {
+		return cmp
+	}
+
+	if hasRelease {
+		return vercmp.Compare(a.release, w.release)
+	}
+
+	return 0
+}
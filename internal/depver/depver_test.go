@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package depver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.stplr.dev/stplr/internal/depver"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want depver.Dep
+	}{
+		{"foo", depver.Dep{Name: "foo"}},
+		{"foo>=1.2", depver.Dep{Name: "foo", Op: depver.OpGe, Version: "1.2"}},
+		{"foo >= 1.2", depver.Dep{Name: "foo", Op: depver.OpGe, Version: "1.2"}},
+		{"foo (>= 1.2)", depver.Dep{Name: "foo", Op: depver.OpGe, Version: "1.2"}},
+		{"foo (<< 2)", depver.Dep{Name: "foo", Op: depver.OpLt, Version: "2"}},
+		{"foo<=1:2.0-3", depver.Dep{Name: "foo", Op: depver.OpLe, Version: "1:2.0-3"}},
+		{"foo=1.2", depver.Dep{Name: "foo", Op: depver.OpEq, Version: "1.2"}},
+		{"foo>1", depver.Dep{Name: "foo", Op: depver.OpGt, Version: "1"}},
+		{"foo<1", depver.Dep{Name: "foo", Op: depver.OpLt, Version: "1"}},
+		{"repo/foo>=1", depver.Dep{Name: "repo/foo", Op: depver.OpGe, Version: "1"}},
+		{"libc.so.6()(64bit)", depver.Dep{Name: "libc.so.6()(64bit)"}},
+		{"(foo >= 1 or bar)", depver.Dep{Name: "(foo >= 1 or bar)"}},
+		{"foo>=", depver.Dep{Name: "foo>="}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, depver.Parse(tt.in))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		dep    string
+		format string
+		want   string
+	}{
+		{"foo", "deb", "foo"},
+		{"foo>=1.2", "deb", "foo (>= 1.2)"},
+		{"foo<1.2", "deb", "foo (<< 1.2)"},
+		{"foo>1.2", "deb", "foo (>> 1.2)"},
+		{"foo=1.2", "deb", "foo (= 1.2)"},
+		{"foo>=1.2", "rpm", "foo >= 1.2"},
+		{"foo<1.2", "rpm", "foo < 1.2"},
+		{"foo>=1.2", "apk", "foo>=1.2"},
+		{"foo<=1.2", "archlinux", "foo<=1.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.dep, func(t *testing.T) {
+			assert.Equal(t, tt.want, depver.Parse(tt.dep).Format(tt.format))
+		})
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		dep     string
+		version string
+		want    bool
+	}{
+		{"foo", "0.1", true},
+		{"foo>=1.2", "1.2", true},
+		{"foo>=1.2", "1.10", true},
+		{"foo>=1.2", "1.1", false},
+		{"foo>1.2", "1.2", false},
+		{"foo<2", "1.9", true},
+		{"foo<=2", "2", true},
+		{"foo=1.2", "1.2-3", true},
+		{"foo=1.2", "1:1.2-3", true},
+		{"foo=1.2-3", "1.2-4", false},
+		{"foo>=1.2-3", "1.2-4", true},
+		{"foo>=2:1.0", "1:3.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dep+"@"+tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.want, depver.Parse(tt.dep).Satisfies(tt.version))
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, []string{"foo", "bar"}, depver.Names([]string{"foo>=1", "bar"}))
+}
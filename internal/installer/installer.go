@@ -21,6 +21,10 @@ package installer
 import (
 	"context"
 
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/depver"
 	"go.stplr.dev/stplr/internal/manager"
 )
 
@@ -64,25 +68,81 @@ func (i *Installer) InstallLocal(ctx context.Context, paths []string, opts *mana
 	return i.mgr.InstallLocal(i.modifyOpts(opts), paths...)
 }
 
+// Install installs pkgs from the system repositories. Package managers
+// don't share a syntax for version constraints, so they are checked
+// against the installed versions afterwards instead.
 func (i *Installer) Install(ctx context.Context, pkgs []string, opts *manager.Opts) error {
-	return i.mgr.Install(i.modifyOpts(opts), pkgs...)
+	err := i.mgr.Install(i.modifyOpts(opts), depver.Names(pkgs)...)
+	if err != nil {
+		return err
+	}
+	return i.checkInstalled(pkgs)
+}
+
+// checkInstalled returns an error if the installed version of one
+// of pkgs doesn't satisfy its version constraint.
+func (i *Installer) checkInstalled(pkgs []string) error {
+	var installedVersions map[string]string
+
+	for _, dep := range pkgs {
+		d := depver.Parse(dep)
+		if d.Op == depver.OpAny {
+			continue
+		}
+
+		if installedVersions == nil {
+			var err error
+			installedVersions, err = i.mgr.ListInstalled(nil)
+			if err != nil {
+				return err
+			}
+		}
+		// a name missing from the list is provided by another package,
+		// whose version says nothing about the constraint
+		version, ok := installedVersions[d.Name]
+		if ok && !d.Satisfies(version) {
+			return errors.NewI18nError(gotext.Get("%s %s does not satisfy %s%s", d.Name, version, d.Op, d.Version))
+		}
+	}
+
+	return nil
 }
 
 func (i *Installer) Remove(ctx context.Context, pkgs []string, opts *manager.Opts) error {
-	return i.mgr.Remove(i.modifyOpts(opts), pkgs...)
+	return i.mgr.Remove(i.modifyOpts(opts), depver.Names(pkgs)...)
 }
 
+// RemoveAlreadyInstalled returns pkgs that are not installed or whose
+// installed version doesn't satisfy the version constraint.
 func (i *Installer) RemoveAlreadyInstalled(ctx context.Context, pkgs []string) ([]string, error) {
 	filteredPackages := []string{}
 
+	var installedVersions map[string]string
+
 	for _, dep := range pkgs {
-		installed, err := i.mgr.IsInstalled(dep)
-		if err != nil {
-			return nil, err
-		}
-		if installed {
-			continue
+		d := depver.Parse(dep)
+
+		if d.Op == depver.OpAny {
+			installed, err := i.mgr.IsInstalled(d.Name)
+			if err != nil {
+				return nil, err
+			}
+			if installed {
+				continue
+			}
+		} else {
+			if installedVersions == nil {
+				var err error
+				installedVersions, err = i.mgr.ListInstalled(nil)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if version, ok := installedVersions[d.Name]; ok && d.Satisfies(version) {
+				continue
+			}
 		}
+
 		filteredPackages = append(filteredPackages, dep)
 	}
 
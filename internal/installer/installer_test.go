@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package installer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/installer"
+	"go.stplr.dev/stplr/internal/manager"
+)
+
+type fakeManager struct {
+	manager.Manager
+	versions  map[string]string
+	installed []string
+}
+
+func (m *fakeManager) Install(opts *manager.Opts, pkgs ...string) error {
+	m.installed = append(m.installed, pkgs...)
+	return nil
+}
+
+func (m *fakeManager) ListInstalled(opts *manager.Opts) (map[string]string, error) {
+	return m.versions, nil
+}
+
+func TestInstallChecksConstraints(t *testing.T) {
+	mgr := &fakeManager{versions: map[string]string{"foo": "1.0", "bar": "2.1"}}
+	i := installer.New(mgr, false, "")
+
+	err := i.Install(context.Background(), []string{"foo>=1.2", "bar>=2"}, nil)
+	require.EqualError(t, err, "foo 1.0 does not satisfy >=1.2")
+	assert.Equal(t, []string{"foo", "bar"}, mgr.installed)
+
+	mgr.versions["foo"] = "1.2"
+	require.NoError(t, i.Install(context.Background(), []string{"foo>=1.2", "bar>=2", "baz", "virtual>=1"}, nil))
+}
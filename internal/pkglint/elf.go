@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkglint
+
+import (
+	"debug/elf"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+var elfMagic = []byte(elf.ELFMAG)
+
+// debugDirs hold detached debug info, which keeps its symbols.
+var debugDirs = []string{"/usr/lib/debug/"}
+
+func (l *Linter) checkELF(name string, r io.ReaderAt) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		slog.Debug("not a valid ELF file", "path", name, "err", err)
+		return
+	}
+	defer f.Close()
+
+	if f.Type != elf.ET_EXEC && f.Type != elf.ET_DYN {
+		return
+	}
+
+	for _, tag := range []elf.DynTag{elf.DT_RPATH, elf.DT_RUNPATH} {
+		paths, err := f.DynString(tag)
+		if err != nil {
+			continue
+		}
+		for _, p := range paths {
+			for _, dir := range filepath.SplitList(p) {
+				if l.isBuildDir(dir) {
+					l.report(CheckRPath, name, gotext.Get("RPATH %q points into the build directory", dir))
+				}
+			}
+		}
+	}
+
+	for _, dir := range debugDirs {
+		if strings.HasPrefix(name, dir) {
+			return
+		}
+	}
+	if f.Section(".symtab") != nil {
+		l.report(CheckUnstripped, name, gotext.Get("binary is not stripped"))
+	}
+}
+
+func (l *Linter) isBuildDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, b := range l.buildDirs {
+		if b == "" {
+			continue
+		}
+		if dir == b || strings.HasPrefix(dir, b+"/") {
+			return true
+		}
+	}
+	return false
+}
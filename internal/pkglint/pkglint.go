@@ -0,0 +1,274 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package pkglint checks the contents and metadata of built packages,
+// similar to rpmlint or namcap.
+package pkglint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Names of the checks. They are the keys of the lint table
+// of a repo config.
+const (
+	CheckNonFHS        = "non-fhs"
+	CheckWorldWritable = "world-writable"
+	CheckSetuid        = "setuid"
+	CheckRPath         = "rpath"
+	CheckUnstripped    = "unstripped"
+	CheckLicense       = "license"
+	CheckEmpty         = "empty"
+)
+
+// Levels maps checks to their severity.
+type Levels map[string]Severity
+
+// DefaultLevels returns the severity of every check.
+func DefaultLevels() Levels {
+	return Levels{
+		CheckNonFHS:        SeverityWarning,
+		CheckWorldWritable: SeverityError,
+		CheckSetuid:        SeverityWarning,
+		CheckRPath:         SeverityError,
+		CheckUnstripped:    SeverityWarning,
+		CheckLicense:       SeverityWarning,
+		CheckEmpty:         SeverityWarning,
+	}
+}
+
+// ParseLevels returns the default levels with the ones from a repo
+// config applied.
+func ParseLevels(cfg map[string]string) (Levels, error) {
+	levels := DefaultLevels()
+	for check, value := range cfg {
+		if _, ok := levels[check]; !ok {
+			return nil, fmt.Errorf("unknown lint check %q", check)
+		}
+		switch s := Severity(value); s {
+		case SeverityOff, SeverityWarning, SeverityError:
+			levels[check] = s
+		default:
+			return nil, fmt.Errorf("invalid severity %q for lint check %q", value, check)
+		}
+	}
+	return levels, nil
+}
+
+type Finding struct {
+	Check    string
+	Severity Severity
+	// Path is the file in the package, if the finding is about a file.
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	if f.Path == "" {
+		return fmt.Sprintf("%s: %s: %s", f.Severity, f.Check, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", f.Severity, f.Check, f.Path, f.Message)
+}
+
+// HasErrors reports whether any of findings is an error.
+func HasErrors(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool {
+		return f.Severity == SeverityError
+	})
+}
+
+// Metadata holds the package fields that are checked.
+type Metadata struct {
+	Name string
+	// License is empty if the package has no license or the format
+	// doesn't store it.
+	License string
+}
+
+// Linter collects findings for the files of a single package.
+type Linter struct {
+	levels Levels
+	// buildDirs are the directories of the build, which must not
+	// be referenced by the installed files.
+	buildDirs []string
+
+	findings   []Finding
+	nonFHS     map[string]bool
+	files      int
+	hasLicense bool
+}
+
+func New(levels Levels, buildDirs []string) *Linter {
+	if levels == nil {
+		levels = DefaultLevels()
+	}
+	return &Linter{
+		levels:    levels,
+		buildDirs: buildDirs,
+		nonFHS:    make(map[string]bool),
+	}
+}
+
+func (l *Linter) report(check, path, msg string) {
+	s, ok := l.levels[check]
+	if !ok || s == SeverityOff {
+		return
+	}
+	l.findings = append(l.findings, Finding{
+		Check:    check,
+		Severity: s,
+		Path:     path,
+		Message:  msg,
+	})
+}
+
+// CheckFile checks a file installed at name. r is the contents
+// of regular files and nil for other ones.
+func (l *Linter) CheckFile(name string, mode fs.FileMode, r io.Reader) error {
+	name = path.Join("/", name)
+
+	l.checkFHS(name)
+
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		return nil
+	case mode.IsDir():
+		if mode.Perm()&0o002 != 0 && mode&fs.ModeSticky == 0 {
+			l.report(CheckWorldWritable, name, gotext.Get("directory is world-writable"))
+		}
+		return nil
+	}
+
+	l.files++
+	if isLicenseFile(name) {
+		l.hasLicense = true
+	}
+
+	if mode.Perm()&0o002 != 0 {
+		l.report(CheckWorldWritable, name, gotext.Get("file is world-writable"))
+	}
+	if mode&fs.ModeSetuid != 0 {
+		l.report(CheckSetuid, name, gotext.Get("file is setuid"))
+	}
+	if mode&fs.ModeSetgid != 0 {
+		l.report(CheckSetuid, name, gotext.Get("file is setgid"))
+	}
+
+	if r == nil || !mode.IsRegular() {
+		return nil
+	}
+
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(elfMagic))
+	if !bytes.Equal(magic, elfMagic) {
+		return nil
+	}
+	data, err := io.ReadAll(br)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	l.checkELF(name, bytes.NewReader(data))
+
+	return nil
+}
+
+// Finish runs the checks of the whole package and returns all findings.
+func (l *Linter) Finish(meta Metadata) []Finding {
+	if l.files == 0 {
+		l.report(CheckEmpty, "", gotext.Get("package %s has no files", meta.Name))
+	}
+	if meta.License != "" && l.files > 0 && !l.hasLicense {
+		l.report(CheckLicense, "", gotext.Get("license is %q, but the package has no license file", meta.License))
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		return l.findings[i].Path < l.findings[j].Path
+	})
+	return l.findings
+}
+
+// fhsDirs are the top level directories allowed by the FHS. Directories
+// with a list only allow the listed subdirectories.
+var fhsDirs = map[string][]string{
+	"bin":    nil,
+	"boot":   nil,
+	"etc":    nil,
+	"lib":    nil,
+	"lib32":  nil,
+	"lib64":  nil,
+	"libx32": nil,
+	"opt":    nil,
+	"sbin":   nil,
+	"srv":    nil,
+	"var":    nil,
+	"usr": {
+		"bin", "games", "include", "lib", "lib32", "lib64", "libx32",
+		"libexec", "sbin", "share", "src",
+	},
+}
+
+func (l *Linter) checkFHS(name string) {
+	parts := strings.Split(strings.TrimPrefix(name, "/"), "/")
+	if parts[0] == "" {
+		return
+	}
+
+	bad := ""
+	allowed, ok := fhsDirs[parts[0]]
+	switch {
+	case !ok:
+		bad = "/" + parts[0]
+	case allowed != nil && len(parts) > 1 && !slices.Contains(allowed, parts[1]):
+		bad = "/" + parts[0] + "/" + parts[1]
+	}
+	if bad == "" || l.nonFHS[bad] {
+		return
+	}
+
+	// report every directory once, not every file in it
+	l.nonFHS[bad] = true
+	l.report(CheckNonFHS, bad, gotext.Get("directory is not allowed by the FHS"))
+}
+
+var licenseFileRe = regexp.MustCompile(`(?i)^(licen[cs]e|copying|copyright|notice)`)
+
+func isLicenseFile(name string) bool {
+	if !strings.HasPrefix(name, "/usr/share/licenses/") && !strings.HasPrefix(name, "/usr/share/doc/") {
+		return false
+	}
+	return licenseFileRe.MatchString(path.Base(name))
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkglint_test
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/apk"
+	_ "github.com/goreleaser/nfpm/v2/arch"
+	_ "github.com/goreleaser/nfpm/v2/deb"
+	"github.com/goreleaser/nfpm/v2/files"
+	_ "github.com/goreleaser/nfpm/v2/rpm-lowmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/pkglint"
+)
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+// makeELF returns a minimal shared object with a symbol table
+// and a DT_RUNPATH entry, if runpath is set.
+func makeELF(t *testing.T, runpath string) []byte {
+	t.Helper()
+
+	shstrtab := []byte("\x00.shstrtab\x00.dynstr\x00.dynamic\x00.symtab\x00")
+	dynstr := []byte("\x00" + runpath + "\x00")
+	var dynamic bytes.Buffer
+	if runpath != "" {
+		require.NoError(t, binary.Write(&dynamic, binary.LittleEndian, elf.Dyn64{Tag: int64(elf.DT_RUNPATH), Val: 1}))
+	}
+	require.NoError(t, binary.Write(&dynamic, binary.LittleEndian, elf.Dyn64{Tag: int64(elf.DT_NULL)}))
+	symtab := make([]byte, 24)
+
+	const hdrSize = 64
+	off := uint64(hdrSize)
+	var data bytes.Buffer
+	add := func(b []byte) (uint64, uint64) {
+		o := off + uint64(data.Len())
+		data.Write(b)
+		return o, uint64(len(b))
+	}
+	shstrOff, shstrSize := add(shstrtab)
+	dynstrOff, dynstrSize := add(dynstr)
+	dynOff, dynSize := add(dynamic.Bytes())
+	symOff, symSize := add(symtab)
+
+	sections := []elf.Section64{
+		{},
+		{Name: 1, Type: uint32(elf.SHT_STRTAB), Off: shstrOff, Size: shstrSize, Addralign: 1},
+		{Name: 11, Type: uint32(elf.SHT_STRTAB), Off: dynstrOff, Size: dynstrSize, Addralign: 1},
+		{Name: 19, Type: uint32(elf.SHT_DYNAMIC), Off: dynOff, Size: dynSize, Link: 2, Addralign: 8, Entsize: 16},
+		{Name: 28, Type: uint32(elf.SHT_SYMTAB), Off: symOff, Size: symSize, Link: 2, Addralign: 8, Entsize: 24},
+	}
+
+	hdr := elf.Header64{
+		Type:      uint16(elf.ET_DYN),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     off + uint64(data.Len()),
+		Ehsize:    hdrSize,
+		Phentsize: 56,
+		Shentsize: 64,
+		Shnum:     uint16(len(sections)),
+		Shstrndx:  1,
+	}
+	copy(hdr.Ident[:], elf.ELFMAG)
+	hdr.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	hdr.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	hdr.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+
+	var out bytes.Buffer
+	require.NoError(t, binary.Write(&out, binary.LittleEndian, hdr))
+	out.Write(data.Bytes())
+	require.NoError(t, binary.Write(&out, binary.LittleEndian, sections))
+	return out.Bytes()
+}
+
+func buildPackage(t *testing.T, format string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:       "hello",
+		Arch:       "amd64",
+		Version:    "1.0.0",
+		Release:    "1",
+		License:    "MIT",
+		Maintainer: "Builder <builder@example.com>",
+		Overridables: nfpm.Overridables{
+			Contents: files.Contents{
+				{
+					Source:      writeFile(t, dir, "hello", makeELF(t, "/var/cache/stplr/pkgs/hello/src/lib")),
+					Destination: "/usr/bin/hello",
+					FileInfo:    &files.ContentFileInfo{Mode: 0o755},
+				},
+				{
+					Source:      writeFile(t, dir, "helper", []byte("#!/bin/sh\n")),
+					Destination: "/usr/bin/helper",
+					FileInfo:    &files.ContentFileInfo{Mode: 0o4755},
+				},
+				{
+					Source:      writeFile(t, dir, "data", []byte("data\n")),
+					Destination: "/usr/share/hello/data",
+					FileInfo:    &files.ContentFileInfo{Mode: 0o666},
+				},
+				{
+					Source:      writeFile(t, dir, "rc", []byte("rc\n")),
+					Destination: "/home/user/.hellorc",
+					FileInfo:    &files.ContentFileInfo{Mode: 0o644},
+				},
+			},
+		},
+	})
+
+	packager, err := nfpm.Get(format)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, packager.ConventionalFileName(info))
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, packager.Package(info, f))
+	require.NoError(t, f.Close())
+
+	return path
+}
+
+func checks(findings []pkglint.Finding) map[string]string {
+	out := make(map[string]string)
+	for _, f := range findings {
+		out[f.Check+" "+f.Path] = string(f.Severity)
+	}
+	return out
+}
+
+func TestLintFile(t *testing.T) {
+	for _, format := range []string{"rpm", "deb", "apk", "archlinux"} {
+		t.Run(format, func(t *testing.T) {
+			path := buildPackage(t, format)
+
+			findings, err := pkglint.LintFile(path, format, pkglint.DefaultLevels(), []string{"/var/cache/stplr/pkgs"})
+			require.NoError(t, err)
+
+			got := checks(findings)
+			assert.Equal(t, "warning", got["non-fhs /home"])
+			assert.Equal(t, "error", got["world-writable /usr/share/hello/data"])
+			assert.Equal(t, "warning", got["setuid /usr/bin/helper"])
+			assert.Equal(t, "warning", got["unstripped /usr/bin/hello"])
+			assert.Equal(t, "error", got["rpath /usr/bin/hello"])
+			if format != "deb" {
+				assert.Equal(t, "warning", got["license "])
+			}
+			assert.NotContains(t, got, "empty ")
+			assert.True(t, pkglint.HasErrors(findings))
+		})
+	}
+}
+
+func TestLintFileLevels(t *testing.T) {
+	path := buildPackage(t, "rpm")
+
+	levels, err := pkglint.ParseLevels(map[string]string{
+		"world-writable": "off",
+		"unstripped":     "error",
+	})
+	require.NoError(t, err)
+
+	findings, err := pkglint.LintFile(path, "rpm", levels, nil)
+	require.NoError(t, err)
+
+	got := checks(findings)
+	assert.NotContains(t, got, "world-writable /usr/share/hello/data")
+	assert.Equal(t, "error", got["unstripped /usr/bin/hello"])
+}
+
+func TestParseLevels(t *testing.T) {
+	_, err := pkglint.ParseLevels(map[string]string{"unknown": "error"})
+	assert.Error(t, err)
+
+	_, err = pkglint.ParseLevels(map[string]string{"setuid": "fatal"})
+	assert.Error(t, err)
+}
+
+func TestLinter(t *testing.T) {
+	l := pkglint.New(nil, []string{"/var/cache/stplr/pkgs/hello/src"})
+
+	require.NoError(t, l.CheckFile("/usr", os.ModeDir|0o755, nil))
+	require.NoError(t, l.CheckFile("/usr/local/bin/hello", 0o755, bytes.NewReader([]byte("#!/bin/sh\n"))))
+	require.NoError(t, l.CheckFile("/usr/local/bin/other", 0o755, nil))
+	require.NoError(t, l.CheckFile("/tmp", os.ModeDir|os.ModeSticky|0o777, nil))
+	require.NoError(t, l.CheckFile("/usr/share/licenses/hello/LICENSE", 0o644, nil))
+
+	got := checks(l.Finish(pkglint.Metadata{Name: "hello", License: "MIT"}))
+	assert.Equal(t, map[string]string{
+		"non-fhs /usr/local": "warning",
+		"non-fhs /tmp":       "warning",
+	}, got)
+
+	empty := pkglint.New(nil, nil)
+	require.NoError(t, empty.CheckFile("/usr/share/hello", os.ModeDir|0o755, nil))
+	assert.Equal(t, map[string]string{"empty ": "warning"}, checks(empty.Finish(pkglint.Metadata{Name: "hello"})))
+}
@@ -0,0 +1,302 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkglint
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported package format")
+
+// LintFile checks the package at path, which is in the given format.
+func LintFile(path, format string, levels Levels, buildDirs []string) ([]Finding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	l := New(levels, buildDirs)
+
+	var meta Metadata
+	switch format {
+	case "rpm":
+		meta, err = l.readRPM(f)
+	case "deb":
+		meta, err = l.readDeb(f)
+	case "apk":
+		meta, err = l.readAPK(f)
+	case "archlinux":
+		meta, err = l.readArchLinux(f)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	return l.Finish(meta), nil
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+// decompress detects the compression of r by its magic bytes.
+func decompress(r io.Reader) (io.Reader, func(), error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(6)
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() { _ = zr.Close() }, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, zr.Close, nil
+	case bytes.HasPrefix(magic, xzMagic):
+		zr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, func() {}, nil
+	}
+
+	return br, func() {}, nil
+}
+
+// checkTar checks the files of a tar archive. Files for which skip
+// returns true are passed to meta instead, if it is not nil.
+func (l *Linter) checkTar(tr *tar.Reader, skip func(name string) bool, meta func(name string, r io.Reader) error) error {
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimPrefix(hdr.Name, "./")
+		if skip != nil && skip(name) {
+			if meta != nil {
+				if err := meta(name, tr); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		var r io.Reader
+		if hdr.Typeflag == tar.TypeReg {
+			r = tr
+		}
+		if err := l.CheckFile(name, hdr.FileInfo().Mode(), r); err != nil {
+			return err
+		}
+	}
+}
+
+// parsePkgInfo reads the name and license from the .PKGINFO file
+// of apk and archlinux packages.
+func parsePkgInfo(r io.Reader, meta *Metadata) error {
+	var licenses []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "pkgname":
+			meta.Name = value
+		case "license":
+			licenses = append(licenses, value)
+		}
+	}
+	meta.License = strings.Join(licenses, ", ")
+	return sc.Err()
+}
+
+func isPkgInfo(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+func (l *Linter) readArchLinux(r io.Reader) (Metadata, error) {
+	var meta Metadata
+
+	dr, closeFn, err := decompress(r)
+	if err != nil {
+		return meta, err
+	}
+	defer closeFn()
+
+	err = l.checkTar(tar.NewReader(dr), isPkgInfo, func(name string, r io.Reader) error {
+		if name != ".PKGINFO" {
+			return nil
+		}
+		return parsePkgInfo(r, &meta)
+	})
+	return meta, err
+}
+
+// readAPK reads an apk package, which consists of concatenated gzip
+// streams: an optional signature, the control data and the files.
+func (l *Linter) readAPK(r io.Reader) (Metadata, error) {
+	var meta Metadata
+
+	br := bufio.NewReader(r)
+	gz, err := gzip.NewReader(br)
+	if err != nil {
+		return meta, err
+	}
+	defer gz.Close()
+
+	for {
+		gz.Multistream(false)
+		err := l.checkTar(tar.NewReader(gz), isPkgInfo, func(name string, r io.Reader) error {
+			if name != ".PKGINFO" {
+				return nil
+			}
+			return parsePkgInfo(r, &meta)
+		})
+		if err != nil {
+			return meta, err
+		}
+		if _, err := io.Copy(io.Discard, gz); err != nil {
+			return meta, err
+		}
+
+		err = gz.Reset(br)
+		if errors.Is(err, io.EOF) {
+			return meta, nil
+		}
+		if err != nil {
+			return meta, err
+		}
+	}
+}
+
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+// readDeb reads a deb package, an ar archive with the files
+// in the data.tar member.
+func (l *Linter) readDeb(r io.Reader) (Metadata, error) {
+	var meta Metadata
+
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != arMagic {
+		return meta, errors.New("not an ar archive")
+	}
+
+	hdr := make([]byte, arHeaderSize)
+	for {
+		if _, err := io.ReadFull(br, hdr); err != nil {
+			if errors.Is(err, io.EOF) {
+				return meta, nil
+			}
+			return meta, err
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return meta, errors.New("bad ar member size")
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(hdr[0:16])), "/")
+		member := &io.LimitedReader{R: br, N: size}
+
+		switch {
+		case strings.HasPrefix(name, "control.tar"):
+			err = readDebControl(member, &meta)
+		case strings.HasPrefix(name, "data.tar"):
+			err = l.readDebData(member)
+		}
+		if err != nil {
+			return meta, fmt.Errorf("reading %s: %w", name, err)
+		}
+
+		// members are aligned to 2 bytes
+		if _, err := io.Copy(io.Discard, io.LimitReader(br, member.N+size%2)); err != nil {
+			return meta, err
+		}
+	}
+}
+
+func (l *Linter) readDebData(r io.Reader) error {
+	dr, closeFn, err := decompress(r)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+	return l.checkTar(tar.NewReader(dr), nil, nil)
+}
+
+// readDebControl reads the package name. Debian packages don't keep
+// the license in the control file.
+func readDebControl(r io.Reader, meta *Metadata) error {
+	dr, closeFn, err := decompress(r)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	tr := tar.NewReader(dr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if strings.TrimPrefix(hdr.Name, "./") != "control" {
+			continue
+		}
+		sc := bufio.NewScanner(tr)
+		for sc.Scan() {
+			if name, ok := strings.CutPrefix(sc.Text(), "Package:"); ok {
+				meta.Name = strings.TrimSpace(name)
+			}
+		}
+		return sc.Err()
+	}
+}
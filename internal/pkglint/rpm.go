@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkglint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cavaliergopher/cpio"
+)
+
+const (
+	rpmLeadSize = 96
+
+	rpmTagName    = 1000
+	rpmTagLicense = 1014
+	rpmTypeString = 6
+
+	rpmMaxIndexEntries = 1 << 16
+	rpmMaxHeaderData   = 256 << 20
+)
+
+var rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+
+type rpmHeader struct {
+	index []byte
+	data  []byte
+}
+
+// readRPMHeader reads a header structure and returns it along with
+// its size in the file.
+func readRPMHeader(r io.Reader) (*rpmHeader, int, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, 0, err
+	}
+	if !bytes.Equal(intro[:4], rpmHeaderMagic) {
+		return nil, 0, errors.New("bad rpm header magic")
+	}
+
+	n := binary.BigEndian.Uint32(intro[8:12])
+	size := binary.BigEndian.Uint32(intro[12:16])
+	if n > rpmMaxIndexEntries || size > rpmMaxHeaderData {
+		return nil, 0, errors.New("rpm header is too large")
+	}
+
+	rest := make([]byte, 16*int(n)+int(size))
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, 0, err
+	}
+
+	return &rpmHeader{index: rest[:16*n], data: rest[16*n:]}, len(intro) + len(rest), nil
+}
+
+func (h *rpmHeader) string(tag uint32) string {
+	for e := h.index; len(e) >= 16; e = e[16:] {
+		if binary.BigEndian.Uint32(e[0:4]) != tag || binary.BigEndian.Uint32(e[4:8]) != rpmTypeString {
+			continue
+		}
+		offset := binary.BigEndian.Uint32(e[8:12])
+		if int(offset) >= len(h.data) {
+			return ""
+		}
+		s, _, _ := strings.Cut(string(h.data[offset:]), "\x00")
+		return s
+	}
+	return ""
+}
+
+// readRPM reads an rpm package: the lead, the signature header padded
+// to 8 bytes, the main header and the compressed cpio payload.
+func (l *Linter) readRPM(r io.Reader) (Metadata, error) {
+	var meta Metadata
+
+	if _, err := io.CopyN(io.Discard, r, rpmLeadSize); err != nil {
+		return meta, err
+	}
+
+	_, size, err := readRPMHeader(r)
+	if err != nil {
+		return meta, err
+	}
+	if pad := (8 - size%8) % 8; pad > 0 {
+		if _, err := io.CopyN(io.Discard, r, int64(pad)); err != nil {
+			return meta, err
+		}
+	}
+
+	h, _, err := readRPMHeader(r)
+	if err != nil {
+		return meta, err
+	}
+	meta.Name = h.string(rpmTagName)
+	meta.License = h.string(rpmTagLicense)
+
+	dr, closeFn, err := decompress(r)
+	if err != nil {
+		return meta, err
+	}
+	defer closeFn()
+
+	cr := cpio.NewReader(dr)
+	for {
+		hdr, err := cr.Next()
+		if errors.Is(err, io.EOF) {
+			return meta, nil
+		}
+		if err != nil {
+			return meta, err
+		}
+
+		var body io.Reader
+		if hdr.Mode.IsRegular() {
+			body = cr
+		}
+		if err := l.CheckFile(strings.TrimPrefix(hdr.Name, "."), hdr.FileInfo().Mode(), body); err != nil {
+			return meta, err
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkgsig
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // apk signatures use SHA1
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const apkSignPrefix = ".SIGN.RSA."
+
+func readRSAPublicKey(file string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in key file")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		rsaKey, pkcs1Err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if pkcs1Err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		return rsaKey, nil
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// countingReader counts the bytes consumed by a gzip reader. It
+// implements io.ByteReader, so that gzip doesn't read ahead.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+// verifyAPK checks the signature of an apk package. It is the first
+// gzip stream of the package and signs the SHA1 digest of the second,
+// control stream. The control stream holds the SHA-256 digest of the
+// third, data stream in .PKGINFO, which is checked as well.
+func verifyAPK(r io.ReaderAt, size int64, key *rsa.PublicKey) (string, error) {
+	cr := &countingReader{r: bufio.NewReader(io.NewSectionReader(r, 0, size))}
+
+	gz, err := gzip.NewReader(cr)
+	if err != nil {
+		return "", fmt.Errorf("reading apk: %w", err)
+	}
+	gz.Multistream(false)
+
+	hdr, err := tar.NewReader(gz).Next()
+	if err != nil {
+		return "", fmt.Errorf("reading apk: %w", err)
+	}
+	if !strings.HasPrefix(hdr.Name, apkSignPrefix) {
+		return "", ErrNotSigned
+	}
+	sig, err := io.ReadAll(io.LimitReader(gz, hdr.Size))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(io.Discard, gz); err != nil {
+		return "", err
+	}
+	controlStart := cr.n
+
+	if err := gz.Reset(cr); err != nil {
+		return "", fmt.Errorf("reading apk control: %w", err)
+	}
+	gz.Multistream(false)
+	dataHash, err := readAPKDataHash(gz)
+	if err != nil {
+		return "", fmt.Errorf("reading apk control: %w", err)
+	}
+	controlEnd := cr.n
+
+	h := sha1.New() //nolint:gosec
+	if _, err := io.Copy(h, io.NewSectionReader(r, controlStart, controlEnd-controlStart)); err != nil {
+		return "", err
+	}
+
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA1, h.Sum(nil), sig); err != nil {
+		return "", fmt.Errorf("bad signature: %w", err)
+	}
+
+	if dataHash == "" {
+		return "", errors.New("apk control has no datahash")
+	}
+	dh := sha256.New()
+	if _, err := io.Copy(dh, io.NewSectionReader(r, controlEnd, size-controlEnd)); err != nil {
+		return "", err
+	}
+	if !strings.EqualFold(hex.EncodeToString(dh.Sum(nil)), dataHash) {
+		return "", errors.New("apk data doesn't match its datahash")
+	}
+
+	return strings.TrimPrefix(hdr.Name, apkSignPrefix), nil
+}
+
+// readAPKDataHash reads the control stream from r up to its end and
+// returns the datahash of its .PKGINFO.
+func readAPKDataHash(r io.Reader) (string, error) {
+	var dataHash string
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if hdr.Name != ".PKGINFO" {
+			continue
+		}
+		sc := bufio.NewScanner(tr)
+		for sc.Scan() {
+			key, value, ok := strings.Cut(sc.Text(), "=")
+			if ok && strings.TrimSpace(key) == "datahash" {
+				dataHash = strings.TrimSpace(value)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return "", err
+		}
+	}
+	// the tar reader stops at the end-of-archive blocks,
+	// the rest of the stream is padding
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return "", err
+	}
+	return dataHash, nil
+}
+
+func verifyAPKFile(path string, key *rsa.PublicKey) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return verifyAPK(f, fi.Size(), key)
+}
+
+// rsaKeyID returns the SHA-256 of the public part
+// of the RSA private key in file.
+func rsaKeyID(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("reading key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return "", errors.New("no PEM block found in key file")
+	}
+
+	var key *rsa.PrivateKey
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = k
+	} else {
+		parsed, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if pkcs8Err != nil {
+			return "", fmt.Errorf("parsing private key: %w", err)
+		}
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return "", errors.New("key is not an RSA key")
+		}
+		key = rsaKey
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkgsig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+type arMember struct {
+	name   string
+	offset int64
+	size   int64
+}
+
+func readArMembers(r io.ReaderAt, size int64) ([]arMember, error) {
+	magic := make([]byte, len(arMagic))
+	if _, err := r.ReadAt(magic, 0); err != nil || string(magic) != arMagic {
+		return nil, errors.New("not an ar archive")
+	}
+
+	var members []arMember
+	offset := int64(len(arMagic))
+	hdr := make([]byte, arHeaderSize)
+	for offset < size {
+		if _, err := r.ReadAt(hdr, offset); err != nil {
+			return nil, fmt.Errorf("reading ar header: %w", err)
+		}
+		memberSize, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil || memberSize < 0 {
+			return nil, errors.New("bad ar member size")
+		}
+		offset += arHeaderSize
+		members = append(members, arMember{
+			name:   strings.TrimSuffix(strings.TrimSpace(string(hdr[0:16])), "/"),
+			offset: offset,
+			size:   memberSize,
+		})
+		// members are aligned to 2 bytes
+		offset += memberSize + memberSize%2
+	}
+	return members, nil
+}
+
+// verifyDeb checks a debsign signature (the _gpgorigin member), which
+// covers debian-binary, control.tar and data.tar in this order.
+func verifyDeb(r io.ReaderAt, size int64, keyring openpgp.EntityList) (string, error) {
+	members, err := readArMembers(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	var signed []io.Reader
+	var sig *arMember
+	for i, m := range members {
+		switch {
+		case m.name == "debian-binary",
+			strings.HasPrefix(m.name, "control.tar"),
+			strings.HasPrefix(m.name, "data.tar"):
+			signed = append(signed, io.NewSectionReader(r, m.offset, m.size))
+		case strings.HasPrefix(m.name, "_gpg"):
+			sig = &members[i]
+		}
+	}
+	if sig == nil {
+		return "", ErrNotSigned
+	}
+
+	data := make([]byte, sig.size)
+	if _, err := r.ReadAt(data, sig.offset); err != nil {
+		return "", err
+	}
+
+	return verifyDetached(keyring, io.MultiReader(signed...), bytes.NewReader(data))
+}
+
+func verifyDebFile(path string, keyring openpgp.EntityList) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	return verifyDeb(f, fi.Size(), keyring)
+}
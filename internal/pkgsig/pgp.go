@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkgsig
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+// readKeyRing reads OpenPGP keys from file, which can be ASCII-armored.
+func readKeyRing(file string) (openpgp.EntityList, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading key file: %w", err)
+	}
+
+	if block, err := armor.Decode(bytes.NewReader(data)); err == nil {
+		data, err = io.ReadAll(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decoding key file: %w", err)
+		}
+	}
+
+	keyring, err := openpgp.ReadKeyRing(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing key file: %w", err)
+	}
+	return keyring, nil
+}
+
+// readSigningKey returns the first private key from file, decrypted
+// with passphrase if needed.
+func readSigningKey(file, passphrase string) (*openpgp.Entity, error) {
+	keyring, err := readKeyRing(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entity := range keyring {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			if passphrase == "" {
+				return nil, errors.New("key is encrypted but no passphrase was provided")
+			}
+			if err := entity.DecryptPrivateKeys([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("decrypting key: %w", err)
+			}
+		}
+		return entity, nil
+	}
+
+	return nil, errors.New("no private key found")
+}
+
+// SignDetached writes a binary detached OpenPGP signature of path to
+// SignatureFile(path), as pacman expects it.
+func SignDetached(path, keyFile, passphrase string) error {
+	entity, err := readSigningKey(keyFile, passphrase)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(SignatureFile(path))
+	if err != nil {
+		return err
+	}
+
+	if err := openpgp.DetachSign(out, entity, in, nil); err != nil {
+		_ = out.Close()
+		_ = os.Remove(out.Name())
+		return fmt.Errorf("signing %q: %w", path, err)
+	}
+
+	return out.Close()
+}
+
+// Sign returns a binary detached OpenPGP signature of data and
+// the fingerprint of the key that made it.
+func Sign(data io.Reader, keyFile, passphrase string) ([]byte, string, error) {
+	entity, err := readSigningKey(keyFile, passphrase)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var sig bytes.Buffer
+	if err := openpgp.DetachSign(&sig, entity, data, nil); err != nil {
+		return nil, "", fmt.Errorf("signing: %w", err)
+	}
+
+	return sig.Bytes(), hex.EncodeToString(entity.PrimaryKey.Fingerprint), nil
+}
+
+func verifyDetached(keyring openpgp.EntityList, signed, signature io.Reader) (string, error) {
+	data, err := io.ReadAll(signature)
+	if err != nil {
+		return "", err
+	}
+
+	var signer *openpgp.Entity
+	if _, err := armor.Decode(bytes.NewReader(data)); err == nil {
+		signer, err = openpgp.CheckArmoredDetachedSignature(keyring, signed, bytes.NewReader(data), nil)
+		if err != nil {
+			return "", fmt.Errorf("bad signature: %w", err)
+		}
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(keyring, signed, bytes.NewReader(data), nil)
+		if err != nil {
+			return "", fmt.Errorf("bad signature: %w", err)
+		}
+	}
+
+	return signerName(signer), nil
+}
+
+func verifyDetachedFile(path, sigPath string, keyring openpgp.EntityList) (string, error) {
+	sig, err := os.Open(sigPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotSigned
+	}
+	if err != nil {
+		return "", err
+	}
+	defer sig.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return verifyDetached(keyring, f, sig)
+}
+
+func signerName(e *openpgp.Entity) string {
+	if id := e.PrimaryIdentity(); id != nil {
+		return id.Name
+	}
+	return e.PrimaryKey.KeyIdString()
+}
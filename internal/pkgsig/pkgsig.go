@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package pkgsig signs built packages and verifies their signatures.
+package pkgsig
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+var (
+	ErrNotSigned         = errors.New("package is not signed")
+	ErrUnsupportedFormat = errors.New("unsupported package format")
+)
+
+// Format guesses the package format from the file name of path.
+// It returns an empty string for unknown files.
+func Format(path string) string {
+	name := filepath.Base(path)
+	switch {
+	case strings.HasSuffix(name, ".rpm"):
+		return "rpm"
+	case strings.HasSuffix(name, ".deb"):
+		return "deb"
+	case strings.HasSuffix(name, ".apk"):
+		return "apk"
+	case strings.Contains(name, ".pkg.tar"):
+		return "archlinux"
+	}
+	return ""
+}
+
+// SignatureFile returns the path of the detached signature of an
+// archlinux package.
+func SignatureFile(path string) string {
+	return path + ".sig"
+}
+
+// Passphrase reads the passphrase of key from its configured source.
+// Keys without a passphrase source get an empty passphrase.
+func Passphrase(key types.SigningKey) (string, error) {
+	switch {
+	case key.PassphraseFile != "":
+		data, err := os.ReadFile(key.PassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("reading passphrase file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case key.PassphraseEnv != "":
+		return os.Getenv(key.PassphraseEnv), nil
+	}
+	return "", nil
+}
+
+// Verify checks the signature of the package at path against the public
+// key in publicKeyFile and returns the name of the signer.
+func Verify(path, format, publicKeyFile string) (string, error) {
+	if format == "apk" {
+		key, err := readRSAPublicKey(publicKeyFile)
+		if err != nil {
+			return "", err
+		}
+		return verifyAPKFile(path, key)
+	}
+
+	keyring, err := readKeyRing(publicKeyFile)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case "rpm":
+		return verifyRPMFile(path, keyring)
+	case "deb":
+		return verifyDebFile(path, keyring)
+	case "archlinux":
+		return verifyDetachedFile(path, SignatureFile(path), keyring)
+	}
+
+	return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+}
+
+// KeyID returns the fingerprint of key, which signs packages of format,
+// or an empty string if no key file is configured. Encrypted keys are
+// not decrypted, as the fingerprint is of the public part.
+func KeyID(key types.SigningKey, format string) (string, error) {
+	if key.KeyFile == "" {
+		return "", nil
+	}
+	if format == "apk" {
+		return rsaKeyID(key.KeyFile)
+	}
+
+	keyring, err := readKeyRing(key.KeyFile)
+	if err != nil {
+		return "", err
+	}
+	for _, entity := range keyring {
+		if entity.PrivateKey != nil {
+			return hex.EncodeToString(entity.PrimaryKey.Fingerprint), nil
+		}
+	}
+	return "", errors.New("no private key found")
+}
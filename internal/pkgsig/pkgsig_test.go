@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkgsig_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/apk"
+	_ "github.com/goreleaser/nfpm/v2/deb"
+	"github.com/goreleaser/nfpm/v2/files"
+	_ "github.com/goreleaser/nfpm/v2/rpm-lowmem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type testKeys struct {
+	pgpPrivate, pgpPublic string
+	rsaPrivate, rsaPublic string
+	// otherPGPPublic and otherRSAPublic belong to different keys
+	otherPGPPublic, otherRSAPublic string
+}
+
+func writePGPKey(t *testing.T, dir, name string) (private, public string) {
+	t.Helper()
+
+	e, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+
+	var priv, pub bytes.Buffer
+	w, err := armor.Encode(&priv, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+
+	w, err = armor.Encode(&pub, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+
+	private = filepath.Join(dir, name+".asc")
+	public = filepath.Join(dir, name+".pub.asc")
+	require.NoError(t, os.WriteFile(private, priv.Bytes(), 0o600))
+	require.NoError(t, os.WriteFile(public, pub.Bytes(), 0o644))
+	return private, public
+}
+
+func writeRSAKey(t *testing.T, dir, name string) (private, public string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	private = filepath.Join(dir, name+".rsa")
+	public = filepath.Join(dir, name+".rsa.pub")
+	require.NoError(t, os.WriteFile(private, pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0o600))
+	require.NoError(t, os.WriteFile(public, pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: pubDER,
+	}), 0o644))
+	return private, public
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	dir := t.TempDir()
+	var k testKeys
+	k.pgpPrivate, k.pgpPublic = writePGPKey(t, dir, "builder")
+	_, k.otherPGPPublic = writePGPKey(t, dir, "other")
+	k.rsaPrivate, k.rsaPublic = writeRSAKey(t, dir, "builder")
+	_, k.otherRSAPublic = writeRSAKey(t, dir, "other")
+	return k
+}
+
+func buildPackage(t *testing.T, format string, sign func(info *nfpm.Info)) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "hello")
+	require.NoError(t, os.WriteFile(src, []byte("hello\n"), 0o644))
+
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:       "hello",
+		Arch:       "amd64",
+		Version:    "1.0.0",
+		Maintainer: "Builder <builder@example.com>",
+		Overridables: nfpm.Overridables{
+			Contents: files.Contents{
+				{Source: src, Destination: "/usr/share/hello/hello"},
+			},
+		},
+	})
+	if sign != nil {
+		sign(info)
+	}
+
+	packager, err := nfpm.Get(format)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, packager.ConventionalFileName(info))
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, packager.Package(info, f))
+	require.NoError(t, f.Close())
+
+	return path
+}
+
+func TestVerify(t *testing.T) {
+	keys := newTestKeys(t)
+
+	for _, tc := range []struct {
+		format       string
+		sign         func(info *nfpm.Info)
+		publicKey    string
+		otherKey     string
+		expectSigner string
+	}{
+		{
+			format:       "rpm",
+			sign:         func(info *nfpm.Info) { info.RPM.Signature.KeyFile = keys.pgpPrivate },
+			publicKey:    keys.pgpPublic,
+			otherKey:     keys.otherPGPPublic,
+			expectSigner: "builder <builder@example.com>",
+		},
+		{
+			format:       "deb",
+			sign:         func(info *nfpm.Info) { info.Deb.Signature.KeyFile = keys.pgpPrivate },
+			publicKey:    keys.pgpPublic,
+			otherKey:     keys.otherPGPPublic,
+			expectSigner: "builder <builder@example.com>",
+		},
+		{
+			format: "apk",
+			sign: func(info *nfpm.Info) {
+				info.APK.Signature.KeyFile = keys.rsaPrivate
+				info.APK.Signature.KeyName = "builder"
+			},
+			publicKey:    keys.rsaPublic,
+			otherKey:     keys.otherRSAPublic,
+			expectSigner: "builder.rsa.pub",
+		},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			signed := buildPackage(t, tc.format, tc.sign)
+			assert.Equal(t, tc.format, pkgsig.Format(signed))
+
+			signer, err := pkgsig.Verify(signed, tc.format, tc.publicKey)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectSigner, signer)
+
+			_, err = pkgsig.Verify(signed, tc.format, tc.otherKey)
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, pkgsig.ErrNotSigned)
+
+			unsigned := buildPackage(t, tc.format, nil)
+			_, err = pkgsig.Verify(unsigned, tc.format, tc.publicKey)
+			require.ErrorIs(t, err, pkgsig.ErrNotSigned)
+		})
+	}
+}
+
+func TestVerifyTamperedPayload(t *testing.T) {
+	keys := newTestKeys(t)
+
+	for _, tc := range []struct {
+		format    string
+		sign      func(info *nfpm.Info)
+		publicKey string
+	}{
+		{
+			format:    "rpm",
+			sign:      func(info *nfpm.Info) { info.RPM.Signature.KeyFile = keys.pgpPrivate },
+			publicKey: keys.pgpPublic,
+		},
+		{
+			format: "apk",
+			sign: func(info *nfpm.Info) {
+				info.APK.Signature.KeyFile = keys.rsaPrivate
+				info.APK.Signature.KeyName = "builder"
+			},
+			publicKey: keys.rsaPublic,
+		},
+	} {
+		t.Run(tc.format, func(t *testing.T) {
+			path := buildPackage(t, tc.format, tc.sign)
+
+			// the payload, or the data stream of an apk, comes last
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			data[len(data)-1] ^= 0xff
+			require.NoError(t, os.WriteFile(path, data, 0o644))
+
+			_, err = pkgsig.Verify(path, tc.format, tc.publicKey)
+			require.Error(t, err)
+			assert.NotErrorIs(t, err, pkgsig.ErrNotSigned)
+		})
+	}
+}
+
+func TestSignDetached(t *testing.T) {
+	keys := newTestKeys(t)
+
+	path := filepath.Join(t.TempDir(), "hello-1.0.0-1-x86_64.pkg.tar.zst")
+	require.NoError(t, os.WriteFile(path, []byte("package"), 0o644))
+
+	_, err := pkgsig.Verify(path, "archlinux", keys.pgpPublic)
+	require.ErrorIs(t, err, pkgsig.ErrNotSigned)
+
+	require.NoError(t, pkgsig.SignDetached(path, keys.pgpPrivate, ""))
+
+	signer, err := pkgsig.Verify(path, pkgsig.Format(path), keys.pgpPublic)
+	require.NoError(t, err)
+	assert.Equal(t, "builder <builder@example.com>", signer)
+
+	require.NoError(t, os.WriteFile(path, []byte("tampered"), 0o644))
+	_, err = pkgsig.Verify(path, "archlinux", keys.pgpPublic)
+	require.Error(t, err)
+}
+
+func TestPassphrase(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "passphrase")
+	require.NoError(t, os.WriteFile(file, []byte("secret\n"), 0o600))
+
+	p, err := pkgsig.Passphrase(types.SigningKey{PassphraseFile: file})
+	require.NoError(t, err)
+	assert.Equal(t, "secret", p)
+
+	t.Setenv("STPLR_TEST_PASSPHRASE", "from-env")
+	p, err = pkgsig.Passphrase(types.SigningKey{PassphraseEnv: "STPLR_TEST_PASSPHRASE"})
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", p)
+
+	p, err = pkgsig.Passphrase(types.SigningKey{})
+	require.NoError(t, err)
+	assert.Empty(t, p)
+}
+
+func TestKeyID(t *testing.T) {
+	keys := newTestKeys(t)
+
+	id, err := pkgsig.KeyID(types.SigningKey{}, "rpm")
+	require.NoError(t, err)
+	assert.Empty(t, id)
+
+	pgpID, err := pkgsig.KeyID(types.SigningKey{KeyFile: keys.pgpPrivate}, "rpm")
+	require.NoError(t, err)
+	assert.Len(t, pgpID, 40)
+
+	rsaID, err := pkgsig.KeyID(types.SigningKey{KeyFile: keys.rsaPrivate}, "apk")
+	require.NoError(t, err)
+	assert.Len(t, rsaID, 64)
+
+	_, err = pkgsig.KeyID(types.SigningKey{KeyFile: keys.pgpPublic}, "rpm")
+	require.Error(t, err)
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package pkgsig
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha1" //nolint:gosec // old rpms use SHA1 payload digests
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+const (
+	rpmLeadSize = 96
+	// rpmSigTagRSA is the OpenPGP signature of the main header.
+	rpmSigTagRSA = 268
+	// rpmTagPayloadDigest is the hex digest of the compressed payload
+	// and rpmTagPayloadDigestAlgo is the OpenPGP id of its algorithm.
+	rpmTagPayloadDigest     = 5092
+	rpmTagPayloadDigestAlgo = 5093
+
+	rpmTypeInt32       = 4
+	rpmTypeBin         = 7
+	rpmTypeStringArray = 8
+
+	rpmMaxIndexEntries = 1 << 16
+	rpmMaxHeaderData   = 256 << 20
+)
+
+var (
+	rpmLeadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	rpmHeaderMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+type rpmIndexEntry struct {
+	tag, typ, offset, count uint32
+}
+
+type rpmHeader struct {
+	entries []rpmIndexEntry
+	data    []byte
+	// raw is the whole header as it is stored in the file.
+	raw []byte
+}
+
+func readRPMHeader(r io.Reader) (*rpmHeader, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(intro[:4], rpmHeaderMagic) {
+		return nil, errors.New("bad rpm header magic")
+	}
+
+	n := binary.BigEndian.Uint32(intro[8:12])
+	size := binary.BigEndian.Uint32(intro[12:16])
+	if n > rpmMaxIndexEntries || size > rpmMaxHeaderData {
+		return nil, errors.New("rpm header is too large")
+	}
+
+	rest := make([]byte, 16*int(n)+int(size))
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, err
+	}
+
+	h := &rpmHeader{
+		entries: make([]rpmIndexEntry, n),
+		data:    rest[16*n:],
+		raw:     append(intro, rest...),
+	}
+	for i := range h.entries {
+		e := rest[16*i:]
+		h.entries[i] = rpmIndexEntry{
+			tag:    binary.BigEndian.Uint32(e[0:4]),
+			typ:    binary.BigEndian.Uint32(e[4:8]),
+			offset: binary.BigEndian.Uint32(e[8:12]),
+			count:  binary.BigEndian.Uint32(e[12:16]),
+		}
+	}
+	return h, nil
+}
+
+func (h *rpmHeader) bin(tag uint32) ([]byte, bool) {
+	for _, e := range h.entries {
+		if e.tag != tag || e.typ != rpmTypeBin {
+			continue
+		}
+		end := uint64(e.offset) + uint64(e.count)
+		if end > uint64(len(h.data)) {
+			return nil, false
+		}
+		return h.data[e.offset:end], true
+	}
+	return nil, false
+}
+
+func (h *rpmHeader) int32(tag uint32) (uint32, bool) {
+	for _, e := range h.entries {
+		if e.tag != tag || e.typ != rpmTypeInt32 || e.count == 0 {
+			continue
+		}
+		end := uint64(e.offset) + 4
+		if end > uint64(len(h.data)) {
+			return 0, false
+		}
+		return binary.BigEndian.Uint32(h.data[e.offset:end]), true
+	}
+	return 0, false
+}
+
+// firstString returns the first string of a string array.
+func (h *rpmHeader) firstString(tag uint32) (string, bool) {
+	for _, e := range h.entries {
+		if e.tag != tag || e.typ != rpmTypeStringArray || e.count == 0 {
+			continue
+		}
+		if uint64(e.offset) >= uint64(len(h.data)) {
+			return "", false
+		}
+		str, _, ok := bytes.Cut(h.data[e.offset:], []byte{0})
+		if !ok {
+			return "", false
+		}
+		return string(str), true
+	}
+	return "", false
+}
+
+// rpmDigestAlgos maps the OpenPGP ids of hash algorithms
+// used for payload digests to their Go counterparts.
+var rpmDigestAlgos = map[uint32]crypto.Hash{
+	2:  crypto.SHA1,
+	8:  crypto.SHA256,
+	9:  crypto.SHA384,
+	10: crypto.SHA512,
+	11: crypto.SHA224,
+}
+
+// checkPayload compares the payload read from r
+// with the payload digest of header.
+func checkPayload(r io.Reader, header *rpmHeader) error {
+	want, ok := header.firstString(rpmTagPayloadDigest)
+	if !ok {
+		return errors.New("rpm header has no payload digest")
+	}
+	id, ok := header.int32(rpmTagPayloadDigestAlgo)
+	if !ok {
+		return errors.New("rpm header has no payload digest algorithm")
+	}
+	algo, ok := rpmDigestAlgos[id]
+	if !ok {
+		return fmt.Errorf("unsupported rpm payload digest algorithm %d", id)
+	}
+
+	h := algo.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return fmt.Errorf("reading rpm payload: %w", err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, want) {
+		return errors.New("rpm payload doesn't match its digest")
+	}
+	return nil
+}
+
+// verifyRPM checks the header signature of an rpm package, which covers
+// the main header including the payload digest, and the payload against
+// that digest.
+func verifyRPM(r io.Reader, keyring openpgp.EntityList) (string, error) {
+	lead := make([]byte, rpmLeadSize)
+	if _, err := io.ReadFull(r, lead); err != nil {
+		return "", fmt.Errorf("reading rpm lead: %w", err)
+	}
+	if !bytes.Equal(lead[:4], rpmLeadMagic) {
+		return "", errors.New("not an rpm package")
+	}
+
+	sigHeader, err := readRPMHeader(r)
+	if err != nil {
+		return "", fmt.Errorf("reading rpm signature header: %w", err)
+	}
+	// the signature header is padded to 8 bytes
+	if pad := (8 - len(sigHeader.raw)%8) % 8; pad > 0 {
+		if _, err := io.CopyN(io.Discard, r, int64(pad)); err != nil {
+			return "", err
+		}
+	}
+
+	header, err := readRPMHeader(r)
+	if err != nil {
+		return "", fmt.Errorf("reading rpm header: %w", err)
+	}
+
+	sig, ok := sigHeader.bin(rpmSigTagRSA)
+	if !ok {
+		return "", ErrNotSigned
+	}
+
+	signer, err := verifyDetached(keyring, bytes.NewReader(header.raw), bytes.NewReader(sig))
+	if err != nil {
+		return "", err
+	}
+	if err := checkPayload(r, header); err != nil {
+		return "", err
+	}
+	return signer, nil
+}
+
+func verifyRPMFile(path string, keyring openpgp.EntityList) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return verifyRPM(f, keyring)
+}
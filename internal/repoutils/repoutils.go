@@ -79,6 +79,7 @@ func repoFromReader(r interface {
 	repo.Icon = repocfg.Repo.Icon
 	repo.RequireSignedCommits = repocfg.Repo.RequireSignedCommits
 	repo.TrustedKeys = repocfg.Repo.TrustedKeys
+	repo.Lint = repocfg.Repo.Lint
 
 	return &repo, nil
 }
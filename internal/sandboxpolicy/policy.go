@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package sandboxpolicy checks the host paths and environment variables
+// that Staplerfiles request for their build sandbox against the
+// allowlists of the config.
+package sandboxpolicy
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// rule allows the packages matching pattern one value,
+// a host path or the name of an environment variable.
+type rule struct {
+	pattern glob.Glob
+	value   string
+}
+
+// Policy is the allowlist of the sandbox binds and the environment
+// variables passed through to the sandbox. Anything not allowed
+// for a package is refused.
+type Policy struct {
+	binds []rule
+	env   []rule
+}
+
+// New parses the allowlists. Their entries are a repo/package glob
+// and a value separated by a colon, like "default/cuda-*:/opt/cuda".
+// The values of env may be globs too, like "*/foo:FOO_*".
+func New(binds, env []string) (*Policy, error) {
+	p := &Policy{}
+	var err error
+	if p.binds, err = parseRules(binds); err != nil {
+		return nil, fmt.Errorf("invalid sandbox bind: %w", err)
+	}
+	for _, r := range p.binds {
+		if !filepath.IsAbs(r.value) {
+			return nil, fmt.Errorf("invalid sandbox bind: %q is not an absolute path", r.value)
+		}
+	}
+	if p.env, err = parseRules(env); err != nil {
+		return nil, fmt.Errorf("invalid sandbox environment variable: %w", err)
+	}
+	for _, r := range p.env {
+		if _, err := path.Match(r.value, ""); err != nil {
+			return nil, fmt.Errorf("invalid sandbox environment variable %q: %w", r.value, err)
+		}
+	}
+	return p, nil
+}
+
+func parseRules(entries []string) ([]rule, error) {
+	rules := make([]rule, 0, len(entries))
+	for _, entry := range entries {
+		pattern, value, ok := strings.Cut(entry, ":")
+		if !ok || pattern == "" || value == "" {
+			return nil, fmt.Errorf("%q is not in the repo/package:value form", entry)
+		}
+		g, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", entry, err)
+		}
+		rules = append(rules, rule{pattern: g, value: value})
+	}
+	return rules, nil
+}
+
+// Binds checks the host paths the package fullName requests to be
+// mounted into its sandbox. A path is allowed if it is an allowed
+// path or below one. The paths are returned cleaned.
+func (p *Policy) Binds(fullName string, requested []string) ([]string, error) {
+	binds := make([]string, 0, len(requested))
+	for _, req := range requested {
+		bind := filepath.Clean(req)
+		if !filepath.IsAbs(bind) {
+			return nil, fmt.Errorf("%s requests the sandbox bind %q, which is not an absolute path", fullName, req)
+		}
+		allowed := slices.ContainsFunc(p.binds, func(r rule) bool {
+			if !r.pattern.Match(fullName) {
+				return false
+			}
+			rel, err := filepath.Rel(filepath.Clean(r.value), bind)
+			return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+		})
+		if !allowed {
+			return nil, fmt.Errorf("%s requests the sandbox bind %s, which sandboxBinds doesn't allow for it", fullName, bind)
+		}
+		binds = append(binds, bind)
+	}
+	return binds, nil
+}
+
+// CheckEnv checks the names of the environment variables the
+// package fullName requests to be passed to its sandbox.
+func (p *Policy) CheckEnv(fullName string, requested []string) error {
+	for _, name := range requested {
+		if !p.EnvAllowed(fullName, name) {
+			return fmt.Errorf("%s requests the environment variable %s, which sandboxEnvPassthrough doesn't allow for it", fullName, name)
+		}
+	}
+	return nil
+}
+
+// EnvAllowed tells if the variable name may be passed
+// to the sandbox of the package fullName.
+func (p *Policy) EnvAllowed(fullName, name string) bool {
+	return slices.ContainsFunc(p.env, func(r rule) bool {
+		matched, _ := path.Match(r.value, name)
+		return matched && r.pattern.Match(fullName)
+	})
+}
+
+// Environ returns the variables of environ, in the NAME=value
+// form, that may be passed to the sandbox of any of fullNames.
+func (p *Policy) Environ(fullNames []string, environ []string) []string {
+	var res []string
+	for _, kv := range environ {
+		name, _, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if slices.ContainsFunc(fullNames, func(fullName string) bool {
+			return p.EnvAllowed(fullName, name)
+		}) {
+			res = append(res, kv)
+		}
+	}
+	return res
+}
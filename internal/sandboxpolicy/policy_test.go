@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sandboxpolicy_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/sandboxpolicy"
+)
+
+func TestBinds(t *testing.T) {
+	p, err := sandboxpolicy.New([]string{
+		"default/vendor-sdk:/opt/sdk",
+		"*/cuda-*:/opt/cuda/",
+	}, nil)
+	require.NoError(t, err)
+
+	binds, err := p.Binds("default/vendor-sdk", []string{"/opt/sdk", "/opt/sdk/lib/../bin"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/opt/sdk", "/opt/sdk/bin"}, binds)
+
+	binds, err = p.Binds("extra/cuda-toolkit", []string{"/opt/cuda"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/opt/cuda"}, binds)
+
+	binds, err = p.Binds("default/foo", nil)
+	require.NoError(t, err)
+	assert.Empty(t, binds)
+
+	for _, tc := range []struct {
+		pkg  string
+		bind string
+	}{
+		{"default/foo", "/opt/sdk"},
+		{"other/vendor-sdk", "/opt/sdk"},
+		{"default/vendor-sdk", "/opt/sdk-other"},
+		{"default/vendor-sdk", "/opt/sdk/../../etc"},
+		{"default/vendor-sdk", "/opt"},
+		{"default/vendor-sdk", "opt/sdk"},
+	} {
+		_, err := p.Binds(tc.pkg, []string{tc.bind})
+		assert.Error(t, err, "%s %s", tc.pkg, tc.bind)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	p, err := sandboxpolicy.New(nil, []string{
+		"default/vendor-sdk:SDK_LICENSE",
+		"default/*:GOPROXY",
+		"extra/foo:FOO_*",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, p.CheckEnv("default/vendor-sdk", []string{"SDK_LICENSE", "GOPROXY"}))
+	require.NoError(t, p.CheckEnv("extra/foo", []string{"FOO_TOKEN"}))
+	assert.Error(t, p.CheckEnv("default/bar", []string{"SDK_LICENSE"}))
+	assert.Error(t, p.CheckEnv("extra/foo", []string{"GOPROXY"}))
+
+	environ := []string{"SDK_LICENSE=/lic", "GOPROXY=direct", "FOO_TOKEN=x", "HOME=/root", "broken"}
+	assert.Equal(t, []string{"SDK_LICENSE=/lic", "GOPROXY=direct"}, p.Environ([]string{"default/vendor-sdk"}, environ))
+	assert.Equal(t, []string{"GOPROXY=direct", "FOO_TOKEN=x"}, p.Environ([]string{"default/bar", "extra/foo"}, environ))
+	assert.Empty(t, p.Environ([]string{"other/baz"}, environ))
+}
+
+func TestNew(t *testing.T) {
+	for _, tc := range []struct {
+		binds []string
+		env   []string
+	}{
+		{binds: []string{"/opt/sdk"}},
+		{binds: []string{"default/foo:opt/sdk"}},
+		{binds: []string{":/opt/sdk"}},
+		{binds: []string{"default/[foo:/opt/sdk"}},
+		{env: []string{"default/foo:"}},
+		{env: []string{"default/foo:FOO_["}},
+	} {
+		_, err := sandboxpolicy.New(tc.binds, tc.env)
+		assert.Error(t, err, "%v %v", tc.binds, tc.env)
+	}
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sbom
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"go.stplr.dev/stplr/internal/config"
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+const (
+	statementType  = "https://in-toto.io/Statement/v1"
+	predicateType  = "https://slsa.dev/provenance/v1"
+	buildType      = "https://stplr.dev/provenance/build/v1"
+	payloadType    = "application/vnd.in-toto+json"
+	builderIDFmt   = "https://stplr.dev/stplr@%s"
+	gitCommitAlgo  = "gitCommit"
+	sourceRepoName = "staplerfile"
+)
+
+type statement struct {
+	Type          string     `json:"_type"`
+	Subject       []resource `json:"subject"`
+	PredicateType string     `json:"predicateType"`
+	Predicate     provenance `json:"predicate"`
+}
+
+type resource struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+type provenance struct {
+	BuildDefinition buildDefinition `json:"buildDefinition"`
+	RunDetails      runDetails      `json:"runDetails"`
+}
+
+type buildDefinition struct {
+	BuildType            string            `json:"buildType"`
+	ExternalParameters   map[string]string `json:"externalParameters"`
+	ResolvedDependencies []resource        `json:"resolvedDependencies,omitempty"`
+}
+
+type runDetails struct {
+	Builder  builder        `json:"builder"`
+	Metadata *buildMetadata `json:"metadata,omitempty"`
+}
+
+type builder struct {
+	ID string `json:"id"`
+}
+
+type buildMetadata struct {
+	StartedOn  string `json:"startedOn,omitempty"`
+	FinishedOn string `json:"finishedOn,omitempty"`
+}
+
+// Envelope is a DSSE envelope carrying the provenance statement.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of an Envelope. Sig is a binary OpenPGP
+// signature and KeyID is the fingerprint of the key.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+func newStatement(in *Input, digest string) statement {
+	params := map[string]string{
+		"repository": in.Repository,
+		"package":    in.Name,
+		"version":    in.Version,
+		"format":     in.Format,
+		"arch":       in.Arch,
+	}
+	if in.Distro != "" {
+		params["distro"] = in.Distro
+	}
+
+	var deps []resource
+	if in.SourceURL != "" || in.SourceCommit != "" {
+		r := resource{Name: sourceRepoName, URI: in.SourceURL}
+		if in.SourceCommit != "" {
+			r.Digest = map[string]string{gitCommitAlgo: in.SourceCommit}
+		}
+		deps = append(deps, r)
+	}
+	for i, src := range in.Sources {
+		r := resource{URI: src}
+		if i < len(in.Checksums) {
+			if algo, value, ok := parseChecksum(in.Checksums[i]); ok {
+				r.Digest = map[string]string{algo: value}
+			}
+		}
+		deps = append(deps, r)
+	}
+
+	var meta *buildMetadata
+	if !in.Started.IsZero() || !in.Finished.IsZero() {
+		meta = &buildMetadata{
+			StartedOn:  formatTime(in.Started),
+			FinishedOn: formatTime(in.Finished),
+		}
+	}
+
+	return statement{
+		Type: statementType,
+		Subject: []resource{{
+			Name:   filepath.Base(in.Path),
+			Digest: map[string]string{"sha256": digest},
+		}},
+		PredicateType: predicateType,
+		Predicate: provenance{
+			BuildDefinition: buildDefinition{
+				BuildType:            buildType,
+				ExternalParameters:   params,
+				ResolvedDependencies: deps,
+			},
+			RunDetails: runDetails{
+				Builder:  builder{ID: fmt.Sprintf(builderIDFmt, config.Version)},
+				Metadata: meta,
+			},
+		},
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// PAE returns the DSSE pre-authentication encoding of payload,
+// which is what the signatures of an envelope are made over.
+func PAE(payloadType string, payload []byte) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "DSSEv1 %d %s %d ", len(payloadType), payloadType, len(payload))
+	b.Write(payload)
+	return b.Bytes()
+}
+
+func writeProvenance(w io.Writer, in *Input, digest string, key types.SigningKey, passphrase string) error {
+	payload, err := json.Marshal(newStatement(in, digest))
+	if err != nil {
+		return err
+	}
+
+	env := Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{},
+	}
+
+	if key.KeyFile != "" {
+		sig, keyID, err := pkgsig.Sign(bytes.NewReader(PAE(payloadType, payload)), key.KeyFile, passphrase)
+		if err != nil {
+			return err
+		}
+		env.Signatures = append(env.Signatures, Signature{
+			KeyID: keyID,
+			Sig:   base64.StdEncoding.EncodeToString(sig),
+		})
+	}
+
+	// .intoto.jsonl holds one envelope per line
+	return json.NewEncoder(w).Encode(env)
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package sbom writes an SPDX software bill of materials and an in-toto
+// build provenance statement for a built package.
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+// Input describes a built package and how it was built.
+type Input struct {
+	// Path is the package file.
+	Path    string
+	Name    string
+	Version string
+	Arch    string
+	Format  string
+
+	Summary    string
+	Homepage   string
+	Maintainer string
+	Licenses   []string
+
+	Depends      []string
+	BuildDepends []string
+	Sources      []string
+	// Checksums are in the Staplerfile format, one per source.
+	Checksums []string
+
+	// Repository is the Stapler repo the script came from and
+	// SourceURL and SourceCommit are where it was fetched from.
+	Repository   string
+	SourceURL    string
+	SourceCommit string
+	Distro       string
+
+	// Created is the time of the documents. Started and Finished
+	// are left out of the provenance if zero.
+	Created  time.Time
+	Started  time.Time
+	Finished time.Time
+}
+
+// SPDXFile returns the path of the SPDX document of the package at path.
+func SPDXFile(path string) string {
+	return path + ".spdx.json"
+}
+
+// ProvenanceFile returns the path of the provenance statement of the
+// package at path.
+func ProvenanceFile(path string) string {
+	return path + ".intoto.jsonl"
+}
+
+// Files returns the paths of all documents written for the package at path.
+func Files(path string) []string {
+	return []string{SPDXFile(path), ProvenanceFile(path)}
+}
+
+// Write writes the SPDX document and the provenance statement next to
+// in.Path. The statement is signed with key if it has a key file.
+func Write(in *Input, key types.SigningKey, passphrase string) error {
+	digest, err := fileSHA256(in.Path)
+	if err != nil {
+		return fmt.Errorf("hashing %q: %w", in.Path, err)
+	}
+
+	if err := writeFile(SPDXFile(in.Path), func(w io.Writer) error {
+		return writeSPDX(w, in, digest)
+	}); err != nil {
+		return fmt.Errorf("writing SPDX document: %w", err)
+	}
+
+	if err := writeFile(ProvenanceFile(in.Path), func(w io.Writer) error {
+		return writeProvenance(w, in, digest, key, passphrase)
+	}); err != nil {
+		return fmt.Errorf("writing provenance: %w", err)
+	}
+
+	return nil
+}
+
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// parseChecksum splits a Staplerfile checksum into the algorithm and
+// the hex digest. It returns false for SKIP.
+func parseChecksum(checksum string) (algo, digest string, ok bool) {
+	if checksum == "" || strings.EqualFold(checksum, "SKIP") {
+		return "", "", false
+	}
+	algo, digest, found := strings.Cut(checksum, ":")
+	if !found {
+		return "sha256", strings.ToLower(checksum), true
+	}
+	return strings.ToLower(algo), strings.ToLower(digest), true
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sbom_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/sbom"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+func testInput(t *testing.T) *sbom.Input {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "foo-1.0-1.x86_64.rpm")
+	require.NoError(t, os.WriteFile(path, []byte("package"), 0o644))
+
+	return &sbom.Input{
+		Path:         path,
+		Name:         "foo",
+		Version:      "1.0-1",
+		Arch:         "x86_64",
+		Format:       "rpm",
+		Maintainer:   "Jane Doe <jane@example.com>",
+		Licenses:     []string{"MIT", "Custom license"},
+		Depends:      []string{"glibc"},
+		BuildDepends: []string{"gcc"},
+		Sources:      []string{"https://example.com/foo-1.0.tar.gz", "file.patch"},
+		Checksums:    []string{"sha512:ABCD", "SKIP"},
+		Repository:   "repo",
+		SourceURL:    "https://git.example.com/repo.git",
+		SourceCommit: "0123456789abcdef",
+		Created:      time.Unix(1700000000, 0),
+	}
+}
+
+func readEnvelope(t *testing.T, path string) (sbom.Envelope, map[string]any) {
+	t.Helper()
+
+	data, err := os.ReadFile(sbom.ProvenanceFile(path))
+	require.NoError(t, err)
+
+	var env sbom.Envelope
+	require.NoError(t, json.Unmarshal(data, &env))
+	assert.Equal(t, "application/vnd.in-toto+json", env.PayloadType)
+
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	require.NoError(t, err)
+	var statement map[string]any
+	require.NoError(t, json.Unmarshal(payload, &statement))
+
+	return env, statement
+}
+
+func TestWrite(t *testing.T) {
+	in := testInput(t)
+	require.NoError(t, sbom.Write(in, types.SigningKey{}, ""))
+
+	data, err := os.ReadFile(sbom.SPDXFile(in.Path))
+	require.NoError(t, err)
+
+	var doc struct {
+		SPDXVersion  string `json:"spdxVersion"`
+		CreationInfo struct {
+			Created string `json:"created"`
+		} `json:"creationInfo"`
+		Packages []struct {
+			SPDXID           string `json:"SPDXID"`
+			Name             string `json:"name"`
+			Supplier         string `json:"supplier"`
+			DownloadLocation string `json:"downloadLocation"`
+			LicenseDeclared  string `json:"licenseDeclared"`
+			Checksums        []struct {
+				Algorithm     string `json:"algorithm"`
+				ChecksumValue string `json:"checksumValue"`
+			} `json:"checksums"`
+		} `json:"packages"`
+		Relationships []struct {
+			SPDXElementID      string `json:"spdxElementId"`
+			RelationshipType   string `json:"relationshipType"`
+			RelatedSPDXElement string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "2023-11-14T22:13:20Z", doc.CreationInfo.Created)
+	require.Len(t, doc.Packages, 5)
+
+	main := doc.Packages[0]
+	assert.Equal(t, "foo", main.Name)
+	assert.Equal(t, "Person: Jane Doe (jane@example.com)", main.Supplier)
+	assert.Equal(t, "(MIT AND LicenseRef-Custom-license)", main.LicenseDeclared)
+	require.Len(t, main.Checksums, 1)
+	assert.Equal(t, "SHA256", main.Checksums[0].Algorithm)
+
+	src := doc.Packages[1]
+	assert.Equal(t, "foo-1.0.tar.gz", src.Name)
+	assert.Equal(t, "https://example.com/foo-1.0.tar.gz", src.DownloadLocation)
+	require.Len(t, src.Checksums, 1)
+	assert.Equal(t, "SHA512", src.Checksums[0].Algorithm)
+	assert.Equal(t, "abcd", src.Checksums[0].ChecksumValue)
+	assert.Empty(t, doc.Packages[2].Checksums)
+
+	assert.Contains(t, doc.Relationships, struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+	}{"SPDXRef-BuildDependency-0", "BUILD_DEPENDENCY_OF", "SPDXRef-Package"})
+
+	env, statement := readEnvelope(t, in.Path)
+	assert.Empty(t, env.Signatures)
+	assert.Equal(t, "https://slsa.dev/provenance/v1", statement["predicateType"])
+
+	subject := statement["subject"].([]any)[0].(map[string]any)
+	assert.Equal(t, filepath.Base(in.Path), subject["name"])
+	assert.Equal(t, main.Checksums[0].ChecksumValue, subject["digest"].(map[string]any)["sha256"])
+
+	deps := statement["predicate"].(map[string]any)["buildDefinition"].(map[string]any)["resolvedDependencies"].([]any)
+	require.Len(t, deps, 3)
+	assert.Equal(t, map[string]any{"gitCommit": "0123456789abcdef"}, deps[0].(map[string]any)["digest"])
+	assert.Equal(t, map[string]any{"sha512": "abcd"}, deps[1].(map[string]any)["digest"])
+}
+
+func TestWriteSigned(t *testing.T) {
+	e, err := openpgp.NewEntity("builder", "", "builder@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+
+	var priv bytes.Buffer
+	require.NoError(t, e.SerializePrivate(&priv, nil))
+	keyFile := filepath.Join(t.TempDir(), "key.gpg")
+	require.NoError(t, os.WriteFile(keyFile, priv.Bytes(), 0o600))
+
+	in := testInput(t)
+	require.NoError(t, sbom.Write(in, types.SigningKey{KeyFile: keyFile}, ""))
+
+	env, _ := readEnvelope(t, in.Path)
+	require.Len(t, env.Signatures, 1)
+
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	require.NoError(t, err)
+	sig, err := base64.StdEncoding.DecodeString(env.Signatures[0].Sig)
+	require.NoError(t, err)
+
+	signer, err := openpgp.CheckDetachedSignature(
+		openpgp.EntityList{e},
+		bytes.NewReader(sbom.PAE(env.PayloadType, payload)),
+		bytes.NewReader(sig),
+		nil,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, e.PrimaryKey.KeyId, signer.PrimaryKey.KeyId)
+}
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.stplr.dev/stplr/internal/config"
+)
+
+const noAssertion = "NOASSERTION"
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string         `json:"SPDXID"`
+	Name             string         `json:"name"`
+	VersionInfo      string         `json:"versionInfo,omitempty"`
+	PackageFileName  string         `json:"packageFileName,omitempty"`
+	Supplier         string         `json:"supplier,omitempty"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+	Homepage         string         `json:"homepage,omitempty"`
+	LicenseConcluded string         `json:"licenseConcluded"`
+	LicenseDeclared  string         `json:"licenseDeclared"`
+	CopyrightText    string         `json:"copyrightText"`
+	Summary          string         `json:"summary,omitempty"`
+	PrimaryPurpose   string         `json:"primaryPackagePurpose,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+var spdxAlgorithms = map[string]string{
+	"sha1":        "SHA1",
+	"sha224":      "SHA224",
+	"sha256":      "SHA256",
+	"sha384":      "SHA384",
+	"sha512":      "SHA512",
+	"md5":         "MD5",
+	"blake2b-256": "BLAKE2b-256",
+	"blake2b-512": "BLAKE2b-512",
+}
+
+func writeSPDX(w io.Writer, in *Input, digest string) error {
+	const pkgID = "SPDXRef-Package"
+
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              in.Name + "-" + in.Version,
+		DocumentNamespace: fmt.Sprintf("https://stplr.dev/spdx/%s/%s-%s-%s", in.Repository, in.Name, in.Version, digest),
+		CreationInfo: spdxCreationInfo{
+			Created:  in.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: stplr-" + config.Version},
+		},
+	}
+
+	license := licenseExpression(in.Licenses)
+	doc.Packages = append(doc.Packages, spdxPackage{
+		SPDXID:           pkgID,
+		Name:             in.Name,
+		VersionInfo:      in.Version,
+		PackageFileName:  filepath.Base(in.Path),
+		Supplier:         supplier(in.Maintainer),
+		DownloadLocation: noAssertion,
+		Checksums:        []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: digest}},
+		Homepage:         in.Homepage,
+		LicenseConcluded: noAssertion,
+		LicenseDeclared:  license,
+		CopyrightText:    noAssertion,
+		Summary:          in.Summary,
+		PrimaryPurpose:   "INSTALL",
+	})
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		SPDXElementID:      "SPDXRef-DOCUMENT",
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: pkgID,
+	})
+
+	for i, src := range in.Sources {
+		id := fmt.Sprintf("SPDXRef-Source-%d", i)
+		p := spdxPackage{
+			SPDXID:           id,
+			Name:             sourceName(src),
+			DownloadLocation: src,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  license,
+			CopyrightText:    noAssertion,
+			PrimaryPurpose:   "SOURCE",
+		}
+		if i < len(in.Checksums) {
+			if algo, value, ok := parseChecksum(in.Checksums[i]); ok {
+				if name, ok := spdxAlgorithms[algo]; ok {
+					p.Checksums = []spdxChecksum{{Algorithm: name, ChecksumValue: value}}
+				}
+			}
+		}
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      pkgID,
+			RelationshipType:   "GENERATED_FROM",
+			RelatedSPDXElement: id,
+		})
+	}
+
+	addDeps := func(prefix string, deps []string, relationship func(id string) spdxRelationship) {
+		for i, dep := range deps {
+			id := fmt.Sprintf("SPDXRef-%s-%d", prefix, i)
+			doc.Packages = append(doc.Packages, spdxPackage{
+				SPDXID:           id,
+				Name:             dep,
+				DownloadLocation: noAssertion,
+				LicenseConcluded: noAssertion,
+				LicenseDeclared:  noAssertion,
+				CopyrightText:    noAssertion,
+			})
+			doc.Relationships = append(doc.Relationships, relationship(id))
+		}
+	}
+	addDeps("Dependency", in.Depends, func(id string) spdxRelationship {
+		return spdxRelationship{pkgID, "DEPENDS_ON", id}
+	})
+	addDeps("BuildDependency", in.BuildDepends, func(id string) spdxRelationship {
+		return spdxRelationship{id, "BUILD_DEPENDENCY_OF", pkgID}
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+var (
+	licenseIDRe      = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+	licenseRefCharRe = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+)
+
+// licenseExpression joins licenses into an SPDX expression. Names that
+// can't be SPDX identifiers become LicenseRef- identifiers.
+func licenseExpression(licenses []string) string {
+	if len(licenses) == 0 {
+		return noAssertion
+	}
+	ids := make([]string, 0, len(licenses))
+	for _, l := range licenses {
+		l = strings.TrimSpace(l)
+		if !licenseIDRe.MatchString(l) {
+			l = "LicenseRef-" + strings.Trim(licenseRefCharRe.ReplaceAllString(l, "-"), "-")
+		}
+		ids = append(ids, l)
+	}
+	if len(ids) == 1 {
+		return ids[0]
+	}
+	return "(" + strings.Join(ids, " AND ") + ")"
+}
+
+// supplier converts a "Name <email>" maintainer to an SPDX supplier.
+func supplier(maintainer string) string {
+	if maintainer == "" {
+		return ""
+	}
+	name, email, ok := strings.Cut(maintainer, "<")
+	if !ok {
+		return "Person: " + strings.TrimSpace(maintainer)
+	}
+	return fmt.Sprintf("Person: %s (%s)", strings.TrimSpace(name), strings.TrimSuffix(strings.TrimSpace(email), ">"))
+}
+
+func sourceName(src string) string {
+	src, _, _ = strings.Cut(src, "?")
+	return filepath.Base(strings.TrimSuffix(src, "/"))
+}
@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scaffold
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// BuildSystem is how a source tree is built and installed.
+type BuildSystem struct {
+	Name string
+	// Files are the files in the root of a source tree that use the
+	// build system. Any of them is enough.
+	Files     []string
+	BuildDeps []string
+	// Build and Package are the commands of the build() and package()
+	// functions, which are run in the root of the source tree.
+	Build   []string
+	Package []string
+
+	// version returns the version in the files of the build system,
+	// or an empty string.
+	version func(dir string) string
+}
+
+// BuildSystems are the detected build systems, in the order they are
+// tried in.
+var BuildSystems = []*BuildSystem{
+	{
+		Name:      "meson",
+		Files:     []string{"meson.build"},
+		BuildDeps: []string{"meson", "ninja", "gcc"},
+		Build: []string{
+			`meson setup build --prefix=/usr --buildtype=release`,
+			`meson compile -C build`,
+		},
+		Package: []string{
+			`meson install -C build --destdir "${pkgdir}"`,
+		},
+		version: regexpVersion("meson.build", `(?s)project\s*\(.*?version\s*:\s*'([^']+)'`),
+	},
+	{
+		Name:      "cmake",
+		Files:     []string{"CMakeLists.txt"},
+		BuildDeps: []string{"cmake", "gcc", "make"},
+		Build: []string{
+			`cmake -B build -S . -DCMAKE_BUILD_TYPE=Release -DCMAKE_INSTALL_PREFIX=/usr`,
+			`cmake --build build`,
+		},
+		Package: []string{
+			`DESTDIR="${pkgdir}" cmake --install build`,
+		},
+		version: regexpVersion("CMakeLists.txt", `(?is)project\s*\([^)]*?VERSION\s+([0-9][^\s)]*)`),
+	},
+	{
+		Name:      "autotools",
+		Files:     []string{"configure", "configure.ac"},
+		BuildDeps: []string{"autoconf", "automake", "gcc", "make"},
+		Build: []string{
+			`[ -x configure ] || autoreconf -fi`,
+			`./configure --prefix=/usr`,
+			`make`,
+		},
+		Package: []string{
+			`make DESTDIR="${pkgdir}" install`,
+		},
+		version: regexpVersion("configure.ac", `AC_INIT\(\s*\[?[^,\]]*\]?\s*,\s*\[?([^,\]\)]+)`),
+	},
+	{
+		Name:      "cargo",
+		Files:     []string{"Cargo.toml"},
+		BuildDeps: []string{"cargo"},
+		Build: []string{
+			`cargo build --release --locked`,
+		},
+		Package: []string{
+			`install-binary "target/release/${name}"`,
+		},
+		version: func(dir string) string {
+			var manifest struct {
+				Package struct {
+					Version string `toml:"version"`
+				} `toml:"package"`
+			}
+			decodeFile(filepath.Join(dir, "Cargo.toml"), toml.Unmarshal, &manifest)
+			return manifest.Package.Version
+		},
+	},
+	{
+		Name:      "go",
+		Files:     []string{"go.mod"},
+		BuildDeps: []string{"golang"},
+		Build: []string{
+			`go build -trimpath -o "${name}" .`,
+		},
+		Package: []string{
+			`install-binary "${name}"`,
+		},
+	},
+	{
+		Name:      "python",
+		Files:     []string{"pyproject.toml"},
+		BuildDeps: []string{"python3", "python3-build", "python3-installer"},
+		Build: []string{
+			`python3 -m build --wheel --no-isolation`,
+		},
+		Package: []string{
+			`python3 -m installer --destdir="${pkgdir}" dist/*.whl`,
+		},
+		version: func(dir string) string {
+			var manifest struct {
+				Project struct {
+					Version string `toml:"version"`
+				} `toml:"project"`
+			}
+			decodeFile(filepath.Join(dir, "pyproject.toml"), toml.Unmarshal, &manifest)
+			return manifest.Project.Version
+		},
+	},
+	{
+		Name:      "npm",
+		Files:     []string{"package.json"},
+		BuildDeps: []string{"nodejs", "npm"},
+		Build: []string{
+			`npm ci`,
+			`npm run build --if-present`,
+			`npm pack`,
+		},
+		Package: []string{
+			`npm install -g --omit=dev --prefix "${pkgdir}/usr" ./*.tgz`,
+		},
+		version: func(dir string) string {
+			var manifest struct {
+				Version string `json:"version"`
+			}
+			decodeFile(filepath.Join(dir, "package.json"), json.Unmarshal, &manifest)
+			return manifest.Version
+		},
+	},
+}
+
+// DetectBuildSystem returns the build system of the source tree in dir,
+// or nil if it is not detected.
+func DetectBuildSystem(dir string) *BuildSystem {
+	for _, bs := range BuildSystems {
+		for _, name := range bs.Files {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				return bs
+			}
+		}
+	}
+	return nil
+}
+
+// Version returns the version of the source tree in dir from the files
+// of the build system, or an empty string.
+func (bs *BuildSystem) Version(dir string) string {
+	if bs == nil || bs.version == nil {
+		return ""
+	}
+	return bs.version(dir)
+}
+
+func regexpVersion(file, expr string) func(dir string) string {
+	re := regexp.MustCompile(expr)
+	return func(dir string) string {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			return ""
+		}
+		m := re.FindSubmatch(data)
+		if m == nil {
+			return ""
+		}
+		return string(m[1])
+	}
+}
+
+// decodeFile decodes the file at path into v. The version is only a
+// hint, so errors leave v unchanged.
+func decodeFile(path string, unmarshal func([]byte, any) error, v any) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	_ = unmarshal(data, v)
+}
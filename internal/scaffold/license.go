@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// licenseFile matches the names of the files with the license
+// of a source tree.
+var licenseFile = regexp.MustCompile(`(?i)^(licen[cs]e|copying|unlicense)([-_.].*)?$`)
+
+// licenseTexts are the SPDX identifiers of licenses and the phrases of
+// their texts, all of which a license file has. The phrases are mostly
+// the titles, as license texts mention other licenses. More specific
+// licenses come first. The GNU licenses are the -only variants, as
+// their texts do not tell if later versions are allowed.
+var licenseTexts = []struct {
+	id      string
+	phrases []string
+}{
+	{"AGPL-3.0-only", []string{"gnu affero general public license version 3"}},
+	{"LGPL-3.0-only", []string{"gnu lesser general public license version 3"}},
+	{"LGPL-2.1-only", []string{"gnu lesser general public license version 2.1"}},
+	{"GPL-3.0-only", []string{"gnu general public license version 3"}},
+	{"GPL-2.0-only", []string{"gnu general public license version 2"}},
+	{"Apache-2.0", []string{"apache license version 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license version 2.0"}},
+	{"BSL-1.0", []string{"boost software license"}},
+	{"Unlicense", []string{"this is free and unencumbered software"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+}
+
+// DetectLicenses returns the SPDX identifiers of the licenses in the
+// license files in the root of the source tree in dir.
+func DetectLicenses(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() || !licenseFile.MatchString(e.Name()) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		if id := identifyLicense(string(data)); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func identifyLicense(text string) string {
+	text = strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	for _, l := range licenseTexts {
+		if containsAll(text, l.phrases) {
+			return l.id
+		}
+	}
+	return ""
+}
+
+func containsAll(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package scaffold generates Staplerfiles for source trees.
+package scaffold
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"go.stplr.dev/stplr/internal/scriptfmt"
+)
+
+// DefaultVersion is the version of a package
+// whose version is not detected.
+const DefaultVersion = "0.1.0"
+
+// Spec is what a generated Staplerfile is made of.
+type Spec struct {
+	Name     string
+	Version  string
+	Licenses []string
+	// Sources and Checksums are the values of the arrays. The version
+	// in them is replaced with the version variable.
+	Sources   []string
+	Checksums []string
+	// Dir is the root of the source tree in the source directory,
+	// or an empty string if the source directory is the root.
+	Dir string
+	// BuildSystem is nil if it is not detected.
+	BuildSystem *BuildSystem
+}
+
+var staplerfileTmpl = template.Must(template.New("Staplerfile").Parse(`name={{.Name}}
+version={{.Version}}
+release=1
+summary=""
+license=({{range .Licenses}}{{.}} {{end}})
+build_deps=({{range .BuildDeps}}{{.}} {{end}})
+sources=({{range .Sources}}{{.}} {{end}})
+checksums=({{range .Checksums}}{{.}} {{end}})
+{{- if .Build}}
+
+build() {
+{{- if .Dir}}
+	cd {{.Dir}}
+{{- end}}
+{{- range .Build}}
+	{{.}}
+{{- end}}
+}
+{{- end}}
+
+package() {
+{{- if .Dir}}
+	cd {{.Dir}}
+{{- end}}
+{{- range .Package}}
+	{{.}}
+{{- end}}
+}
+`))
+
+// unknownPackage is the package function of
+// a source tree without a detected build system.
+var unknownPackage = []string{
+	`# Install the files of the package into "${pkgdir}"`,
+	`true`,
+}
+
+// Generate returns the Staplerfile of spec in the style of scriptfmt.
+func Generate(spec Spec) ([]byte, error) {
+	version := spec.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+
+	data := struct {
+		Name, Version, Dir string
+		Licenses           []string
+		BuildDeps          []string
+		Sources            []string
+		Checksums          []string
+		Build, Package     []string
+	}{
+		Name:      quote(spec.Name),
+		Version:   quote(version),
+		Licenses:  quoteAll(spec.Licenses),
+		Sources:   make([]string, len(spec.Sources)),
+		Checksums: quoteAll(spec.Checksums),
+		Package:   unknownPackage,
+	}
+	for i, src := range spec.Sources {
+		data.Sources[i] = withVersionVar(quote(src), version)
+	}
+	if spec.Dir != "" {
+		data.Dir = `"${srcdir}/` + withVersionVar(escape(spec.Dir), version) + `"`
+	}
+	if bs := spec.BuildSystem; bs != nil {
+		data.BuildDeps = quoteAll(bs.BuildDeps)
+		data.Build = bs.Build
+		data.Package = bs.Package
+	}
+
+	var buf bytes.Buffer
+	if err := staplerfileTmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return scriptfmt.Format(buf.Bytes())
+}
+
+var archiveVersion = regexp.MustCompile(`[-_]v?([0-9]+(?:\.[0-9A-Za-z]+)*(?:[-~+][0-9A-Za-z.]+)?)$`)
+
+// VersionFromName returns the version in the name of an archive without
+// its extension, such as 1.2.3 in foo-1.2.3, or an empty string.
+func VersionFromName(name string) string {
+	m := archiveVersion.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// quote returns s in double quotes for a shell.
+func quote(s string) string {
+	return `"` + escape(s) + `"`
+}
+
+// escape escapes the characters that are special in double quotes.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`").Replace(s)
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return quoted
+}
+
+// withVersionVar replaces version in the escaped string s with the
+// version variable, where it is not part of a longer word or number.
+func withVersionVar(s, version string) string {
+	if version == "" {
+		return s
+	}
+	re := regexp.MustCompile(`(^|[^0-9A-Za-z.]|v)` + regexp.QuoteMeta(escape(version)) + `($|[^0-9A-Za-z.]|\.[^0-9])`)
+	return re.ReplaceAllString(s, "${1}$${version}${2}")
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scaffold_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/scaffold"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestDetectBuildSystem(t *testing.T) {
+	for _, tc := range []struct {
+		files   map[string]string
+		name    string
+		version string
+	}{
+		{map[string]string{"meson.build": "project('foo', 'c',\n  version : '1.2.0')\n"}, "meson", "1.2.0"},
+		{map[string]string{"CMakeLists.txt": "cmake_minimum_required(VERSION 3.10)\nproject(foo VERSION 2.1 LANGUAGES C)\n"}, "cmake", "2.1"},
+		{map[string]string{"configure.ac": "AC_INIT([foo], [0.9.1], [bugs@example.com])\n"}, "autotools", "0.9.1"},
+		{map[string]string{"Cargo.toml": "[package]\nname = \"foo\"\nversion = \"0.3.0\"\n"}, "cargo", "0.3.0"},
+		{map[string]string{"go.mod": "module example.com/foo\n"}, "go", ""},
+		{map[string]string{"pyproject.toml": "[project]\nname = \"foo\"\nversion = \"4.0\"\n"}, "python", "4.0"},
+		{map[string]string{"package.json": `{"name": "foo", "version": "5.0.1"}`}, "npm", "5.0.1"},
+		{map[string]string{"meson.build": "project('foo')\n", "CMakeLists.txt": "project(foo)\n"}, "meson", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := writeFiles(t, tc.files)
+			bs := scaffold.DetectBuildSystem(dir)
+			require.NotNil(t, bs)
+			assert.Equal(t, tc.name, bs.Name)
+			assert.Equal(t, tc.version, bs.Version(dir))
+		})
+	}
+
+	assert.Nil(t, scaffold.DetectBuildSystem(writeFiles(t, map[string]string{"README": "foo"})))
+}
+
+func TestDetectLicenses(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"LICENSE-MIT": "MIT License\n\nPermission is hereby granted, free of charge, to any person\n",
+		"LICENSE-APACHE": "                                 Apache License\n" +
+			"                           Version 2.0, January 2004\n",
+		"COPYING": "                    GNU GENERAL PUBLIC LICENSE\n                       Version 3, 29 June 2007\n" +
+			"use the GNU Lesser General Public License instead of this License.\n",
+		"README": "Permission is hereby granted, free of charge",
+	})
+
+	assert.ElementsMatch(t, []string{"MIT", "Apache-2.0", "GPL-3.0-only"}, scaffold.DetectLicenses(dir))
+}
+
+func TestVersionFromName(t *testing.T) {
+	assert.Equal(t, "1.2.3", scaffold.VersionFromName("foo-1.2.3"))
+	assert.Equal(t, "2.0", scaffold.VersionFromName("foo-bar_v2.0"))
+	assert.Equal(t, "1.0-rc1", scaffold.VersionFromName("foo-1.0-rc1"))
+	assert.Empty(t, scaffold.VersionFromName("foo"))
+}
+
+func TestGenerate(t *testing.T) {
+	out, err := scaffold.Generate(scaffold.Spec{
+		Name:      "foo",
+		Version:   "1.2",
+		Licenses:  []string{"MIT"},
+		Sources:   []string{"local:///foo-1.2.tar.gz"},
+		Checksums: []string{"abcd"},
+		Dir:       "foo-1.2",
+		BuildSystem: &scaffold.BuildSystem{
+			BuildDeps: []string{"gcc", "make"},
+			Build:     []string{"make"},
+			Package:   []string{`make DESTDIR="${pkgdir}" install`},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `name="foo"
+version="1.2"
+release=1
+license=("MIT")
+summary=""
+build_deps=("gcc" "make")
+sources=("local:///foo-${version}.tar.gz")
+checksums=("abcd")
+
+build() {
+    cd "${srcdir}/foo-${version}"
+    make
+}
+
+package() {
+    cd "${srcdir}/foo-${version}"
+    make DESTDIR="${pkgdir}" install
+}
+`, string(out))
+}
+
+func TestGenerateUnknown(t *testing.T) {
+	out, err := scaffold.Generate(scaffold.Spec{Name: "foo"})
+	require.NoError(t, err)
+	assert.Equal(t, `name="foo"
+version="0.1.0"
+release=1
+license=()
+summary=""
+build_deps=()
+sources=()
+checksums=()
+
+package() {
+    # Install the files of the package into "${pkgdir}"
+    true
+}
+`, string(out))
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package scriptedit changes the variables of a Staplerfile in place,
+// keeping the formatting of the rest of the file.
+package scriptedit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// ErrAmbiguous is returned when a variable is assigned more than once
+// in a scope or appended to, so the value it ends up with can't be
+// changed by editing one assignment.
+var ErrAmbiguous = errors.New("is assigned more than once")
+
+// Find returns the assignment of the variable name in scope and the
+// statement it is in. The scope is the name of a function or empty for
+// the top level of the script, which doesn't include the functions.
+// Find returns nils if the variable is not assigned in scope.
+func Find(f *syntax.File, scope, name string) (*syntax.Stmt, *syntax.Assign, error) {
+	var root syntax.Node = f
+	if scope != "" {
+		root = nil
+		for _, stmt := range f.Stmts {
+			if fn, ok := stmt.Cmd.(*syntax.FuncDecl); ok && fn.Name.Value == scope {
+				root = fn.Body
+			}
+		}
+		if root == nil {
+			return nil, nil, nil
+		}
+	}
+
+	var (
+		found  *syntax.Stmt
+		assign *syntax.Assign
+		count  int
+	)
+	syntax.Walk(root, func(node syntax.Node) bool {
+		switch n := node.(type) {
+		case *syntax.FuncDecl:
+			return false
+		case *syntax.Stmt:
+			call, ok := n.Cmd.(*syntax.CallExpr)
+			if !ok || len(call.Args) > 0 {
+				return true
+			}
+			for _, a := range call.Assigns {
+				if a.Name == nil || a.Name.Value != name {
+					continue
+				}
+				count++
+				if a.Append {
+					count++
+				}
+				found, assign = n, a
+			}
+		}
+		return true
+	})
+
+	if count > 1 {
+		return nil, nil, fmt.Errorf("%s %w", name, ErrAmbiguous)
+	}
+	return found, assign, nil
+}
+
+// Editor collects changes to the source of a script.
+type Editor struct {
+	src   []byte
+	edits []edit
+}
+
+type edit struct {
+	start, end int
+	text       string
+}
+
+// NewEditor returns an Editor for the script src,
+// which the positions of the changes refer to.
+func NewEditor(src []byte) *Editor {
+	return &Editor{src: src}
+}
+
+// SetArray replaces the elements of the array assigned by a with
+// values, keeping the layout of the array and the quoting of its
+// first element.
+func (e *Editor) SetArray(a *syntax.Assign, values []string) error {
+	if a.Array == nil {
+		return fmt.Errorf("%s is not an array", a.Name.Value)
+	}
+
+	e.edits = append(e.edits, edit{
+		start: int(a.Array.Lparen.Offset()),
+		end:   int(a.Array.Rparen.Offset()) + 1,
+		text:  e.layoutOf(a.Array).format(values),
+	})
+	return nil
+}
+
+// AddArrayAfter assigns the array values to name on a new line after
+// stmt, laid out like the array assigned by like.
+func (e *Editor) AddArrayAfter(stmt *syntax.Stmt, like *syntax.Assign, name string, values []string) {
+	l := layout{quote: '"'}
+	if like != nil && like.Array != nil {
+		l = e.layoutOf(like.Array)
+	}
+
+	// after the comments at the end of the line of stmt
+	end := int(stmt.End().Offset())
+	if i := bytes.IndexByte(e.src[end:], '\n'); i >= 0 {
+		end += i
+	} else {
+		end = len(e.src)
+	}
+
+	e.edits = append(e.edits, edit{
+		start: end,
+		end:   end,
+		text:  "\n" + e.indentOf(int(stmt.Pos().Offset())) + name + "=" + l.format(values),
+	})
+}
+
+// Bytes returns the source with the changes applied.
+func (e *Editor) Bytes() []byte {
+	edits := append([]edit(nil), e.edits...)
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].start > edits[j].start
+	})
+
+	out := bytes.Clone(e.src)
+	for _, ed := range edits {
+		out = append(out[:ed.start], append([]byte(ed.text), out[ed.end:]...)...)
+	}
+	return out
+}
+
+// indentOf returns the whitespace at the start of the line of offset.
+func (e *Editor) indentOf(offset int) string {
+	start := bytes.LastIndexByte(e.src[:offset], '\n') + 1
+	line := e.src[start:offset]
+	return string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+}
+
+type layout struct {
+	multiline bool
+	indent    string
+	// closeIndent is the indentation of the closing parenthesis
+	// on its own line, or nil if it follows the last element.
+	closeIndent *string
+	quote       byte
+}
+
+func (e *Editor) layoutOf(arr *syntax.ArrayExpr) layout {
+	l := layout{quote: '"'}
+	if len(arr.Elems) == 0 {
+		return l
+	}
+
+	first := arr.Elems[0]
+	if first.Value != nil && len(first.Value.Parts) > 0 {
+		switch first.Value.Parts[0].(type) {
+		case *syntax.SglQuoted:
+			l.quote = '\''
+		case *syntax.Lit:
+			l.quote = 0
+		}
+	}
+
+	if first.Pos().Line() > arr.Lparen.Line() {
+		l.multiline = true
+		l.indent = e.indentOf(int(first.Pos().Offset()))
+		if last := arr.Elems[len(arr.Elems)-1]; arr.Rparen.Line() > last.End().Line() {
+			indent := e.indentOf(int(arr.Rparen.Offset()))
+			l.closeIndent = &indent
+		}
+	}
+	return l
+}
+
+func (l layout) format(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		if l.quote != 0 {
+			quoted[i] = string(l.quote) + v + string(l.quote)
+		} else {
+			quoted[i] = v
+		}
+	}
+
+	if !l.multiline {
+		return "(" + strings.Join(quoted, " ") + ")"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("(")
+	for _, q := range quoted {
+		sb.WriteString("\n" + l.indent + q)
+	}
+	if l.closeIndent != nil {
+		sb.WriteString("\n" + *l.closeIndent)
+	}
+	sb.WriteString(")")
+	return sb.String()
+}
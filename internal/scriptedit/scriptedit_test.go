@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scriptedit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/internal/scriptedit"
+)
+
+const script = `name=foo
+version=1.0
+
+# upstream tarball
+sources=("https://example.com/foo-$version.tar.gz" 'local:///foo.patch')
+checksums=('SKIP' 'SKIP') # fill in
+
+sources_amd64=(
+	"https://example.com/foo-$version-amd64.bin"
+)
+
+checksums_amd64=(
+	'aaaa'
+	'bbbb'
+)
+
+meta_foo_doc() {
+	sources_arm64=(
+		"https://example.com/doc.tar.gz"
+		"https://example.com/doc.tar.gz.sig")
+	checksums=(cccc)
+}
+
+build() {
+	checksums=(dddd)
+}
+`
+
+func parse(t *testing.T, src string) *syntax.File {
+	t.Helper()
+	f, err := syntax.NewParser().Parse(strings.NewReader(src), "Staplerfile")
+	require.NoError(t, err)
+	return f
+}
+
+func TestFind(t *testing.T) {
+	f := parse(t, script)
+
+	_, a, err := scriptedit.Find(f, "", "checksums")
+	require.NoError(t, err)
+	require.NotNil(t, a)
+	assert.Equal(t, uint(6), a.Pos().Line())
+
+	_, a, err = scriptedit.Find(f, "meta_foo_doc", "checksums")
+	require.NoError(t, err)
+	require.NotNil(t, a)
+	assert.Equal(t, uint(21), a.Pos().Line())
+
+	_, a, err = scriptedit.Find(f, "meta_foo_doc", "checksums_amd64")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	_, a, err = scriptedit.Find(f, "meta_missing", "checksums")
+	require.NoError(t, err)
+	assert.Nil(t, a)
+
+	f = parse(t, "checksums=(a)\nif true; then checksums=(b); fi\n")
+	_, _, err = scriptedit.Find(f, "", "checksums")
+	assert.ErrorIs(t, err, scriptedit.ErrAmbiguous)
+
+	f = parse(t, "checksums+=(a)\n")
+	_, _, err = scriptedit.Find(f, "", "checksums")
+	assert.ErrorIs(t, err, scriptedit.ErrAmbiguous)
+}
+
+func TestEditor(t *testing.T) {
+	f := parse(t, script)
+	e := scriptedit.NewEditor([]byte(script))
+
+	_, a, err := scriptedit.Find(f, "", "checksums")
+	require.NoError(t, err)
+	require.NoError(t, e.SetArray(a, []string{"1111", "2222"}))
+
+	_, a, err = scriptedit.Find(f, "", "checksums_amd64")
+	require.NoError(t, err)
+	require.NoError(t, e.SetArray(a, []string{"3333"}))
+
+	_, a, err = scriptedit.Find(f, "meta_foo_doc", "checksums")
+	require.NoError(t, err)
+	require.NoError(t, e.SetArray(a, []string{"4444", "5555"}))
+
+	stmt, like, err := scriptedit.Find(f, "meta_foo_doc", "sources_arm64")
+	require.NoError(t, err)
+	e.AddArrayAfter(stmt, like, "checksums_arm64", []string{"6666", "SKIP"})
+
+	assert.Equal(t, `name=foo
+version=1.0
+
+# upstream tarball
+sources=("https://example.com/foo-$version.tar.gz" 'local:///foo.patch')
+checksums=('1111' '2222') # fill in
+
+sources_amd64=(
+	"https://example.com/foo-$version-amd64.bin"
+)
+
+checksums_amd64=(
+	'3333'
+)
+
+meta_foo_doc() {
+	sources_arm64=(
+		"https://example.com/doc.tar.gz"
+		"https://example.com/doc.tar.gz.sig")
+	checksums_arm64=(
+		"6666"
+		"SKIP")
+	checksums=(4444 5555)
+}
+
+build() {
+	checksums=(dddd)
+}
+`, string(e.Bytes()))
+}
+
+func TestSetArrayNotArray(t *testing.T) {
+	f := parse(t, "checksums=SKIP\n")
+	_, a, err := scriptedit.Find(f, "", "checksums")
+	require.NoError(t, err)
+	assert.Error(t, scriptedit.NewEditor(nil).SetArray(a, []string{"1111"}))
+}
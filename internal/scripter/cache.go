@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/buildcache"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// writeCacheManifest records the inputs of the package at pkgPath,
+// so that it is reused only while they are unchanged. A package
+// without a manifest is rebuilt, so errors are only reported.
+func (e *LocalScriptExecutor) writeCacheManifest(bctx packageBuildContext, vars *staplerfile.Package, pkgPath string) {
+	m, err := buildcache.New(e.cfg, bctx.input, vars)
+	if err == nil {
+		if wantsDebugInfo(e.cfg, vars) {
+			// the debug info package is built next if the debug info
+			// was split, see buildDebugInfoPackage
+			_, statErr := os.Stat(debugInfoDir(bctx.dirs, vars.Name))
+			m.DebugInfo = statErr == nil
+		}
+		err = m.Write(pkgPath)
+	}
+	if err != nil {
+		e.out.Warn(gotext.Get("Failed to write the cache manifest of %q: %s", vars.Name, err))
+	}
+}
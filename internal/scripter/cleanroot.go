@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/cleanroot"
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/shutils/handlers"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+// prepareCleanRoot unpacks image and installs the build dependencies
+// into it. The installation runs in its own sandbox, because the build
+// one may have no network access.
+func (e *LocalScriptExecutor) prepareCleanRoot(
+	ctx context.Context,
+	input *commonbuild.BuildInput,
+	dirs types.Directories,
+	image string,
+	out io.Writer,
+) (string, error) {
+	e.out.Info(gotext.Get("Preparing clean root from %s", image))
+
+	rootfs, err := cleanroot.Prepare(image, dirs.BaseDir)
+	if err != nil {
+		return "", fmt.Errorf("preparing clean root: %w", err)
+	}
+
+	if len(input.CleanRootDepends) > 0 || len(input.CleanRootPackages) > 0 {
+		e.out.Info(gotext.Get("Installing build dependencies into the clean root"))
+
+		sandbox, err := handlers.SandboxHandler(2*time.Second, dirs, false, handlers.WithRootfs(rootfs))
+		if err != nil {
+			_ = cleanroot.Remove(rootfs)
+			return "", fmt.Errorf("creating sandbox handler: %w", err)
+		}
+		err = cleanroot.Install(ctx, sandbox, rootfs, input.CleanRootDepends, input.CleanRootPackages, out)
+		sandbox.Cleanup()
+		if err != nil {
+			_ = cleanroot.Remove(rootfs)
+			return "", fmt.Errorf("installing build dependencies into the clean root: %w", err)
+		}
+	}
+
+	return rootfs, nil
+}
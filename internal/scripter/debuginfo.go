@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"bytes"
+	"context"
+	"debug/elf"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+// debugInfoSuffix returns the suffix that distros of format give
+// to debug info packages.
+func debugInfoSuffix(format string) string {
+	switch format {
+	case "rpm":
+		return "-debuginfo"
+	case "deb":
+		return "-dbgsym"
+	case "apk":
+		return "-dbg"
+	}
+	return "-debug"
+}
+
+// debugInfoDir is the root of the debug info package of the package name.
+func debugInfoDir(dirs types.Directories, name string) string {
+	return filepath.Join(dirs.BaseDir, "debug", name)
+}
+
+func wantsDebugInfo(cfg commonbuild.Config, vars *staplerfile.Package) bool {
+	return vars.DebugInfo.Resolved() || cfg.DebugInfo()
+}
+
+// splitDebugInfo moves the debug info of the ELF files in pkgDir under
+// /usr/lib/debug of debugRoot, the way distro tooling does. The files get
+// a .gnu_debuglink to their debug info, which is also linked from the
+// .build-id directory. Only the files in contents are split, if it is
+// not nil. The files are stripped with the flags of the strip option
+// if strip is set, otherwise only their debug sections are removed.
+func (e *LocalScriptExecutor) splitDebugInfo(
+	ctx context.Context,
+	pkgDir, debugRoot string,
+	contents *[]string,
+	strip bool,
+) error {
+	objcopy, err := exec.LookPath("objcopy")
+	if err != nil {
+		e.out.Warn(gotext.Get("objcopy is not installed, debug info is not split"))
+		return nil
+	}
+	stripBin, err := exec.LookPath("strip")
+	if err != nil {
+		e.out.Warn(gotext.Get("strip is not installed, debug info is not split"))
+		return nil
+	}
+
+	if err := os.RemoveAll(debugRoot); err != nil {
+		return err
+	}
+
+	skip := filepath.Join(pkgDir, debugDir)
+	return filepath.WalkDir(pkgDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(pkgDir, path)
+		if err != nil {
+			return err
+		}
+		if !inContents(contents, rel) {
+			return nil
+		}
+
+		buildID, ok, err := debugInfoOf(path)
+		if err != nil || !ok {
+			return err
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		debugFile := filepath.Join(debugRoot, debugDir, rel+".debug")
+		if err := os.MkdirAll(filepath.Dir(debugFile), 0o755); err != nil {
+			return err
+		}
+
+		stripArgs := []string{"--strip-debug"}
+		if strip {
+			if stripArgs, err = stripFlags(path); err != nil {
+				return err
+			}
+		}
+
+		err = withWritable(path, fi, func() error {
+			if err := runTool(ctx, objcopy, "--only-keep-debug", path, debugFile); err != nil {
+				return err
+			}
+			if err := runTool(ctx, stripBin, append(stripArgs, path)...); err != nil {
+				return err
+			}
+			return runTool(ctx, objcopy, "--remove-section=.gnu_debuglink", "--add-gnu-debuglink="+debugFile, path)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		if err := os.Chmod(debugFile, 0o644); err != nil {
+			return err
+		}
+
+		if len(buildID) < 3 {
+			return nil
+		}
+		link := filepath.Join(debugRoot, debugDir, ".build-id", buildID[:2], buildID[2:]+".debug")
+		if err := os.MkdirAll(filepath.Dir(link), 0o755); err != nil {
+			return err
+		}
+		// .build-id/xx/ is two levels below /usr/lib/debug
+		return os.Symlink(filepath.Join("..", "..", rel+".debug"), link)
+	})
+}
+
+// inContents reports whether rel is one of contents or in one of
+// its directories. Everything is included if contents is nil.
+func inContents(contents *[]string, rel string) bool {
+	if contents == nil {
+		return true
+	}
+	for _, c := range *contents {
+		c = strings.Trim(filepath.Clean("/"+c), "/")
+		if c == "" || rel == c || strings.HasPrefix(rel, c+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// debugInfoOf reports whether the file at path is an executable or
+// shared library with debug info and returns its build ID, if any.
+func debugInfoOf(path string) (string, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(elf.ELFMAG))
+	if _, err := f.ReadAt(magic, 0); err != nil || string(magic) != elf.ELFMAG {
+		return "", false, nil
+	}
+
+	ef, err := elf.NewFile(f)
+	if err != nil {
+		return "", false, nil
+	}
+	defer ef.Close()
+
+	if ef.Type != elf.ET_EXEC && ef.Type != elf.ET_DYN {
+		return "", false, nil
+	}
+	if ef.Section(".debug_info") == nil && ef.Section(".zdebug_info") == nil {
+		return "", false, nil
+	}
+
+	return buildIDOf(ef), true, nil
+}
+
+// ntGNUBuildID is the note type of GNU build IDs.
+const ntGNUBuildID = 3
+
+// buildIDOf returns the GNU build ID of f in hex, or "" if it has none.
+func buildIDOf(f *elf.File) string {
+	s := f.Section(".note.gnu.build-id")
+	if s == nil {
+		return ""
+	}
+	data, err := s.Data()
+	if err != nil || len(data) < 16 {
+		return ""
+	}
+
+	// Elf_Nhdr: namesz, descsz and type, then the name padded to 4 bytes
+	order := f.ByteOrder
+	namesz := order.Uint32(data[0:4])
+	descsz := order.Uint32(data[4:8])
+	if order.Uint32(data[8:12]) != ntGNUBuildID {
+		return ""
+	}
+	start := 12 + (uint64(namesz)+3)&^3
+	end := start + uint64(descsz)
+	if end > uint64(len(data)) || !bytes.Equal(data[12:12+namesz], []byte("GNU\x00")) {
+		return ""
+	}
+	return hex.EncodeToString(data[start:end])
+}
+
+// DebugInfoPackage returns the package that holds the split debug
+// info of vars.
+func DebugInfoPackage(vars *staplerfile.Package, format string) *staplerfile.Package {
+	pkg := &staplerfile.Package{
+		Options:       &staplerfile.ScriptOptions{},
+		Repository:    vars.Repository,
+		Name:          vars.Name + debugInfoSuffix(format),
+		BasePkgName:   vars.BasePkgName,
+		Version:       vars.Version,
+		Release:       vars.Release,
+		Epoch:         vars.Epoch,
+		Architectures: vars.Architectures,
+		Licenses:      vars.Licenses,
+	}
+
+	desc := fmt.Sprintf("Debug information for package %s", vars.Name)
+	pkg.Summary.SetResolved(desc)
+	pkg.Description.SetResolved(desc)
+	pkg.Homepage.SetResolved(vars.Homepage.Resolved())
+	pkg.Maintainer.SetResolved(vars.Maintainer.Resolved())
+	pkg.Group.SetResolved(vars.Group.Resolved())
+	pkg.Depends.SetResolved([]string{vars.Name})
+	return pkg
+}
+
+// buildDebugInfoPackage packages the debug info split from vars.
+// It returns nil if there is none.
+func (e *LocalScriptExecutor) buildDebugInfoPackage(
+	ctx context.Context,
+	bctx packageBuildContext,
+	vars *staplerfile.Package,
+) (*commonbuild.BuiltDep, error) {
+	dirs := bctx.dirs
+	dirs.PkgDir = debugInfoDir(bctx.dirs, vars.Name)
+	if _, err := os.Stat(dirs.PkgDir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	dbg := DebugInfoPackage(vars, bctx.input.PkgFormat())
+	e.out.Info(gotext.Get("Building debug info package %q", dbg.Name))
+
+	pkgInfo, err := buildPkgMetadata(ctx, e.cfg, e.out, bctx.input, dbg, dirs, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building metadata: %w", err)
+	}
+
+	dep, err := e.writePackage(ctx, bctx, dbg, pkgInfo)
+	if err != nil {
+		return nil, err
+	}
+	dep.DebugInfo = true
+	return dep, nil
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"debug/elf"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+func TestDebugInfoPackage(t *testing.T) {
+	vars := &staplerfile.Package{Name: "foo", Version: "1.0", Release: 2}
+	vars.Homepage.SetResolved("https://example.com")
+
+	for format, name := range map[string]string{
+		"rpm":       "foo-debuginfo",
+		"deb":       "foo-dbgsym",
+		"apk":       "foo-dbg",
+		"archlinux": "foo-debug",
+	} {
+		pkg := DebugInfoPackage(vars, format)
+		assert.Equal(t, name, pkg.Name)
+		assert.Equal(t, "1.0", pkg.Version)
+		assert.Equal(t, 2, pkg.Release)
+		assert.Equal(t, "https://example.com", pkg.Homepage.Resolved())
+		assert.Equal(t, []string{"foo"}, pkg.Depends.Resolved())
+	}
+}
+
+func TestInContents(t *testing.T) {
+	assert.True(t, inContents(nil, "usr/bin/foo"))
+
+	contents := []string{"/usr/bin/foo", "./usr/lib/foo"}
+	assert.True(t, inContents(&contents, "usr/bin/foo"))
+	assert.True(t, inContents(&contents, "usr/lib/foo/libfoo.so"))
+	assert.False(t, inContents(&contents, "usr/bin/foobar"))
+	assert.False(t, inContents(&contents, "usr/lib/libbar.so"))
+}
+
+func TestSplitDebugInfo(t *testing.T) {
+	for _, tool := range []string{"cc", "objcopy", "strip"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s is not installed", tool)
+		}
+	}
+
+	src := filepath.Join(t.TempDir(), "main.c")
+	require.NoError(t, os.WriteFile(src, []byte("int main(void) { return 0; }\n"), 0o644))
+
+	pkgDir := t.TempDir()
+	bin := filepath.Join(pkgDir, "usr/bin/foo")
+	require.NoError(t, os.MkdirAll(filepath.Dir(bin), 0o755))
+	out, err := exec.Command("cc", "-g", "-Wl,--build-id", "-o", bin, src).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.Chmod(bin, 0o555))
+
+	debugRoot := filepath.Join(t.TempDir(), "debug")
+	e := NewLocalScriptExecutor(nil, output.NewConsoleOutput())
+	require.NoError(t, e.splitDebugInfo(t.Context(), pkgDir, debugRoot, nil, true))
+
+	f, err := elf.Open(bin)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Nil(t, f.Section(".debug_info"))
+	assert.Nil(t, f.Section(".symtab"))
+	require.NotNil(t, f.Section(".gnu_debuglink"))
+	buildID := buildIDOf(f)
+	require.NotEmpty(t, buildID)
+
+	fi, err := os.Stat(bin)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o555), fi.Mode().Perm())
+
+	debugFile := filepath.Join(debugRoot, "usr/lib/debug/usr/bin/foo.debug")
+	df, err := elf.Open(debugFile)
+	require.NoError(t, err)
+	defer df.Close()
+	assert.NotNil(t, df.Section(".debug_info"))
+
+	link := filepath.Join(debugRoot, "usr/lib/debug/.build-id", buildID[:2], buildID[2:]+".debug")
+	target, err := filepath.EvalSymlinks(link)
+	require.NoError(t, err)
+	resolved, err := filepath.EvalSymlinks(debugFile)
+	require.NoError(t, err)
+	assert.Equal(t, resolved, target)
+}
+
+func TestSplitDebugInfoNoDebugInfo(t *testing.T) {
+	pkgDir := t.TempDir()
+	writeTestFile(t, filepath.Join(pkgDir, "usr/bin/foo"), "#!/bin/sh\n")
+
+	debugRoot := filepath.Join(t.TempDir(), "debug")
+	e := NewLocalScriptExecutor(nil, output.NewConsoleOutput())
+	require.NoError(t, e.splitDebugInfo(t.Context(), pkgDir, debugRoot, nil, true))
+	assert.NoDirExists(t, debugRoot)
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"fmt"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/config/common"
+	"go.stplr.dev/stplr/internal/shutils/handlers"
+)
+
+// hardeningOptions returns the seccomp profile and the capabilities
+// of the build sandbox. If stplr was built without seccomp, any profile
+// but an explicitly unconfined one fails the build.
+func hardeningOptions(cfg commonbuild.Config) ([]handlers.SandboxOption, error) {
+	var opts []handlers.SandboxOption
+
+	switch path := cfg.SandboxSeccompProfile(); path {
+	case handlers.SeccompUnconfined:
+	case "":
+		if !handlers.SeccompSupported() {
+			return nil, fmt.Errorf("stplr was built without seccomp, set %s to %q to build without a seccomp filter: %w",
+				common.SANDBOX_SECCOMP_PROFILE, handlers.SeccompUnconfined, handlers.ErrSeccompUnsupported)
+		}
+		opts = append(opts, handlers.WithSeccomp(handlers.DefaultSeccompProfile()))
+	default:
+		if !handlers.SeccompSupported() {
+			return nil, fmt.Errorf("loading seccomp profile %s: %w", path, handlers.ErrSeccompUnsupported)
+		}
+		profile, err := handlers.LoadSeccompProfile(path)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, handlers.WithSeccomp(profile))
+	}
+
+	if names := cfg.SandboxCapabilities(); len(names) > 0 {
+		caps, err := handlers.ParseCapabilities(names)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sandbox capabilities: %w", err)
+		}
+		opts = append(opts, handlers.WithCapabilities(caps))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/shutils/handlers"
+)
+
+type hardeningConfig struct {
+	commonbuild.Config
+	seccompProfile string
+	capabilities   []string
+}
+
+func (c *hardeningConfig) SandboxSeccompProfile() string { return c.seccompProfile }
+func (c *hardeningConfig) SandboxCapabilities() []string { return c.capabilities }
+
+func TestHardeningOptions(t *testing.T) {
+	opts, err := hardeningOptions(&hardeningConfig{seccompProfile: handlers.SeccompUnconfined})
+	require.NoError(t, err)
+	assert.Empty(t, opts)
+
+	opts, err = hardeningOptions(&hardeningConfig{seccompProfile: handlers.SeccompUnconfined, capabilities: []string{"chown"}})
+	require.NoError(t, err)
+	assert.Len(t, opts, 1)
+
+	_, err = hardeningOptions(&hardeningConfig{seccompProfile: handlers.SeccompUnconfined, capabilities: []string{"sys_magic"}})
+	assert.ErrorContains(t, err, "invalid sandbox capabilities")
+
+	opts, err = hardeningOptions(&hardeningConfig{})
+	if handlers.SeccompSupported() {
+		require.NoError(t, err)
+		assert.Len(t, opts, 1)
+	} else {
+		assert.ErrorIs(t, err, handlers.ErrSeccompUnsupported)
+	}
+
+	path := filepath.Join(t.TempDir(), "profile.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"defaultAction": "SCMP_ACT_ALLOW"}`), 0o644))
+	opts, err = hardeningOptions(&hardeningConfig{seccompProfile: path})
+	if handlers.SeccompSupported() {
+		require.NoError(t, err)
+		assert.Len(t, opts, 1)
+	} else {
+		assert.ErrorIs(t, err, handlers.ErrSeccompUnsupported)
+	}
+}
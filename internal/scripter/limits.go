@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/shutils/handlers"
+	"go.stplr.dev/stplr/internal/units"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// BuildLimits are the resource limits of the sandbox of a build
+// and the time every build function may run for.
+type BuildLimits struct {
+	Sandbox handlers.Limits
+	Timeout time.Duration
+}
+
+// resolveBuildLimits returns the limits of building pkgs. The
+// variables of the script override the limits of the config.
+func resolveBuildLimits(cfg commonbuild.Config, pkgs []*staplerfile.Package) (BuildLimits, error) {
+	value := func(cfgValue string, field func(*staplerfile.Package) string) string {
+		for _, pkg := range pkgs {
+			if v := field(pkg); v != "" {
+				return v
+			}
+		}
+		return cfgValue
+	}
+
+	var l BuildLimits
+
+	if v := value(cfg.BuildMemoryMax(), func(p *staplerfile.Package) string { return p.BuildMemoryMax.Resolved() }); v != "" {
+		size, err := units.ParseSize(v)
+		if err != nil {
+			return l, fmt.Errorf("invalid build memory limit %q", v)
+		}
+		l.Sandbox.MemoryMax = size
+	}
+
+	if v := value(cfg.BuildCPUMax(), func(p *staplerfile.Package) string { return p.BuildCPUMax.Resolved() }); v != "" {
+		cpus, err := strconv.ParseFloat(v, 64)
+		if err != nil || cpus < 0 {
+			return l, fmt.Errorf("invalid build CPU limit %q", v)
+		}
+		l.Sandbox.CPUMax = cpus
+	}
+
+	if v := value(cfg.BuildPidsMax(), func(p *staplerfile.Package) string { return p.BuildPidsMax.Resolved() }); v != "" {
+		pids, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || pids < 0 {
+			return l, fmt.Errorf("invalid build process limit %q", v)
+		}
+		l.Sandbox.PidsMax = pids
+	}
+
+	if v := value(cfg.BuildIOWeight(), func(p *staplerfile.Package) string { return p.BuildIOWeight.Resolved() }); v != "" {
+		weight, err := strconv.ParseUint(v, 10, 16)
+		if err != nil || weight != 0 && (weight < 10 || weight > 1000) {
+			return l, fmt.Errorf("invalid build I/O weight %q, it must be from 10 to 1000", v)
+		}
+		l.Sandbox.IOWeight = uint16(weight)
+	}
+
+	if v := value(cfg.BuildTimeout(), func(p *staplerfile.Package) string { return p.BuildTimeout.Resolved() }); v != "" {
+		timeout, err := units.ParseAge(v)
+		if err != nil {
+			return l, fmt.Errorf("invalid build timeout %q", v)
+		}
+		l.Timeout = timeout
+	}
+
+	return l, nil
+}
+
+// runLimited runs the build function name with the timeout of l and
+// tells which limit stopped it, if any.
+func runLimited(ctx context.Context, l BuildLimits, name string, fn func(ctx context.Context) error) error {
+	fnCtx := ctx
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		fnCtx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+
+	err := fn(fnCtx)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(fnCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("%s() was stopped after running for longer than the build timeout of %s: %w", name, l.Timeout, context.DeadlineExceeded)
+	}
+
+	var limitErr *handlers.LimitError
+	if errors.As(err, &limitErr) {
+		switch limitErr.Resource {
+		case handlers.ResourceMemory:
+			return fmt.Errorf("%s() was killed for exceeding the build memory limit of %s: %w", name, units.FormatSize(l.Sandbox.MemoryMax), err)
+		case handlers.ResourcePids:
+			return fmt.Errorf("%s() exceeded the build limit of %d processes: %w", name, l.Sandbox.PidsMax, err)
+		}
+	}
+
+	return err
+}
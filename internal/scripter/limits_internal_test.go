@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/shutils/handlers"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+type limitsConfig struct {
+	commonbuild.Config
+	memoryMax, cpuMax, pidsMax, ioWeight, timeout string
+}
+
+func (c *limitsConfig) BuildMemoryMax() string { return c.memoryMax }
+func (c *limitsConfig) BuildCPUMax() string    { return c.cpuMax }
+func (c *limitsConfig) BuildPidsMax() string   { return c.pidsMax }
+func (c *limitsConfig) BuildIOWeight() string  { return c.ioWeight }
+func (c *limitsConfig) BuildTimeout() string   { return c.timeout }
+
+func TestResolveBuildLimits(t *testing.T) {
+	cfg := &limitsConfig{memoryMax: "4G", cpuMax: "2", pidsMax: "1024", ioWeight: "100", timeout: "1h"}
+
+	l, err := resolveBuildLimits(cfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, BuildLimits{
+		Sandbox: handlers.Limits{MemoryMax: 4 << 30, CPUMax: 2, PidsMax: 1024, IOWeight: 100},
+		Timeout: time.Hour,
+	}, l)
+
+	pkg := &staplerfile.Package{}
+	pkg.BuildMemoryMax.SetResolved("16G")
+	pkg.BuildTimeout.SetResolved("3h")
+	l, err = resolveBuildLimits(cfg, []*staplerfile.Package{{}, pkg})
+	require.NoError(t, err)
+	assert.Equal(t, int64(16<<30), l.Sandbox.MemoryMax)
+	assert.Equal(t, 2.0, l.Sandbox.CPUMax)
+	assert.Equal(t, 3*time.Hour, l.Timeout)
+
+	l, err = resolveBuildLimits(&limitsConfig{}, nil)
+	require.NoError(t, err)
+	assert.True(t, l.Sandbox.IsZero())
+	assert.Zero(t, l.Timeout)
+
+	for _, cfg := range []*limitsConfig{
+		{memoryMax: "lots"},
+		{cpuMax: "-1"},
+		{pidsMax: "1.5"},
+		{ioWeight: "5"},
+		{timeout: "soon"},
+	} {
+		_, err := resolveBuildLimits(cfg, nil)
+		assert.Error(t, err, fmt.Sprintf("%+v", *cfg))
+	}
+}
+
+func TestRunLimited(t *testing.T) {
+	l := BuildLimits{
+		Sandbox: handlers.Limits{MemoryMax: 1 << 30, PidsMax: 64},
+		Timeout: 10 * time.Millisecond,
+	}
+
+	err := runLimited(t.Context(), l, "build", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "build() was stopped")
+
+	err = runLimited(t.Context(), l, "build", func(ctx context.Context) error {
+		return &handlers.LimitError{Resource: handlers.ResourceMemory}
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "build() was killed for exceeding the build memory limit of 1.0 GiB")
+
+	err = runLimited(t.Context(), l, "check", func(ctx context.Context) error {
+		return &handlers.LimitError{Resource: handlers.ResourcePids}
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "check() exceeded the build limit of 64 processes")
+
+	failed := errors.New("exit status 1")
+	err = runLimited(t.Context(), BuildLimits{}, "build", func(ctx context.Context) error {
+		return failed
+	})
+	assert.Equal(t, failed, err)
+}
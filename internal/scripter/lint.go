@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/pkglint"
+)
+
+// lintPackage checks the contents and metadata of the package before
+// it is written. Findings are printed and errors fail the build.
+func (e *LocalScriptExecutor) lintPackage(bctx packageBuildContext, pkgInfo *nfpm.Info) error {
+	levels, err := pkglint.ParseLevels(bctx.input.LintLevels)
+	if err != nil {
+		return fmt.Errorf("lint settings of repo %q: %w", bctx.input.Repository(), err)
+	}
+
+	l := pkglint.New(levels, []string{bctx.dirs.SrcDir, bctx.dirs.PkgDir})
+	for _, c := range pkgInfo.Contents {
+		if err := lintContent(l, c); err != nil {
+			return err
+		}
+	}
+
+	findings := l.Finish(pkglint.Metadata{Name: pkgInfo.Name, License: pkgInfo.License})
+	for _, f := range findings {
+		if f.Severity == pkglint.SeverityError {
+			e.out.Error("%s", f.String())
+		} else {
+			e.out.Warn("%s", f.String())
+		}
+	}
+	if pkglint.HasErrors(findings) {
+		return fmt.Errorf("%s", gotext.Get("Package %s has lint errors", pkgInfo.Name))
+	}
+
+	return nil
+}
+
+func lintContent(l *pkglint.Linter, c *files.Content) error {
+	switch c.Type {
+	case files.TypeRPMGhost:
+		return nil
+	case files.TypeSymlink:
+		return l.CheckFile(c.Destination, fs.ModeSymlink|0o777, nil)
+	}
+
+	st, err := os.Lstat(c.Source)
+	if err != nil {
+		return err
+	}
+	// nfpm prefers the mode from FileInfo
+	mode := st.Mode()
+	if c.FileInfo != nil && c.FileInfo.Mode != 0 {
+		mode = c.FileInfo.Mode
+	}
+	if !mode.IsRegular() {
+		return l.CheckFile(c.Destination, mode, nil)
+	}
+
+	f, err := os.Open(c.Source)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return l.CheckFile(c.Destination, mode, f)
+}
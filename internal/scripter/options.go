@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PackageOptions control how the contents of $pkgdir are tidied
+// before they are packaged, like the options array of makepkg.
+type PackageOptions struct {
+	// Strip strips symbols from ELF files.
+	Strip bool
+	// Debug keeps the debug info of ELF files, they are not stripped.
+	Debug bool
+	// EmptyDirs keeps empty directories.
+	EmptyDirs bool
+	// Docs keeps the doc directories.
+	Docs bool
+	// Libtool keeps libtool .la files.
+	Libtool bool
+	// StaticLibs keeps static libraries that have a shared counterpart.
+	StaticLibs bool
+	// ZipMan compresses man and info pages with gzip.
+	ZipMan bool
+}
+
+// DefaultPackageOptions leave the contents as the script installed them.
+func DefaultPackageOptions() PackageOptions {
+	return PackageOptions{
+		EmptyDirs:  true,
+		Docs:       true,
+		Libtool:    true,
+		StaticLibs: true,
+	}
+}
+
+// ParsePackageOptions applies lists of options such as strip or !docs
+// to the defaults. Later lists override earlier ones, so the options
+// of a script should go after the ones from the config.
+func ParsePackageOptions(lists ...[]string) (PackageOptions, error) {
+	opts := DefaultPackageOptions()
+	for _, list := range lists {
+		for _, o := range list {
+			name, negated := strings.CutPrefix(o, "!")
+			value := !negated
+
+			switch name {
+			case "strip":
+				opts.Strip = value
+			case "debug":
+				opts.Debug = value
+			case "emptydirs":
+				opts.EmptyDirs = value
+			case "docs":
+				opts.Docs = value
+			case "libtool":
+				opts.Libtool = value
+			case "staticlibs":
+				opts.StaticLibs = value
+			case "zipman":
+				opts.ZipMan = value
+			default:
+				return opts, fmt.Errorf("unknown package option %q", o)
+			}
+		}
+	}
+	return opts, nil
+}
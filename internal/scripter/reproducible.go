@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+)
+
+// reproducibleBuildHost replaces the host name that rpm records in
+// packages, so that it doesn't depend on the build machine.
+const reproducibleBuildHost = "stplr"
+
+// makeReproducible removes the parts of pkgInfo that differ between two
+// builds of the same sources: file times newer than sourceDateEpoch are
+// clamped to it, contents are sorted and build host fields are fixed.
+func makeReproducible(pkgInfo *nfpm.Info, sourceDateEpoch int64) {
+	epoch := time.Unix(sourceDateEpoch, 0).UTC()
+
+	pkgInfo.MTime = epoch
+	pkgInfo.RPM.BuildHost = reproducibleBuildHost
+
+	for _, content := range pkgInfo.Contents {
+		if content.FileInfo == nil {
+			content.FileInfo = &files.ContentFileInfo{}
+		}
+		if content.FileInfo.MTime.IsZero() || content.FileInfo.MTime.After(epoch) {
+			content.FileInfo.MTime = epoch
+		}
+	}
+
+	slices.SortStableFunc(pkgInfo.Contents, func(a, b *files.Content) int {
+		return strings.Compare(a.Destination, b.Destination)
+	})
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/goreleaser/nfpm/v2/files"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeReproducible(t *testing.T) {
+	epoch := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	older := epoch.Add(-time.Hour)
+
+	pkgInfo := &nfpm.Info{
+		Overridables: nfpm.Overridables{
+			Contents: files.Contents{
+				{Destination: "/usr/bin/foo", FileInfo: &files.ContentFileInfo{MTime: time.Now()}},
+				{Destination: "/usr/bin/bar", FileInfo: &files.ContentFileInfo{MTime: older}},
+				{Destination: "/etc/foo.conf"},
+			},
+		},
+	}
+
+	makeReproducible(pkgInfo, epoch.Unix())
+
+	assert.Equal(t, epoch, pkgInfo.MTime)
+	assert.Equal(t, reproducibleBuildHost, pkgInfo.RPM.BuildHost)
+
+	require.Len(t, pkgInfo.Contents, 3)
+	assert.Equal(t, "/etc/foo.conf", pkgInfo.Contents[0].Destination)
+	assert.Equal(t, epoch, pkgInfo.Contents[0].FileInfo.MTime)
+	assert.Equal(t, "/usr/bin/bar", pkgInfo.Contents[1].Destination)
+	assert.Equal(t, older, pkgInfo.Contents[1].FileInfo.MTime)
+	assert.Equal(t, "/usr/bin/foo", pkgInfo.Contents[2].Destination)
+	assert.Equal(t, epoch, pkgInfo.Contents[2].FileInfo.MTime)
+}
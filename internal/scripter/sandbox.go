@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/sandboxpolicy"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// sandboxRequests returns the host paths the packages want mounted
+// into the build sandbox and the environment variables they want
+// passed to it. Requests the config doesn't allow fail the build.
+// Allowed variables that stplr was not run with are left out.
+func sandboxRequests(
+	cfg commonbuild.Config,
+	input *commonbuild.BuildInput,
+	pkgs []*staplerfile.Package,
+) (binds, env []string, err error) {
+	policy, err := sandboxpolicy.New(cfg.SandboxBinds(), cfg.SandboxEnvPassthrough())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, pkg := range pkgs {
+		fullName := input.Repository() + "/" + pkg.Name
+
+		pkgBinds, err := policy.Binds(fullName, pkg.SandboxBinds.Resolved())
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, bind := range pkgBinds {
+			if _, err := os.Stat(bind); err != nil {
+				return nil, nil, fmt.Errorf("sandbox bind of %s: %w", fullName, err)
+			}
+			if !slices.Contains(binds, bind) {
+				binds = append(binds, bind)
+			}
+		}
+
+		names := pkg.SandboxEnv.Resolved()
+		if err := policy.CheckEnv(fullName, names); err != nil {
+			return nil, nil, err
+		}
+		for _, kv := range input.SandboxEnv {
+			name, _, _ := strings.Cut(kv, "=")
+			if slices.Contains(names, name) && !slices.Contains(env, kv) {
+				env = append(env, kv)
+			}
+		}
+	}
+
+	return binds, env, nil
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+type sandboxConfig struct {
+	commonbuild.Config
+	binds, env []string
+}
+
+func (c *sandboxConfig) SandboxBinds() []string          { return c.binds }
+func (c *sandboxConfig) SandboxEnvPassthrough() []string { return c.env }
+
+func TestSandboxRequests(t *testing.T) {
+	sdk := t.TempDir()
+	cfg := &sandboxConfig{
+		binds: []string{"default/vendor-*:" + sdk},
+		env:   []string{"default/vendor-*:SDK_*"},
+	}
+	input := &commonbuild.BuildInput{
+		Repository_: "default",
+		SandboxEnv:  []string{"SDK_LICENSE=/lic", "SDK_TOKEN=secret", "OTHER=1"},
+	}
+
+	pkg := &staplerfile.Package{Name: "vendor-sdk"}
+	pkg.SandboxBinds.SetResolved([]string{sdk})
+	pkg.SandboxEnv.SetResolved([]string{"SDK_LICENSE", "SDK_MISSING"})
+
+	binds, env, err := sandboxRequests(cfg, input, []*staplerfile.Package{pkg, {Name: "vendor-sdk-doc"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{sdk}, binds)
+	assert.Equal(t, []string{"SDK_LICENSE=/lic"}, env)
+
+	refused := &staplerfile.Package{Name: "foo"}
+	refused.SandboxBinds.SetResolved([]string{sdk})
+	_, _, err = sandboxRequests(cfg, input, []*staplerfile.Package{pkg, refused})
+	assert.ErrorContains(t, err, "default/foo requests the sandbox bind")
+
+	refused = &staplerfile.Package{Name: "vendor-sdk"}
+	refused.SandboxEnv.SetResolved([]string{"OTHER"})
+	_, _, err = sandboxRequests(cfg, input, []*staplerfile.Package{refused})
+	assert.ErrorContains(t, err, "environment variable OTHER")
+
+	missing := &staplerfile.Package{Name: "vendor-sdk"}
+	missing.SandboxBinds.SetResolved([]string{sdk + "/missing"})
+	_, _, err = sandboxRequests(cfg, input, []*staplerfile.Package{missing})
+	assert.Error(t, err)
+}
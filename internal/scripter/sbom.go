@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goreleaser/nfpm/v2"
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/sbom"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+func pkgVersion(pkgInfo *nfpm.Info) string {
+	version := pkgInfo.Version
+	if pkgInfo.Release != "" {
+		version += "-" + pkgInfo.Release
+	}
+	if pkgInfo.Epoch != "" && pkgInfo.Epoch != "0" {
+		version = pkgInfo.Epoch + ":" + version
+	}
+	return version
+}
+
+// sbomInput collects what the SBOM and the provenance of the package
+// at pkgPath are made from.
+func sbomInput(bctx packageBuildContext, vars *staplerfile.Package, pkgInfo *nfpm.Info, pkgPath string, reproducible bool) *sbom.Input {
+	in := &sbom.Input{
+		Path:         pkgPath,
+		Name:         pkgInfo.Name,
+		Version:      pkgVersion(pkgInfo),
+		Arch:         pkgInfo.Arch,
+		Format:       bctx.input.PkgFormat(),
+		Summary:      vars.Summary.Resolved(),
+		Homepage:     pkgInfo.Homepage,
+		Maintainer:   pkgInfo.Maintainer,
+		Licenses:     vars.Licenses,
+		Depends:      pkgInfo.Depends,
+		BuildDepends: vars.BuildDepends.Resolved(),
+		Sources:      vars.Sources.Resolved(),
+		Checksums:    vars.Checksums.Resolved(),
+		Repository:   bctx.input.Repository(),
+		SourceURL:    bctx.input.SourceURL,
+		SourceCommit: bctx.input.SourceCommit,
+	}
+	if info := bctx.input.OSRelease(); info != nil {
+		in.Distro = info.ID
+	}
+
+	// the documents of reproducible builds must not differ either
+	if reproducible {
+		in.Created = time.Unix(bctx.input.SourceDateEpoch, 0)
+	} else {
+		in.Created = time.Now()
+		in.Started = bctx.started
+		in.Finished = in.Created
+	}
+
+	return in
+}
+
+func (e *LocalScriptExecutor) writeSBOM(in *sbom.Input, passphrase string) error {
+	key := e.cfg.Signing().Provenance
+	if key.KeyFile == "" {
+		e.out.Warn(gotext.Get("No provenance signing key is configured, the provenance of %q is not signed", in.Name))
+	}
+
+	e.out.Info(gotext.Get("Writing SBOM for package %q", in.Name))
+	if err := sbom.Write(in, key, passphrase); err != nil {
+		return fmt.Errorf("writing SBOM for %q: %w", in.Path, err)
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -39,7 +40,9 @@ import (
 
 	"go.stplr.dev/stplr/internal/app/output"
 	"go.stplr.dev/stplr/internal/build/common"
+	"go.stplr.dev/stplr/internal/cleanroot"
 	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/pkgsig"
 	"go.stplr.dev/stplr/internal/shutils/decoder"
 	"go.stplr.dev/stplr/internal/shutils/handlers"
 	"go.stplr.dev/stplr/internal/shutils/helpers"
@@ -111,12 +114,26 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 	builtDeps []*commonbuild.BuiltDep,
 	basePkg string,
 ) ([]*commonbuild.BuiltDep, error) {
+	started := time.Now()
+
 	dirs, err := commonbuild.GetDirs(e.cfg, sf.Path(), basePkg)
 	if err != nil {
 		return nil, fmt.Errorf("getting dirs for %q: %w", basePkg, err)
 	}
 
-	runner, cleanup, err := e.createRunner(input, dirs, varsOfPackages)
+	if wantsBuildCache(e.cfg, varsOfPackages) {
+		dirs.CacheDir = commonbuild.GetBuildCacheDir(e.cfg, basePkg)
+		if err := os.MkdirAll(dirs.CacheDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating build cache for %q: %w", basePkg, err)
+		}
+	}
+
+	limits, err := resolveBuildLimits(e.cfg, varsOfPackages)
+	if err != nil {
+		return nil, fmt.Errorf("resolving build limits for %q: %w", basePkg, err)
+	}
+
+	runner, cleanup, err := e.createRunner(ctx, input, dirs, varsOfPackages, limits.Sandbox)
 	if err != nil {
 		return nil, fmt.Errorf("creating runner for %q: %w", basePkg, err)
 	}
@@ -128,7 +145,7 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 
 	dec := decoder.New(input.OSRelease(), runner)
 
-	if err = e.ExecuteFunctions(ctx, dirs, dec); err != nil {
+	if err = e.ExecuteFunctions(ctx, input, dirs, dec, limits); err != nil {
 		return nil, fmt.Errorf("executing functions for %q: %w", basePkg, err)
 	}
 
@@ -139,6 +156,8 @@ func (e *LocalScriptExecutor) ExecuteSecondPass(
 		repoDeps:  repoDeps,
 		builtDeps: builtDeps,
 		basePkg:   basePkg,
+		started:   started,
+		limits:    limits,
 	}, varsOfPackages)
 }
 
@@ -149,18 +168,33 @@ type packageBuildContext struct {
 	repoDeps  []string
 	builtDeps []*commonbuild.BuiltDep
 	basePkg   string
+	started   time.Time
+	limits    BuildLimits
 }
 
 func (e *LocalScriptExecutor) createRunner(
+	ctx context.Context,
 	input *commonbuild.BuildInput,
 	dirs types.Directories,
 	varsOfPackages []*staplerfile.Package,
+	limits handlers.Limits,
 ) (*interp.Runner, func(), error) {
-	env := common.CreateBuildEnvVars(input.OSRelease(), dirs)
+	binds, sandboxEnv, err := sandboxRequests(e.cfg, input, varsOfPackages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env := common.CreateBuildEnvVars(input.OSRelease(), dirs, input.SourceDateEpoch)
+	env = append(env, sandboxEnv...)
 
+	allowed := []string{dirs.SrcDir, dirs.PkgDir, dirs.HomeDir}
+	if dirs.CacheDir != "" {
+		allowed = append(allowed, dirs.CacheDir)
+	}
+	allowed = append(allowed, binds...)
 	options := []handlers.Option{
 		handlers.WithFilter(
-			handlers.RestrictSandbox(dirs.SrcDir, dirs.PkgDir, dirs.HomeDir),
+			handlers.RestrictSandbox(allowed...),
 		),
 	}
 
@@ -168,16 +202,51 @@ func (e *LocalScriptExecutor) createRunner(
 		return pkg.DisableNetwork.Resolved()
 	})
 
-	sandboxInstance, err := handlers.SandboxHandler(2*time.Second, dirs, disableNet)
+	sandboxOpts, err := hardeningOptions(e.cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stderr, closeLog, err := openBuildLog(input.LogPath)
 	if err != nil {
+		return nil, nil, fmt.Errorf("opening build log: %w", err)
+	}
+
+	if !limits.IsZero() {
+		sandboxOpts = append(sandboxOpts, handlers.WithLimits(limits))
+	}
+	if len(binds) > 0 {
+		sandboxOpts = append(sandboxOpts, handlers.WithBinds(binds))
+	}
+	removeRootfs := func() {}
+	if image := input.BuildOpts().CleanRootImage; image != "" {
+		rootfs, err := e.prepareCleanRoot(ctx, input, dirs, image, stderr)
+		if err != nil {
+			closeLog()
+			return nil, nil, err
+		}
+		sandboxOpts = append(sandboxOpts, handlers.WithRootfs(rootfs))
+		removeRootfs = func() { _ = cleanroot.Remove(rootfs) }
+	}
+
+	sandboxInstance, err := handlers.SandboxHandler(2*time.Second, dirs, disableNet, sandboxOpts...)
+	if err != nil {
+		removeRootfs()
+		closeLog()
 		return nil, nil, fmt.Errorf("creating sandbox handler: %w", err)
 	}
 
 	options = append(options, handlers.WithPathRedirect("/tmp", filepath.Join(sandboxInstance.Rootfs(), "tmp")))
 
+	cleanup := func() {
+		sandboxInstance.Cleanup()
+		removeRootfs()
+		closeLog()
+	}
+
 	runner, err := interp.New(
 		interp.Env(expand.ListEnviron(env...)),
-		interp.StdIO(os.Stdin, os.Stderr, os.Stderr),
+		interp.StdIO(os.Stdin, stderr, stderr),
 		interp.ReadDirHandler2(handlers.RestrictedReadDir(options...)),
 		interp.OpenHandler(handlers.RestrictedOpen(options...)),
 		interp.StatHandler(handlers.RestrictedStat(options...)),
@@ -186,11 +255,26 @@ func (e *LocalScriptExecutor) createRunner(
 		}),
 	)
 	if err != nil {
-		sandboxInstance.Cleanup()
+		cleanup()
 		return nil, nil, fmt.Errorf("creating interpreter: %w", err)
 	}
 
-	return runner, sandboxInstance.Cleanup, nil
+	return runner, cleanup, nil
+}
+
+// openBuildLog returns a writer that copies the output of the build
+// functions to the build log at path, if there is one.
+func openBuildLog(path string) (io.Writer, func(), error) {
+	if path == "" {
+		return os.Stderr, func() {}, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return io.MultiWriter(os.Stderr, f), func() { _ = f.Close() }, nil
 }
 
 func (e *LocalScriptExecutor) buildPackages(
@@ -198,14 +282,28 @@ func (e *LocalScriptExecutor) buildPackages(
 	bctx packageBuildContext,
 	varsOfPackages []*staplerfile.Package,
 ) ([]*commonbuild.BuiltDep, error) {
+	// debug info packages go last, so that the packages
+	// of the script don't depend on them
+	var debugDeps []*commonbuild.BuiltDep
 	for _, vars := range varsOfPackages {
 		dep, err := e.buildSinglePackage(ctx, bctx, vars)
 		if err != nil {
 			return nil, fmt.Errorf("building package %q: %w", vars.Name, err)
 		}
 		bctx.builtDeps = append(bctx.builtDeps, dep)
+
+		if !wantsDebugInfo(e.cfg, vars) {
+			continue
+		}
+		dep, err = e.buildDebugInfoPackage(ctx, bctx, vars)
+		if err != nil {
+			return nil, fmt.Errorf("building debug info package of %q: %w", vars.Name, err)
+		}
+		if dep != nil {
+			debugDeps = append(debugDeps, dep)
+		}
 	}
-	return bctx.builtDeps, nil
+	return append(bctx.builtDeps, debugDeps...), nil
 }
 
 func (e *LocalScriptExecutor) buildSinglePackage(
@@ -218,11 +316,34 @@ func (e *LocalScriptExecutor) buildSinglePackage(
 		packageName = vars.Name
 	}
 
-	funcOut, err := e.ExecutePackageFunctions(ctx, bctx.dec, bctx.dirs, packageName)
+	var scriptOpts []string
+	if vars.Options != nil {
+		scriptOpts = vars.Options.Options
+	}
+	opts, err := ParsePackageOptions(e.cfg.PackageOptions(), scriptOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	debugInfo := wantsDebugInfo(e.cfg, vars)
+	strip := opts.Strip
+	if debugInfo {
+		// the binaries are stripped when their debug info is split
+		opts.Debug = true
+	}
+
+	funcOut, err := e.ExecutePackageFunctions(ctx, bctx.dec, bctx.dirs, packageName, opts, bctx.limits)
 	if err != nil {
 		return nil, fmt.Errorf("executing package functions: %w", err)
 	}
 
+	if debugInfo {
+		debugRoot := debugInfoDir(bctx.dirs, vars.Name)
+		if err := e.splitDebugInfo(ctx, bctx.dirs.PkgDir, debugRoot, funcOut.Contents, strip); err != nil {
+			return nil, fmt.Errorf("splitting debug info: %w", err)
+		}
+	}
+
 	e.out.Info(gotext.Get("Building metadata for package %q", bctx.basePkg))
 
 	pkgInfo, err := buildPkgMetadata(
@@ -239,8 +360,31 @@ func (e *LocalScriptExecutor) buildSinglePackage(
 		return nil, fmt.Errorf("building metadata: %w", err)
 	}
 
+	if err = e.lintPackage(bctx, pkgInfo); err != nil {
+		return nil, err
+	}
+
+	return e.writePackage(ctx, bctx, vars, pkgInfo)
+}
+
+// writePackage writes the package described by pkgInfo
+// to the base directory of the build.
+func (e *LocalScriptExecutor) writePackage(
+	ctx context.Context,
+	bctx packageBuildContext,
+	vars *staplerfile.Package,
+	pkgInfo *nfpm.Info,
+) (*commonbuild.BuiltDep, error) {
+	reproducible := bctx.input.BuildOpts().Reproducible || e.cfg.Reproducible()
+	if reproducible {
+		makeReproducible(pkgInfo, bctx.input.SourceDateEpoch)
+	}
+
 	format := bctx.input.PkgFormat()
 
+	signingKey := e.cfg.Signing().For(format)
+	configureSigning(pkgInfo, format, signingKey, bctx.input.SigningPassphrase)
+
 	packager, err := nfpm.Get(format)
 	if err != nil {
 		return nil, fmt.Errorf("getting packager for format %q: %w", format, err)
@@ -264,11 +408,28 @@ func (e *LocalScriptExecutor) buildSinglePackage(
 		return nil, fmt.Errorf("closing package file %q: %w", pkgPath, err)
 	}
 
+	// nfpm can't sign archlinux packages, pacman uses detached signatures
+	if format == "archlinux" && signingKey.KeyFile != "" {
+		if err = pkgsig.SignDetached(pkgPath, signingKey.KeyFile, bctx.input.SigningPassphrase); err != nil {
+			_ = os.Remove(pkgPath)
+			return nil, fmt.Errorf("signing %q: %w", pkgPath, err)
+		}
+	}
+
+	if bctx.input.BuildOpts().SBOM || e.cfg.SBOM() {
+		if err = e.writeSBOM(sbomInput(bctx, vars, pkgInfo, pkgPath, reproducible), bctx.input.ProvenancePassphrase); err != nil {
+			_ = os.Remove(pkgPath)
+			return nil, err
+		}
+	}
+
 	if err = ctx.Err(); err != nil {
 		_ = os.Remove(pkgPath)
 		return nil, err
 	}
 
+	e.writeCacheManifest(bctx, vars, pkgPath)
+
 	// return memory that was allocated (critical for high memory usage tasks in packager.Package)
 	pkgInfo = nil  //nolint:ineffassign // hint for GC to collect rpmpack's internal buffer
 	packager = nil //nolint:ineffassign
@@ -312,6 +473,8 @@ func buildPkgMetadata(
 	pkgFormat := input.PkgFormat()
 	info := input.OSRelease()
 
+	pkgInfo.Depends = formatDepends(pkgInfo.Depends, vars.Depends.Resolved(), pkgFormat)
+
 	if pkgFormat == "apk" {
 		// Alpine отказывается устанавливать пакеты, которые предоставляют сами себя, поэтому удаляем такие элементы
 		pkgInfo.Provides = slices.DeleteFunc(pkgInfo.Provides, func(s string) bool {
@@ -400,7 +563,7 @@ func buildPkgMetadata(
 	return pkgInfo, nil
 }
 
-func execFunc(ctx context.Context, out output.Output, d *decoder.Decoder, name string, dirs types.Directories) error {
+func execFunc(ctx context.Context, out output.Output, d *decoder.Decoder, name string, dirs types.Directories, limits BuildLimits) error {
 	fn, ok := d.GetFuncP(name, func(ctx context.Context, r *interp.Runner) error {
 		// It should be done via interp.RunnerOption,
 		// but due to the issues below, it cannot be done.
@@ -414,7 +577,9 @@ func execFunc(ctx context.Context, out output.Output, d *decoder.Decoder, name s
 	})
 	if ok {
 		out.Info(gotext.Get("Executing %s()", name))
-		err := fn(ctx, interp.Dir(dirs.SrcDir))
+		err := runLimited(ctx, limits, name, func(ctx context.Context) error {
+			return fn(ctx, interp.Dir(dirs.SrcDir))
+		})
 		if err != nil {
 			return err
 		}
@@ -422,14 +587,30 @@ func execFunc(ctx context.Context, out output.Output, d *decoder.Decoder, name s
 	return nil
 }
 
-func (e *LocalScriptExecutor) ExecuteFunctions(ctx context.Context, dirs types.Directories, dec *decoder.Decoder) error {
-	if err := execFunc(ctx, e.out, dec, "prepare", dirs); err != nil {
+func (e *LocalScriptExecutor) ExecuteFunctions(
+	ctx context.Context,
+	input *commonbuild.BuildInput,
+	dirs types.Directories,
+	dec *decoder.Decoder,
+	limits BuildLimits,
+) error {
+	if err := execFunc(ctx, e.out, dec, "prepare", dirs, limits); err != nil {
 		return err
 	}
-	if err := execFunc(ctx, e.out, dec, "build", dirs); err != nil {
+	if err := execFunc(ctx, e.out, dec, "build", dirs, limits); err != nil {
 		return err
 	}
 
+	if input.BuildOpts().NoCheck || e.cfg.NoCheck() {
+		if _, ok := dec.GetFunc("check"); ok {
+			e.out.Info(gotext.Get("Skipping check()"))
+		}
+		return nil
+	}
+	if err := execFunc(ctx, e.out, dec, "check", dirs, limits); err != nil {
+		return fmt.Errorf("check failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -438,6 +619,8 @@ func (e *LocalScriptExecutor) ExecutePackageFunctions(
 	dec *decoder.Decoder,
 	dirs types.Directories,
 	packageName string,
+	opts PackageOptions,
+	limits BuildLimits,
 ) (*commonbuild.FunctionsOutput, error) {
 	fOutput := &commonbuild.FunctionsOutput{}
 	var packageFuncName string
@@ -450,7 +633,11 @@ func (e *LocalScriptExecutor) ExecutePackageFunctions(
 		packageFuncName = fmt.Sprintf("package_%s", packageName)
 		filesFuncName = fmt.Sprintf("files_%s", packageName)
 	}
-	if err := execFunc(ctx, e.out, dec, packageFuncName, dirs); err != nil {
+	if err := execFunc(ctx, e.out, dec, packageFuncName, dirs, limits); err != nil {
+		return nil, err
+	}
+
+	if err := e.tidyPackage(ctx, dirs.PkgDir, opts); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/commonbuild"
+	"go.stplr.dev/stplr/internal/shutils/decoder"
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type testConfig struct {
+	commonbuild.Config
+	noCheck bool
+}
+
+func (c *testConfig) NoCheck() bool { return c.noCheck }
+
+func executeTestFunctions(t *testing.T, script string, cfgNoCheck, optsNoCheck bool) (string, error) {
+	t.Helper()
+
+	dirs := types.Directories{SrcDir: t.TempDir()}
+
+	fl, err := syntax.NewParser().Parse(strings.NewReader(script), "Staplerfile")
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	runner, err := interp.New(
+		interp.Env(expand.ListEnviron("srcdir="+dirs.SrcDir)),
+		interp.StdIO(nil, buf, buf),
+	)
+	require.NoError(t, err)
+	require.NoError(t, runner.Run(context.Background(), fl))
+
+	e := NewLocalScriptExecutor(&testConfig{noCheck: cfgNoCheck}, output.NewConsoleOutput())
+	err = e.ExecuteFunctions(
+		context.Background(),
+		&commonbuild.BuildInput{Opts: &types.BuildOpts{NoCheck: optsNoCheck}},
+		dirs,
+		decoder.New(&distro.OSRelease{}, runner),
+		BuildLimits{},
+	)
+	return buf.String(), err
+}
+
+const checkScript = `
+prepare() { echo prepare; }
+build() { echo build; }
+check() { echo check; }
+`
+
+func TestExecuteFunctionsRunsCheck(t *testing.T) {
+	out, err := executeTestFunctions(t, checkScript, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, "prepare\nbuild\ncheck\n", out)
+}
+
+func TestExecuteFunctionsNoCheck(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		cfgNoCheck  bool
+		optsNoCheck bool
+	}{
+		{"flag", false, true},
+		{"config", true, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := executeTestFunctions(t, checkScript, tc.cfgNoCheck, tc.optsNoCheck)
+			require.NoError(t, err)
+			assert.Equal(t, "prepare\nbuild\n", out)
+		})
+	}
+}
+
+func TestExecuteFunctionsCheckFails(t *testing.T) {
+	_, err := executeTestFunctions(t, `
+build() { echo build; }
+check() { return 1; }
+`, false, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "check failed")
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"github.com/goreleaser/nfpm/v2"
+
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+// configureSigning sets up nfpm to sign the package with key.
+// archlinux packages are signed separately, see pkgsig.SignDetached.
+func configureSigning(pkgInfo *nfpm.Info, format string, key types.SigningKey, passphrase string) {
+	if key.KeyFile == "" {
+		return
+	}
+
+	switch format {
+	case "rpm":
+		pkgInfo.RPM.Signature.KeyFile = key.KeyFile
+		pkgInfo.RPM.Signature.KeyPassphrase = passphrase
+	case "deb":
+		pkgInfo.Deb.Signature.KeyFile = key.KeyFile
+		pkgInfo.Deb.Signature.KeyPassphrase = passphrase
+	case "apk":
+		pkgInfo.APK.Signature.KeyFile = key.KeyFile
+		pkgInfo.APK.Signature.KeyPassphrase = passphrase
+		pkgInfo.APK.Signature.KeyName = key.KeyName
+	}
+}
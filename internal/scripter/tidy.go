@@ -0,0 +1,412 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"debug/elf"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Directories removed by !docs and compressed by zipman,
+// relative to $pkgdir.
+var (
+	docDirs = []string{
+		"usr/doc", "usr/share/doc", "usr/local/doc", "usr/local/share/doc",
+		"usr/gtk-doc", "usr/share/gtk-doc", "usr/local/gtk-doc", "usr/local/share/gtk-doc",
+		"opt/*/doc", "opt/*/gtk-doc",
+	}
+	manDirs = []string{
+		"usr/man", "usr/share/man", "usr/local/man", "usr/local/share/man",
+		"usr/info", "usr/share/info", "usr/local/info", "usr/local/share/info",
+		"opt/*/man", "opt/*/info",
+	}
+)
+
+// debugDir holds detached debug info, which must keep its sections.
+const debugDir = "usr/lib/debug"
+
+// tidyPackage applies opts to the files the package function
+// installed into pkgDir.
+func (e *LocalScriptExecutor) tidyPackage(ctx context.Context, pkgDir string, opts PackageOptions) error {
+	if !opts.Docs {
+		if err := removeGlobs(pkgDir, docDirs); err != nil {
+			return fmt.Errorf("removing docs: %w", err)
+		}
+	}
+	if !opts.Libtool {
+		if err := removeFiles(pkgDir, func(path string) bool {
+			return strings.HasSuffix(path, ".la")
+		}); err != nil {
+			return fmt.Errorf("removing libtool files: %w", err)
+		}
+	}
+	if !opts.StaticLibs {
+		if err := removeFiles(pkgDir, hasSharedLib); err != nil {
+			return fmt.Errorf("removing static libraries: %w", err)
+		}
+	}
+	if opts.ZipMan {
+		if err := zipMan(pkgDir); err != nil {
+			return fmt.Errorf("compressing man pages: %w", err)
+		}
+	}
+	if opts.Strip && !opts.Debug {
+		if err := e.stripBinaries(ctx, pkgDir); err != nil {
+			return fmt.Errorf("stripping binaries: %w", err)
+		}
+	}
+	if !opts.EmptyDirs {
+		if err := removeEmptyDirs(pkgDir); err != nil {
+			return fmt.Errorf("removing empty directories: %w", err)
+		}
+	}
+	return nil
+}
+
+func globDirs(pkgDir string, patterns []string) ([]string, error) {
+	var dirs []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(pkgDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, matches...)
+	}
+	return dirs, nil
+}
+
+func removeGlobs(pkgDir string, patterns []string) error {
+	dirs, err := globDirs(pkgDir, patterns)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeFiles removes the non-directory files for which match returns true.
+func removeFiles(pkgDir string, match func(path string) bool) error {
+	var matched []string
+	err := filepath.WalkDir(pkgDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && match(path) {
+			matched = append(matched, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, path := range matched {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasSharedLib reports whether path is a static library
+// installed along with its shared version.
+func hasSharedLib(path string) bool {
+	base, ok := strings.CutSuffix(path, ".a")
+	if !ok {
+		return false
+	}
+	_, err := os.Lstat(base + ".so")
+	return err == nil
+}
+
+var compressedExts = []string{".gz", ".bz2", ".xz", ".zst", ".lz", ".Z"}
+
+func isCompressed(path string) bool {
+	return slices.Contains(compressedExts, filepath.Ext(path))
+}
+
+// zipMan compresses man and info pages. Symlinks are pointed to the
+// compressed pages and hard links are kept.
+func zipMan(pkgDir string) error {
+	dirs, err := globDirs(pkgDir, manDirs)
+	if err != nil {
+		return err
+	}
+
+	var pages []string
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			// the info index is updated by install-info
+			if d.IsDir() || isCompressed(path) || d.Name() == "dir" {
+				return nil
+			}
+			pages = append(pages, path)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	compressed := make(map[uint64]string)
+	for _, path := range pages {
+		fi, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			if err := relinkPage(path); err != nil {
+				return err
+			}
+			continue
+		}
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+
+		// the link count drops as pages are compressed,
+		// so look up every inode
+		st, ok := fi.Sys().(*syscall.Stat_t)
+		if ok {
+			if first, ok := compressed[st.Ino]; ok {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+				if err := os.Link(first, path+".gz"); err != nil {
+					return err
+				}
+				continue
+			}
+			if st.Nlink > 1 {
+				compressed[st.Ino] = path + ".gz"
+			}
+		}
+
+		if err := gzipFile(path, fi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func relinkPage(path string) error {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return err
+	}
+	if !isCompressed(target) {
+		target += ".gz"
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return os.Symlink(target, path+".gz")
+}
+
+// gzipFile replaces path with path.gz. The gzip header has no name
+// and time, so the result is reproducible.
+func gzipFile(path string, fi fs.FileInfo) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	zw, err := gzip.NewWriterLevel(out, gzip.BestCompression)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	if _, err := io.Copy(zw, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chtimes(path+".gz", fi.ModTime(), fi.ModTime()); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+var arMagic = []byte("!<arch>\n")
+
+// stripFlags returns the strip arguments for the file at path,
+// or nil if it is not an object file.
+func stripFlags(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(f, magic); err != nil {
+		return nil, nil
+	}
+	if bytes.Equal(magic, arMagic) {
+		if strings.HasSuffix(path, ".a") {
+			return []string{"--strip-debug"}, nil
+		}
+		return nil, nil
+	}
+	if !bytes.HasPrefix(magic, []byte(elf.ELFMAG)) {
+		return nil, nil
+	}
+
+	ef, err := elf.NewFile(f)
+	if err != nil {
+		return nil, nil
+	}
+	defer ef.Close()
+
+	switch ef.Type {
+	case elf.ET_EXEC:
+		return []string{"--strip-all"}, nil
+	case elf.ET_DYN:
+		// PIE executables are shared objects with an interpreter
+		isPIE := slices.ContainsFunc(ef.Progs, func(p *elf.Prog) bool {
+			return p.Type == elf.PT_INTERP
+		})
+		if isPIE && !strings.Contains(filepath.Base(path), ".so") {
+			return []string{"--strip-all"}, nil
+		}
+		return []string{"--strip-unneeded"}, nil
+	case elf.ET_REL:
+		return []string{"--strip-debug"}, nil
+	}
+	return nil, nil
+}
+
+func (e *LocalScriptExecutor) stripBinaries(ctx context.Context, pkgDir string) error {
+	stripBin, err := exec.LookPath("strip")
+	if err != nil {
+		e.out.Warn(gotext.Get("strip is not installed, binaries are not stripped"))
+		return nil
+	}
+
+	skip := filepath.Join(pkgDir, debugDir)
+	return filepath.WalkDir(pkgDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == skip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		flags, err := stripFlags(path)
+		if err != nil || flags == nil {
+			return err
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return withWritable(path, fi, func() error {
+			return runTool(ctx, stripBin, append(flags, path)...)
+		})
+	})
+}
+
+// withWritable runs fn with the file at path writable by its owner.
+func withWritable(path string, fi fs.FileInfo, fn func() error) error {
+	perm := fi.Mode().Perm()
+	if perm&0o200 != 0 {
+		return fn()
+	}
+	if err := os.Chmod(path, perm|0o200); err != nil {
+		return err
+	}
+	defer func() { _ = os.Chmod(path, perm) }()
+	return fn()
+}
+
+func runTool(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", filepath.Base(name), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// removeEmptyDirs removes the empty directories of pkgDir, deepest first.
+func removeEmptyDirs(pkgDir string) error {
+	var dirs []string
+	err := filepath.WalkDir(pkgDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != pkgDir {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// WalkDir visits parents first
+	for _, dir := range slices.Backward(dirs) {
+		if !isDirEmpty(dir) {
+			continue
+		}
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scripter
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/app/output"
+)
+
+func TestParsePackageOptions(t *testing.T) {
+	opts, err := ParsePackageOptions([]string{"strip", "!docs"}, []string{"docs", "!libtool", "zipman"})
+	require.NoError(t, err)
+
+	want := DefaultPackageOptions()
+	want.Strip = true
+	want.Libtool = false
+	want.ZipMan = true
+	assert.Equal(t, want, opts)
+
+	_, err = ParsePackageOptions([]string{"!stirp"})
+	assert.ErrorContains(t, err, `unknown package option "!stirp"`)
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestTidyPackage(t *testing.T) {
+	pkgDir := t.TempDir()
+	for _, name := range []string{
+		"usr/share/doc/foo/README",
+		"usr/lib/libfoo.la",
+		"usr/lib/libfoo.a",
+		"usr/lib/libfoo.so",
+		"usr/lib/libbar.a",
+		"usr/share/man/man1/foo.1",
+		"usr/share/info/dir",
+	} {
+		writeTestFile(t, filepath.Join(pkgDir, name), name)
+	}
+	man1 := filepath.Join(pkgDir, "usr/share/man/man1")
+	require.NoError(t, os.Symlink("foo.1", filepath.Join(man1, "bar.1")))
+	require.NoError(t, os.Link(filepath.Join(man1, "foo.1"), filepath.Join(man1, "baz.1")))
+	require.NoError(t, os.MkdirAll(filepath.Join(pkgDir, "var/empty"), 0o755))
+
+	opts, err := ParsePackageOptions([]string{"!docs", "!libtool", "!staticlibs", "zipman", "!emptydirs"})
+	require.NoError(t, err)
+
+	e := NewLocalScriptExecutor(nil, output.NewConsoleOutput())
+	require.NoError(t, e.tidyPackage(t.Context(), pkgDir, opts))
+
+	for _, name := range []string{
+		"usr/share/doc",
+		"usr/lib/libfoo.la",
+		"usr/lib/libfoo.a",
+		"usr/share/man/man1/foo.1",
+		"var",
+	} {
+		assert.NoFileExists(t, filepath.Join(pkgDir, name))
+		assert.NoDirExists(t, filepath.Join(pkgDir, name))
+	}
+	assert.FileExists(t, filepath.Join(pkgDir, "usr/lib/libbar.a"))
+	assert.FileExists(t, filepath.Join(pkgDir, "usr/share/info/dir"))
+
+	f, err := os.Open(filepath.Join(man1, "foo.1.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, "usr/share/man/man1/foo.1", string(content))
+
+	target, err := os.Readlink(filepath.Join(man1, "bar.1.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo.1.gz", target)
+
+	foo, err := os.Stat(filepath.Join(man1, "foo.1.gz"))
+	require.NoError(t, err)
+	baz, err := os.Stat(filepath.Join(man1, "baz.1.gz"))
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(foo, baz))
+}
+
+func TestTidyPackageDefaults(t *testing.T) {
+	pkgDir := t.TempDir()
+	writeTestFile(t, filepath.Join(pkgDir, "usr/lib/libfoo.la"), "")
+	require.NoError(t, os.MkdirAll(filepath.Join(pkgDir, "var/empty"), 0o755))
+
+	e := NewLocalScriptExecutor(nil, output.NewConsoleOutput())
+	require.NoError(t, e.tidyPackage(t.Context(), pkgDir, DefaultPackageOptions()))
+
+	assert.FileExists(t, filepath.Join(pkgDir, "usr/lib/libfoo.la"))
+	assert.DirExists(t, filepath.Join(pkgDir, "var/empty"))
+}
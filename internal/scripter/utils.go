@@ -43,6 +43,7 @@ import (
 
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/cpu"
+	"go.stplr.dev/stplr/internal/depver"
 	"go.stplr.dev/stplr/pkg/overrides"
 	"go.stplr.dev/stplr/pkg/staplerfile"
 	"go.stplr.dev/stplr/pkg/types"
@@ -64,6 +65,14 @@ func prepareDirs(dirs types.Directories) error {
 	return os.MkdirAll(dirs.HomeDir, 0o755)
 }
 
+// wantsBuildCache tells if the compiler caches of the packages
+// are kept between builds.
+func wantsBuildCache(cfg commonbuild.Config, pkgs []*staplerfile.Package) bool {
+	return cfg.BuildCache() || slices.ContainsFunc(pkgs, func(pkg *staplerfile.Package) bool {
+		return pkg.BuildCache.Resolved()
+	})
+}
+
 func isDirEmpty(path string) bool {
 	f, err := os.Open(path)
 	if err != nil {
@@ -257,6 +266,29 @@ func setScripts(vars *staplerfile.Package, info *nfpm.Info, scriptDir string) {
 	}
 }
 
+// formatDepends converts deps to the syntax of pkgFormat. Dependencies
+// that were built from Stapler repos come as bare names, so their
+// version constraints are taken from declared.
+func formatDepends(deps, declared []string, pkgFormat string) []string {
+	constraints := make(map[string]depver.Dep)
+	for _, d := range declared {
+		dep := depver.Parse(d)
+		if dep.Op != depver.OpAny {
+			constraints[dep.Name] = dep
+		}
+	}
+
+	out := make([]string, 0, len(deps))
+	for _, d := range deps {
+		dep := depver.Parse(d)
+		if c, ok := constraints[dep.Name]; ok && dep.Op == depver.OpAny {
+			dep = c
+		}
+		out = append(out, dep.Format(pkgFormat))
+	}
+	return out
+}
+
 func Map[T, R any](items []T, f func(T) R) []R {
 	res := make([]R, len(items))
 	for i, item := range items {
@@ -265,8 +297,17 @@ func Map[T, R any](items []T, f func(T) R) []R {
 	return res
 }
 
+// GetBuiltName returns the names of deps. Debug info packages are
+// left out, they are never installed or depended on automatically.
 func GetBuiltName(deps []*commonbuild.BuiltDep) []string {
-	return Map(deps, func(dep *commonbuild.BuiltDep) string {
+	return Map(WithoutDebugInfo(deps), func(dep *commonbuild.BuiltDep) string {
 		return dep.Name
 	})
 }
+
+// WithoutDebugInfo returns deps without the debug info packages.
+func WithoutDebugInfo(deps []*commonbuild.BuiltDep) []*commonbuild.BuiltDep {
+	return slices.DeleteFunc(slices.Clone(deps), func(dep *commonbuild.BuiltDep) bool {
+		return dep.DebugInfo
+	})
+}
@@ -185,3 +185,21 @@ func TestGetBasePkgInfoNilInputs(t *testing.T) {
 		GetBasePkgInfo(nil, mockInput)
 	}, "should panic with nil package")
 }
+
+func TestFormatDepends(t *testing.T) {
+	deps := []string{"libfoo", "bar<2", "baz"}
+	declared := []string{"libfoo>=1.2", "baz"}
+
+	assert.Equal(t,
+		[]string{"libfoo (>= 1.2)", "bar (<< 2)", "baz"},
+		formatDepends(deps, declared, "deb"),
+	)
+	assert.Equal(t,
+		[]string{"libfoo >= 1.2", "bar < 2", "baz"},
+		formatDepends(deps, declared, "rpm"),
+	)
+	assert.Equal(t,
+		[]string{"libfoo>=1.2", "bar<2", "baz"},
+		formatDepends(deps, declared, "archlinux"),
+	)
+}
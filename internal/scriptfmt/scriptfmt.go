@@ -0,0 +1,417 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package scriptfmt formats Staplerfiles in one canonical style.
+package scriptfmt
+
+import (
+	"bytes"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// Indent is the number of spaces of an indentation level.
+const Indent = 4
+
+// Indexed arrays with at most maxInlineArray elements that fit in
+// maxInlineWidth columns are on one line. Other arrays have one
+// element per line.
+const (
+	maxInlineArray = 3
+	maxInlineWidth = 80
+)
+
+// variableOrder is the order of the variables of a script. It is the
+// order of the fields of staplerfile.Package, after the names and before
+// the options of the script.
+var variableOrder = func() []string {
+	vars := []string{"name", "basepkg_name"}
+	t := reflect.TypeOf(staplerfile.Package{})
+	for i := range t.NumField() {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("sh"), ","); tag != "" {
+			vars = append(vars, tag)
+		}
+	}
+	return append(vars, "options")
+}()
+
+// Format returns src in the canonical style. The variables of the script
+// are ordered like variableOrder, followed by the functions. Strings
+// without expansions are double-quoted, and long arrays have one element
+// per line.
+//
+// Statements are only moved if that does not change what the script does:
+// variables stay after the ones they use, and nothing moves across other
+// commands.
+func Format(src []byte) ([]byte, error) {
+	src, err := reorder(src)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err = normalize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := syntax.NewPrinter(syntax.Indent(Indent)).Print(&buf, f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func parse(src []byte) (*syntax.File, error) {
+	return syntax.NewParser(syntax.KeepComments(true)).Parse(bytes.NewReader(src), "Staplerfile")
+}
+
+type kind int
+
+const (
+	kindOther kind = iota
+	kindVariable
+	kindFunction
+)
+
+type stmt struct {
+	kind kind
+	// order is the index of the variable in variableOrder,
+	// and override tells if it has an override suffix.
+	order    int
+	override bool
+	// name is the variable that is set,
+	// and uses are the variables its value uses.
+	name string
+	uses []string
+
+	start, end uint
+}
+
+// reorder moves the variables and functions at the top level of src.
+func reorder(src []byte) ([]byte, error) {
+	f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Stmts) == 0 {
+		return src, nil
+	}
+
+	stmts := make([]stmt, len(f.Stmts))
+	for i, s := range f.Stmts {
+		stmts[i] = classify(s)
+	}
+	stmts[0].start = headerEnd(f.Stmts[0], stmts[0].start)
+
+	order := make([]int, len(stmts))
+	for i := range order {
+		order[i] = i
+	}
+
+	// Functions move to the end if only variables follow them,
+	// as other commands may call them.
+	firstFunc := slices.IndexFunc(stmts, func(s stmt) bool { return s.kind == kindFunction })
+	if firstFunc >= 0 && !slices.ContainsFunc(stmts[firstFunc:], func(s stmt) bool { return s.kind == kindOther }) {
+		slices.SortStableFunc(order, func(a, b int) int {
+			return boolInt(stmts[a].kind == kindFunction) - boolInt(stmts[b].kind == kindFunction)
+		})
+	}
+
+	// Runs of known variables are sorted between other statements.
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && stmts[order[end]].kind == kindVariable {
+			end++
+		}
+		if end > start {
+			sortVariables(stmts, order[start:end])
+			start = end
+		} else {
+			start++
+		}
+	}
+
+	if slices.IsSorted(order) {
+		return src, nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(src[:stmts[0].start])
+	for i, idx := range order {
+		s := stmts[idx]
+		if i > 0 {
+			prev := stmts[order[i-1]]
+			switch {
+			case order[i-1]+1 == idx:
+				// Keep the lines between statements that were already
+				// next to each other.
+				buf.Write(src[prev.end:s.start])
+			case prev.kind == kindFunction || s.kind == kindFunction ||
+				multiline(src, prev) || multiline(src, s):
+				buf.WriteString("\n\n")
+			default:
+				buf.WriteString("\n")
+			}
+		}
+		buf.Write(src[s.start:s.end])
+	}
+	// The comments after the last statement stay at the end.
+	if tail := src[stmts[len(stmts)-1].end:]; len(tail) > 0 {
+		buf.Write(tail)
+	} else {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// headerEnd returns where the statement s starts without the comments
+// at the top of the file, which are the ones before a blank line.
+// They stay at the top when s is moved.
+func headerEnd(s *syntax.Stmt, start uint) uint {
+	var leading []syntax.Comment
+	for _, c := range s.Comments {
+		if c.Pos().After(s.Pos()) {
+			break
+		}
+		leading = append(leading, c)
+	}
+
+	for i := len(leading) - 1; i >= 0; i-- {
+		next := s.Pos()
+		if i+1 < len(leading) {
+			next = leading[i+1].Pos()
+		}
+		if next.Line() > leading[i].End().Line()+1 {
+			return next.Offset()
+		}
+	}
+	return start
+}
+
+// sortVariables sorts idx by variableOrder, with the overrides of a
+// variable after it. They are not sorted if that changes the order of
+// a variable and one that uses it or sets it too.
+func sortVariables(stmts []stmt, idx []int) {
+	sorted := slices.Clone(idx)
+	slices.SortStableFunc(sorted, func(a, b int) int {
+		if stmts[a].order != stmts[b].order {
+			return stmts[a].order - stmts[b].order
+		}
+		return boolInt(stmts[a].override) - boolInt(stmts[b].override)
+	})
+
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if b < a && depends(stmts[a], stmts[b]) {
+				return
+			}
+		}
+	}
+	copy(idx, sorted)
+}
+
+func depends(a, b stmt) bool {
+	return a.name == b.name || slices.Contains(a.uses, b.name) || slices.Contains(b.uses, a.name)
+}
+
+func classify(s *syntax.Stmt) stmt {
+	res := stmt{start: s.Pos().Offset(), end: s.End().Offset()}
+	for _, c := range s.Comments {
+		res.start = min(res.start, c.Pos().Offset())
+		res.end = max(res.end, c.End().Offset())
+	}
+
+	switch cmd := s.Cmd.(type) {
+	case *syntax.FuncDecl:
+		res.kind = kindFunction
+	case *syntax.CallExpr:
+		if len(cmd.Args) > 0 || len(cmd.Assigns) != 1 || s.Negated || s.Background || len(s.Redirs) > 0 {
+			return res
+		}
+		a := cmd.Assigns[0]
+		order := variableIndex(a.Name.Value)
+		if order < 0 || a.Append {
+			return res
+		}
+
+		safe := true
+		syntax.Walk(a, func(node syntax.Node) bool {
+			switch n := node.(type) {
+			case *syntax.CmdSubst, *syntax.ProcSubst, *syntax.ArithmExp:
+				safe = false
+			case *syntax.ParamExp:
+				if n.Param != nil {
+					res.uses = append(res.uses, n.Param.Value)
+				}
+			}
+			return safe
+		})
+		if safe {
+			res.kind = kindVariable
+			res.order = order
+			res.name = a.Name.Value
+			res.override = res.name != variableOrder[order]
+		}
+	}
+	return res
+}
+
+// variableIndex returns the index in variableOrder of the variable name,
+// which may have an override suffix, or -1.
+func variableIndex(name string) int {
+	best, bestLen := -1, 0
+	for i, v := range variableOrder {
+		if (name == v || strings.HasPrefix(name, v+"_")) && len(v) > bestLen {
+			best, bestLen = i, len(v)
+		}
+	}
+	return best
+}
+
+func multiline(src []byte, s stmt) bool {
+	return bytes.ContainsRune(src[s.start:s.end], '\n')
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type edit struct {
+	start, end uint
+	text       string
+}
+
+// normalize quotes the values of assignments and lays out their arrays.
+func normalize(src []byte) ([]byte, error) {
+	f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var edits []edit
+	syntax.Walk(f, func(node syntax.Node) bool {
+		a, ok := node.(*syntax.Assign)
+		if !ok {
+			return true
+		}
+		switch {
+		case a.Array != nil:
+			if text, ok := arrayText(src, a.Array); ok {
+				edits = append(edits, edit{a.Array.Pos().Offset(), a.Array.End().Offset(), text})
+			}
+		case a.Value != nil:
+			edits = append(edits, edit{a.Value.Pos().Offset(), a.Value.End().Offset(), wordText(src, a.Value)})
+		}
+		return false
+	})
+
+	var buf bytes.Buffer
+	last := uint(0)
+	for _, e := range edits {
+		buf.Write(src[last:e.start])
+		buf.WriteString(e.text)
+		last = e.end
+	}
+	buf.Write(src[last:])
+	return buf.Bytes(), nil
+}
+
+// arrayText returns arr on one line if it is short, or with one element
+// per line. Arrays with comments are not changed.
+func arrayText(src []byte, arr *syntax.ArrayExpr) (string, bool) {
+	if len(arr.Last) > 0 {
+		return "", false
+	}
+
+	elems := make([]string, len(arr.Elems))
+	assoc := false
+	for i, e := range arr.Elems {
+		if len(e.Comments) > 0 || e.Value == nil {
+			return "", false
+		}
+		text := wordText(src, e.Value)
+		if e.Index != nil {
+			assoc = true
+			// The position of the index is after its bracket
+			text = string(src[e.Index.Pos().Offset()-1:e.Value.Pos().Offset()]) + text
+		}
+		elems[i] = text
+	}
+
+	if len(elems) == 0 {
+		return "()", true
+	}
+
+	inline := "(" + strings.Join(elems, " ") + ")"
+	if !assoc && len(elems) <= maxInlineArray && len(inline) <= maxInlineWidth && !strings.Contains(inline, "\n") {
+		return inline, true
+	}
+	return "(\n" + strings.Join(elems, "\n") + "\n)", true
+}
+
+// wordText returns w double-quoted if it is a string without expansions
+// or characters that double quotes would expand.
+func wordText(src []byte, w *syntax.Word) string {
+	orig := string(src[w.Pos().Offset():w.End().Offset()])
+	if len(w.Parts) != 1 {
+		return orig
+	}
+
+	var value string
+	switch p := w.Parts[0].(type) {
+	case *syntax.SglQuoted:
+		if p.Dollar {
+			return orig
+		}
+		value = p.Value
+	case *syntax.Lit:
+		// Unquoted words may have escapes, globs or tildes,
+		// and numbers stay unquoted.
+		if strings.ContainsAny(p.Value, "\\*?[~{") || isNumber(p.Value) {
+			return orig
+		}
+		value = p.Value
+	default:
+		return orig
+	}
+
+	if strings.ContainsAny(value, "$`\\\"!") {
+		return orig
+	}
+	return "\"" + value + "\""
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scriptfmt_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/scriptfmt"
+)
+
+func TestFormat(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "order",
+			src: `# Maintainer: Foo <foo@example.com>
+
+# Builds foo
+build() {
+	make
+}
+sources=('https://example.com/foo-1.0.tar.gz')
+license=(MIT)
+version='1.0'
+name=foo
+release=1
+deps_amd64=('bar')
+deps=('baz')
+`,
+			want: `# Maintainer: Foo <foo@example.com>
+
+name="foo"
+version="1.0"
+release=1
+license=("MIT")
+deps=("baz")
+deps_amd64=("bar")
+sources=("https://example.com/foo-1.0.tar.gz")
+
+# Builds foo
+build() {
+    make
+}
+`,
+		},
+		{
+			name: "uses",
+			src: `name=foo
+sources=("https://example.com/foo-$version.tar.gz")
+version=1.0
+`,
+			want: `name="foo"
+sources=("https://example.com/foo-$version.tar.gz")
+version="1.0"
+`,
+		},
+		{
+			name: "barriers",
+			src: `version=1.0
+name=foo
+_commit=abc
+release=1
+name_x=y
+echo hi
+desc=bar
+build() {
+	true
+}
+true
+`,
+			want: `name="foo"
+version="1.0"
+_commit="abc"
+name_x="y"
+release=1
+echo hi
+desc="bar"
+build() {
+    true
+}
+true
+`,
+		},
+		{
+			name: "trailing comment",
+			src: `version=1.0
+name=foo
+
+# trailing comment
+`,
+			want: `name="foo"
+version="1.0"
+
+# trailing comment
+`,
+		},
+		{
+			name: "arrays",
+			src: `name=foo
+architectures=(amd64
+	arm64)
+deps=("$x" 'y z' 'it'"'"'s' ~/a *.b 'c$d')
+sources=('a' 'b' 'c' 'd')
+checksums=(
+	'SKIP' # a
+	'SKIP'
+)
+scripts=([postinstall]=postinstall.sh)
+`,
+			want: `name="foo"
+architectures=("amd64" "arm64")
+deps=(
+    "$x"
+    "y z"
+    'it'"'"'s'
+    ~/a
+    *.b
+    'c$d'
+)
+sources=(
+    "a"
+    "b"
+    "c"
+    "d"
+)
+checksums=(
+    'SKIP' # a
+    'SKIP'
+)
+scripts=(
+    [postinstall]="postinstall.sh"
+)
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := scriptfmt.Format([]byte(tc.src))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(out))
+
+			again, err := scriptfmt.Format(out)
+			require.NoError(t, err)
+			assert.Equal(t, string(out), string(again))
+		})
+	}
+}
+
+func TestFormatIdempotent(t *testing.T) {
+	src, err := os.ReadFile("../../packaging/Staplerfile")
+	require.NoError(t, err)
+
+	out, err := scriptfmt.Format(src)
+	require.NoError(t, err)
+
+	again, err := scriptfmt.Format(out)
+	require.NoError(t, err)
+	assert.Equal(t, string(out), string(again))
+}
+
+func TestFormatError(t *testing.T) {
+	_, err := scriptfmt.Format([]byte("if true; then\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,473 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package scriptlint finds mistakes in Staplerfiles without building
+// them, such as misspelled variables and unknown helpers.
+package scriptlint
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/internal/pkglint"
+	"go.stplr.dev/stplr/internal/scriptedit"
+	"go.stplr.dev/stplr/internal/shutils/helpers"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+// Names of the checks.
+const (
+	CheckSyntax          = "syntax"
+	CheckEval            = "eval"
+	CheckUnknownVariable = "unknown-variable"
+	CheckUnknownHelper   = "unknown-helper"
+	CheckPackageFunction = "package-function"
+	CheckChecksums       = "checksums-length"
+	CheckLicense         = "missing-license"
+	CheckUnquoted        = "unquoted-variable"
+)
+
+var severities = map[string]pkglint.Severity{
+	CheckSyntax:          pkglint.SeverityError,
+	CheckEval:            pkglint.SeverityError,
+	CheckUnknownVariable: pkglint.SeverityWarning,
+	CheckUnknownHelper:   pkglint.SeverityError,
+	CheckPackageFunction: pkglint.SeverityError,
+	CheckChecksums:       pkglint.SeverityError,
+	CheckLicense:         pkglint.SeverityWarning,
+	CheckUnquoted:        pkglint.SeverityWarning,
+}
+
+// Diagnostic is a mistake found at a position of a script.
+// Line and Column start at 1.
+type Diagnostic struct {
+	Line     uint             `json:"line"`
+	Column   uint             `json:"column"`
+	Severity pkglint.Severity `json:"severity"`
+	Check    string           `json:"check"`
+	Message  string           `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s: %s", d.Line, d.Column, d.Severity, d.Check, d.Message)
+}
+
+// HasErrors reports whether any of diags is an error.
+func HasErrors(diags []Diagnostic) bool {
+	return slices.ContainsFunc(diags, func(d Diagnostic) bool {
+		return d.Severity == pkglint.SeverityError
+	})
+}
+
+func newDiagnostic(pos syntax.Pos, check, msg string) Diagnostic {
+	line, col := pos.Line(), pos.Col()
+	if !pos.IsValid() {
+		line, col = 1, 1
+	}
+	return Diagnostic{
+		Line:     line,
+		Column:   col,
+		Severity: severities[check],
+		Check:    check,
+		Message:  msg,
+	}
+}
+
+// ParseError returns the diagnostic of an error of syntax.Parser.
+func ParseError(err error) Diagnostic {
+	var pos syntax.Pos
+	if perr, ok := err.(syntax.ParseError); ok {
+		pos = perr.Pos
+		err = fmt.Errorf("%s", perr.Text)
+	}
+	return newDiagnostic(pos, CheckSyntax, err.Error())
+}
+
+// EvalError returns the diagnostic of an error of evaluating a script.
+func EvalError(err error) Diagnostic {
+	return newDiagnostic(syntax.Pos{}, CheckEval, err.Error())
+}
+
+// knownVariables are the variables of a script, from the sh tags of the
+// structs it is decoded into.
+var knownVariables = func() []string {
+	var vars []string
+	for _, v := range []any{staplerfile.Package{}, staplerfile.PackageNames{}, staplerfile.ScriptOptions{}} {
+		t := reflect.TypeOf(v)
+		for i := range t.NumField() {
+			if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("sh"), ","); tag != "" {
+				vars = append(vars, tag)
+			}
+		}
+	}
+	slices.Sort(vars)
+	return vars
+}()
+
+// foreignVariables are variables of other packaging formats that
+// Staplerfiles have under another name.
+var foreignVariables = map[string]string{
+	"arch":          "architectures",
+	"build_depends": "build_deps",
+	"depends":       "deps",
+	"description":   "desc",
+	"licenses":      "license",
+	"makedepends":   "build_deps",
+	"md5sums":       "checksums",
+	"optdepends":    "opt_deps",
+	"pkgdesc":       "desc",
+	"pkgname":       "name",
+	"pkgrel":        "release",
+	"pkgver":        "version",
+	"sha256sums":    "checksums",
+	"sha512sums":    "checksums",
+	"source":        "sources",
+	"url":           "homepage",
+}
+
+// Check runs the checks that only need the syntax tree of a script.
+func Check(f *syntax.File) []Diagnostic {
+	var diags []Diagnostic
+	diags = append(diags, checkVariables(f)...)
+	diags = append(diags, checkHelpers(f)...)
+	diags = append(diags, checkUnquoted(f)...)
+	sortDiagnostics(diags)
+	return diags
+}
+
+// CheckPackages runs the checks that need the packages of a script,
+// as they are evaluated.
+func CheckPackages(f *syntax.File, pkgs []*staplerfile.Package) []Diagnostic {
+	var diags []Diagnostic
+	diags = append(diags, checkPackageFunctions(f, pkgs)...)
+	for _, pkg := range pkgs {
+		diags = append(diags, checkChecksums(f, pkg)...)
+		diags = append(diags, checkLicense(f, pkg)...)
+	}
+	sortDiagnostics(diags)
+	return diags
+}
+
+func sortDiagnostics(diags []Diagnostic) {
+	slices.SortStableFunc(diags, func(a, b Diagnostic) int {
+		if a.Line != b.Line {
+			return int(a.Line) - int(b.Line)
+		}
+		return int(a.Column) - int(b.Column)
+	})
+}
+
+// checkVariables reports the variables assigned at the top level and in
+// meta functions that look like misspelled variables of the script.
+// Variables that start with an underscore are private to the script.
+func checkVariables(f *syntax.File) []Diagnostic {
+	var diags []Diagnostic
+	for _, scope := range variableScopes(f) {
+		syntax.Walk(scope, func(node syntax.Node) bool {
+			switch n := node.(type) {
+			case *syntax.FuncDecl:
+				return false
+			case *syntax.CallExpr:
+				if len(n.Args) > 0 {
+					return true
+				}
+				for _, a := range n.Assigns {
+					if a.Name == nil || strings.HasPrefix(a.Name.Value, "_") || isKnownVariable(a.Name.Value) {
+						continue
+					}
+					if s := suggestVariable(a.Name.Value); s != "" {
+						diags = append(diags, newDiagnostic(a.Name.Pos(), CheckUnknownVariable,
+							fmt.Sprintf("unknown variable %s, did you mean %s?", a.Name.Value, s)))
+					}
+				}
+			}
+			return true
+		})
+	}
+	return diags
+}
+
+// variableScopes returns the top level of f and its meta functions.
+func variableScopes(f *syntax.File) []syntax.Node {
+	scopes := []syntax.Node{f}
+	for _, stmt := range f.Stmts {
+		if fn, ok := stmt.Cmd.(*syntax.FuncDecl); ok && strings.HasPrefix(fn.Name.Value, "meta_") {
+			scopes = append(scopes, fn.Body)
+		}
+	}
+	return scopes
+}
+
+// isKnownVariable tells if name is a variable of the script,
+// possibly with an override suffix.
+func isKnownVariable(name string) bool {
+	for _, v := range knownVariables {
+		if name == v || strings.HasPrefix(name, v+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestVariable returns the variable that name is likely a misspelling
+// of, keeping its override suffix, or an empty string.
+func suggestVariable(name string) string {
+	prefix, suffix := name, ""
+	for {
+		if v, ok := foreignVariables[prefix]; ok {
+			return v + suffix
+		}
+		if v := closest(prefix, knownVariables); v != "" {
+			return v + suffix
+		}
+
+		i := strings.LastIndexByte(prefix, '_')
+		if i <= 0 {
+			return ""
+		}
+		prefix, suffix = prefix[:i], prefix[i:]+suffix
+	}
+}
+
+// checkHelpers reports the commands that are named like helpers but
+// are not ones.
+func checkHelpers(f *syntax.File) []Diagnostic {
+	names := make([]string, 0, len(helpers.Helpers))
+	for name := range helpers.Helpers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var diags []Diagnostic
+	syntax.Walk(f, func(node syntax.Node) bool {
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		name := call.Args[0].Lit()
+		if !looksLikeHelper(name) {
+			return true
+		}
+		if _, ok := helpers.Helpers[name]; ok {
+			return true
+		}
+
+		msg := fmt.Sprintf("unknown helper %s", name)
+		if s := closest(name, names); s != "" {
+			msg += fmt.Sprintf(", did you mean %s?", s)
+		}
+		diags = append(diags, newDiagnostic(call.Args[0].Pos(), CheckUnknownHelper, msg))
+		return true
+	})
+	return diags
+}
+
+func looksLikeHelper(name string) bool {
+	for _, prefix := range []string{"install-", "files-", "git-"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unquotedVariables are the variables with paths that must be quoted,
+// or the paths are split at their spaces.
+var unquotedVariables = []string{"pkgdir", "srcdir"}
+
+// checkUnquoted reports the arguments of commands
+// with the variables of unquotedVariables outside of quotes.
+func checkUnquoted(f *syntax.File) []Diagnostic {
+	var diags []Diagnostic
+	syntax.Walk(f, func(node syntax.Node) bool {
+		call, ok := node.(*syntax.CallExpr)
+		if !ok {
+			return true
+		}
+		for _, arg := range call.Args {
+			for _, part := range arg.Parts {
+				pe, ok := part.(*syntax.ParamExp)
+				if !ok || pe.Param == nil || !slices.Contains(unquotedVariables, pe.Param.Value) {
+					continue
+				}
+				diags = append(diags, newDiagnostic(pe.Pos(), CheckUnquoted,
+					fmt.Sprintf("$%s is not quoted, use \"$%s\"", pe.Param.Value, pe.Param.Value)))
+			}
+		}
+		return true
+	})
+	return diags
+}
+
+// checkPackageFunctions reports the package functions of split package
+// scripts for packages that are not in their name. Single package
+// scripts only have package functions without a suffix, so their
+// functions are helpers, whatever they are named.
+func checkPackageFunctions(f *syntax.File, pkgs []*staplerfile.Package) []Diagnostic {
+	if len(pkgs) < 2 {
+		return nil
+	}
+
+	var names []string
+	for _, pkg := range pkgs {
+		names = append(names, pkg.Name)
+	}
+
+	var diags []Diagnostic
+	for _, stmt := range f.Stmts {
+		fn, ok := stmt.Cmd.(*syntax.FuncDecl)
+		if !ok {
+			continue
+		}
+		for _, prefix := range []string{"package_", "files_", "meta_"} {
+			name, ok := strings.CutPrefix(fn.Name.Value, prefix)
+			if ok && !slices.Contains(names, name) {
+				diags = append(diags, newDiagnostic(fn.Name.Pos(), CheckPackageFunction,
+					fmt.Sprintf("%s is for package %s, which is not in name", fn.Name.Value, name)))
+			}
+		}
+	}
+	return diags
+}
+
+// checkChecksums reports the overrides of the sources or checksums of
+// pkg that leave them with different lengths. An override of one of
+// them is checked against the same override of the other one or its
+// value without overrides.
+func checkChecksums(f *syntax.File, pkg *staplerfile.Package) []Diagnostic {
+	sources, checksums := pkg.Sources.All(), pkg.Checksums.All()
+
+	keys := map[string]bool{}
+	for k := range sources {
+		keys[k] = true
+	}
+	for k := range checksums {
+		keys[k] = true
+	}
+
+	var diags []Diagnostic
+	for _, key := range sortedKeys(keys) {
+		s, ok := sources[key]
+		if !ok {
+			s = sources[""]
+		}
+		c, ok := checksums[key]
+		if !ok {
+			c = checksums[""]
+		}
+		if len(s) == len(c) {
+			continue
+		}
+
+		name := overrideName("checksums", key)
+		pos := findAssignment(f, pkg, name)
+		if !pos.IsValid() {
+			pos = findAssignment(f, pkg, overrideName("sources", key))
+		}
+		diags = append(diags, newDiagnostic(pos, CheckChecksums,
+			fmt.Sprintf("%s of %s has %d entries, but there are %d sources", name, pkg.Name, len(c), len(s))))
+	}
+	return diags
+}
+
+func checkLicense(f *syntax.File, pkg *staplerfile.Package) []Diagnostic {
+	if len(pkg.Licenses) > 0 {
+		return nil
+	}
+	return []Diagnostic{newDiagnostic(findAssignment(f, pkg, "name"), CheckLicense,
+		fmt.Sprintf("package %s has no license", pkg.Name))}
+}
+
+// findAssignment returns the position of the assignment of name that
+// pkg gets its value from.
+func findAssignment(f *syntax.File, pkg *staplerfile.Package, name string) syntax.Pos {
+	scopes := []string{""}
+	if pkg.BasePkgName != "" {
+		scopes = []string{"meta_" + pkg.Name, ""}
+	}
+	for _, scope := range scopes {
+		if _, a, err := scriptedit.Find(f, scope, name); err == nil && a != nil {
+			return a.Pos()
+		}
+	}
+	return syntax.Pos{}
+}
+
+func overrideName(name, key string) string {
+	if key == "" {
+		return name
+	}
+	return name + "_" + key
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// closest returns the candidate that is a few edits away from name,
+// or an empty string if there is none.
+func closest(name string, candidates []string) string {
+	maxDist := 1
+	switch {
+	case len(name) > 8:
+		maxDist = 3
+	case len(name) > 4:
+		maxDist = 2
+	}
+
+	best, bestDist := "", maxDist+1
+	for _, c := range candidates {
+		if c == name {
+			return ""
+		}
+		if d := distance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// distance returns the Levenshtein distance of a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
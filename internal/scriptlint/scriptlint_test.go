@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package scriptlint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/internal/pkglint"
+	"go.stplr.dev/stplr/internal/scriptlint"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+func parse(t *testing.T, src string) *syntax.File {
+	t.Helper()
+	f, err := syntax.NewParser().Parse(strings.NewReader(src), "Staplerfile")
+	require.NoError(t, err)
+	return f
+}
+
+func checks(diags []scriptlint.Diagnostic) []string {
+	var out []string
+	for _, d := range diags {
+		out = append(out, d.String())
+	}
+	return out
+}
+
+func TestCheck(t *testing.T) {
+	f := parse(t, `name='foo'
+version='1.0'
+build_depends=('gcc')
+deps_amd64=('bar')
+pkgdesc='Foo'
+_commit='abc'
+my_own='x'
+
+package() {
+	install-binary foo
+	install-binray foo
+	install -Dm755 foo $pkgdir/usr/bin/foo
+	cp foo "$pkgdir/usr/bin/foo"
+	local relase=1
+}
+`)
+
+	assert.Equal(t, []string{
+		"3:1: warning: unknown-variable: unknown variable build_depends, did you mean build_deps?",
+		"5:1: warning: unknown-variable: unknown variable pkgdesc, did you mean desc?",
+		"11:2: error: unknown-helper: unknown helper install-binray, did you mean install-binary?",
+		"12:21: warning: unquoted-variable: $pkgdir is not quoted, use \"$pkgdir\"",
+	}, checks(scriptlint.Check(f)))
+}
+
+func TestCheckMeta(t *testing.T) {
+	f := parse(t, `name=('foo' 'bar')
+basepkg_name='foobar'
+
+meta_foo() {
+	desc='Foo'
+	licnse=('MIT')
+	makedepends_amd64=('gcc')
+}
+`)
+
+	assert.Equal(t, []string{
+		"6:2: warning: unknown-variable: unknown variable licnse, did you mean license?",
+		"7:2: warning: unknown-variable: unknown variable makedepends_amd64, did you mean build_deps_amd64?",
+	}, checks(scriptlint.Check(f)))
+}
+
+func TestCheckPackages(t *testing.T) {
+	f := parse(t, `name=('foo' 'bar')
+basepkg_name='foobar'
+sources=('a' 'b')
+checksums=('SKIP' 'SKIP')
+sources_amd64=('a' 'b' 'c')
+
+meta_foo() {
+	license=('MIT')
+}
+
+meta_bar() {
+	license=('MIT')
+	checksums_arm64=('SKIP')
+}
+
+package_foo() {
+	true
+}
+
+package_baz() {
+	true
+}
+`)
+
+	pkg := func(name string, sources, checksums map[string][]string) *staplerfile.Package {
+		p := &staplerfile.Package{Name: name, BasePkgName: "foobar", Licenses: []string{"MIT"}}
+		for k, v := range sources {
+			p.Sources.Set(k, v)
+		}
+		for k, v := range checksums {
+			p.Checksums.Set(k, v)
+		}
+		return p
+	}
+	sources := map[string][]string{"": {"a", "b"}, "amd64": {"a", "b", "c"}}
+
+	diags := scriptlint.CheckPackages(f, []*staplerfile.Package{
+		pkg("foo", sources, map[string][]string{"": {"SKIP", "SKIP"}}),
+		pkg("bar", sources, map[string][]string{"": {"SKIP", "SKIP"}, "arm64": {"SKIP"}}),
+	})
+
+	assert.Equal(t, []string{
+		"5:1: error: checksums-length: checksums_amd64 of foo has 2 entries, but there are 3 sources",
+		"5:1: error: checksums-length: checksums_amd64 of bar has 2 entries, but there are 3 sources",
+		"13:2: error: checksums-length: checksums_arm64 of bar has 1 entries, but there are 2 sources",
+		"20:1: error: package-function: package_baz is for package baz, which is not in name",
+	}, checks(diags))
+	assert.True(t, scriptlint.HasErrors(diags))
+}
+
+func TestCheckPackageFunctionsSinglePackage(t *testing.T) {
+	f := parse(t, `name='foo'
+license=('MIT')
+
+package_docs() {
+	true
+}
+`)
+
+	assert.Empty(t, scriptlint.CheckPackages(f, []*staplerfile.Package{{Name: "foo", Licenses: []string{"MIT"}}}))
+}
+
+func TestCheckLicense(t *testing.T) {
+	f := parse(t, "name='foo'\nversion='1.0'\n")
+
+	diags := scriptlint.CheckPackages(f, []*staplerfile.Package{{Name: "foo"}})
+	require.Len(t, diags, 1)
+	assert.Equal(t, scriptlint.Diagnostic{
+		Line:     1,
+		Column:   1,
+		Severity: pkglint.SeverityWarning,
+		Check:    scriptlint.CheckLicense,
+		Message:  "package foo has no license",
+	}, diags[0])
+	assert.False(t, scriptlint.HasErrors(diags))
+}
+
+func TestParseError(t *testing.T) {
+	_, err := syntax.NewParser().Parse(strings.NewReader("name='foo'\nif true; then\n"), "Staplerfile")
+	require.Error(t, err)
+
+	d := scriptlint.ParseError(err)
+	assert.Equal(t, scriptlint.CheckSyntax, d.Check)
+	assert.Equal(t, pkglint.SeverityError, d.Severity)
+	assert.Equal(t, uint(2), d.Line)
+}
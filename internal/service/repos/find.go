@@ -28,8 +28,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"go.stplr.dev/stplr/internal/depver"
 	"go.stplr.dev/stplr/pkg/staplerfile"
 	"go.stplr.dev/stplr/pkg/types"
 )
@@ -67,11 +69,17 @@ func (rs *Repos) FindPkgs(ctx context.Context, pkgs []string) (map[string][]stap
 			continue
 		}
 
-		result, err := rs.lookupPkg(ctx, pkgName)
+		dep := depver.Parse(pkgName)
+
+		result, err := rs.lookupPkg(ctx, dep.Name)
 		if err != nil {
 			return nil, nil, fmt.Errorf("FindPkgs: lookup for %q failed: %w", pkgName, err)
 		}
 
+		result = slices.DeleteFunc(result, func(pkg staplerfile.Package) bool {
+			return !dep.Satisfies(pkgVersion(&pkg))
+		})
+
 		if len(result) == 0 {
 			notFound = append(notFound, pkgName)
 		} else {
@@ -108,6 +116,10 @@ func (rs *Repos) lookupPkg(ctx context.Context, pkgName string) ([]staplerfile.P
 	return result, nil
 }
 
+func pkgVersion(pkg *staplerfile.Package) string {
+	return fmt.Sprintf("%d:%s-%d", pkg.Epoch, pkg.Version, pkg.Release)
+}
+
 func ExtractNameAndRepo(pkgName string) (string, string, bool) {
 	switch {
 	case strings.Contains(pkgName, "/"):
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/opencontainers/cgroups/fscommon"
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// cpuPeriod is the CFS period the CPU quota of a sandbox is set for.
+const cpuPeriod = 100000
+
+// Limits are the cgroup resource limits of a sandbox.
+// Zero values are not applied.
+type Limits struct {
+	// MemoryMax is the memory the sandbox may use, in bytes.
+	// The sandbox can't use swap when it is set.
+	MemoryMax int64
+	// CPUMax is the number of CPUs the sandbox may use, such as 1.5.
+	CPUMax float64
+	// PidsMax is the number of processes the sandbox may run.
+	PidsMax int64
+	// IOWeight is the block I/O weight of the sandbox, from 10 to 1000.
+	IOWeight uint16
+}
+
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// WithLimits applies the resource limits l to the sandbox.
+func WithLimits(l Limits) SandboxOption {
+	return func(o *sandboxOptions) {
+		o.limits = l
+	}
+}
+
+func (l Limits) resources() *specs.LinuxResources {
+	if l.IsZero() {
+		return nil
+	}
+
+	resources := &specs.LinuxResources{}
+	if l.MemoryMax > 0 {
+		limit := l.MemoryMax
+		// swap is the limit of memory and swap together
+		swap := l.MemoryMax
+		resources.Memory = &specs.LinuxMemory{Limit: &limit, Swap: &swap}
+	}
+	if l.CPUMax > 0 {
+		quota := int64(l.CPUMax * cpuPeriod)
+		period := uint64(cpuPeriod)
+		resources.CPU = &specs.LinuxCPU{Quota: &quota, Period: &period}
+	}
+	if l.PidsMax > 0 {
+		pids := l.PidsMax
+		resources.Pids = &specs.LinuxPids{Limit: &pids}
+	}
+	if l.IOWeight > 0 {
+		weight := l.IOWeight
+		resources.BlockIO = &specs.LinuxBlockIO{Weight: &weight}
+	}
+	return resources
+}
+
+const (
+	ResourceMemory = "memory"
+	ResourcePids   = "pids"
+)
+
+// LimitError is returned when a command of the sandbox failed
+// after the sandbox hit one of its resource limits.
+type LimitError struct {
+	// Resource is ResourceMemory if the command was killed for running
+	// out of memory or ResourcePids if it could not start a process.
+	Resource string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("the %s limit of the sandbox was exceeded", e.Resource)
+}
+
+// limitEvents counts the cgroup events of a sandbox
+// that tell that one of its limits was hit.
+type limitEvents struct {
+	paths    map[string]string
+	oomKills uint64
+	pidsMax  uint64
+}
+
+func newLimitEvents(container *libcontainer.Container) *limitEvents {
+	e := &limitEvents{}
+	if state, err := container.State(); err == nil {
+		e.paths = state.CgroupPaths
+	}
+	e.oomKills, e.pidsMax = e.counts()
+	return e
+}
+
+func (e *limitEvents) counts() (oomKills, pidsMax uint64) {
+	// cgroup v2 has a single unified path, v1 has a path per controller
+	if dir, ok := e.paths[""]; ok {
+		oomKills, _ = fscommon.GetValueByKey(dir, "memory.events", "oom_kill")
+		pidsMax, _ = fscommon.GetValueByKey(dir, "pids.events", "max")
+		return oomKills, pidsMax
+	}
+	if dir, ok := e.paths["memory"]; ok {
+		oomKills, _ = fscommon.GetValueByKey(dir, "memory.oom_control", "oom_kill")
+	}
+	if dir, ok := e.paths["pids"]; ok {
+		pidsMax, _ = fscommon.GetValueByKey(dir, "pids.events", "max")
+	}
+	return oomKills, pidsMax
+}
+
+// check returns a LimitError if a limit was hit since the last check.
+func (e *limitEvents) check() error {
+	if e == nil || e.paths == nil {
+		return nil
+	}
+
+	oomKills, pidsMax := e.oomKills, e.pidsMax
+	e.oomKills, e.pidsMax = e.counts()
+
+	switch {
+	case e.oomKills > oomKills:
+		return &LimitError{Resource: ResourceMemory}
+	case e.pidsMax > pidsMax:
+		return &LimitError{Resource: ResourcePids}
+	}
+	return nil
+}
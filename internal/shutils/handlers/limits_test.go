@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/cgroups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsResources(t *testing.T) {
+	assert.Nil(t, Limits{}.resources())
+
+	r := Limits{MemoryMax: 1 << 30, CPUMax: 1.5, PidsMax: 512, IOWeight: 100}.resources()
+	require.NotNil(t, r)
+
+	assert.Equal(t, int64(1<<30), *r.Memory.Limit)
+	assert.Equal(t, int64(1<<30), *r.Memory.Swap)
+	assert.Equal(t, int64(150000), *r.CPU.Quota)
+	assert.Equal(t, uint64(100000), *r.CPU.Period)
+	assert.Equal(t, int64(512), *r.Pids.Limit)
+	assert.Equal(t, uint16(100), *r.BlockIO.Weight)
+
+	r = Limits{PidsMax: 64}.resources()
+	require.NotNil(t, r)
+	assert.Nil(t, r.Memory)
+	assert.Nil(t, r.CPU)
+	assert.Nil(t, r.BlockIO)
+}
+
+func TestLimitEventsCheck(t *testing.T) {
+	// let the cgroup files be read from a regular directory
+	cgroups.TestMode = true
+	t.Cleanup(func() { cgroups.TestMode = false })
+
+	dir := t.TempDir()
+	write := func(file, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+	}
+	write("memory.events", "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n")
+	write("pids.events", "max 0\n")
+
+	e := &limitEvents{paths: map[string]string{"": dir}}
+	e.oomKills, e.pidsMax = e.counts()
+	require.NoError(t, e.check())
+
+	write("memory.events", "low 0\nhigh 0\nmax 5\noom 2\noom_kill 2\n")
+	var limitErr *LimitError
+	require.True(t, errors.As(e.check(), &limitErr))
+	assert.Equal(t, ResourceMemory, limitErr.Resource)
+	require.NoError(t, e.check())
+
+	write("pids.events", "max 4\n")
+	require.True(t, errors.As(e.check(), &limitErr))
+	assert.Equal(t, ResourcePids, limitErr.Resource)
+
+	assert.NoError(t, (&limitEvents{}).check())
+}
@@ -21,6 +21,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
@@ -31,6 +32,7 @@ import (
 	"syscall"
 	"time"
 
+	securejoin "github.com/cyphar/filepath-securejoin"
 	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/opencontainers/runc/libcontainer/specconv"
@@ -46,15 +48,68 @@ import (
 )
 
 type SandboxHandlerInstance struct {
-	handler   interp.ExecHandlerFunc
-	container *libcontainer.Container
-	cleanup   func()
+	handler     interp.ExecHandlerFunc
+	container   *libcontainer.Container
+	cleanup     func()
+	killTimeout time.Duration
+	events      *limitEvents
+	caps        *configs.Capabilities
+}
+
+type sandboxOptions struct {
+	rootfs       string
+	limits       Limits
+	seccomp      *specs.LinuxSeccomp
+	capabilities []string
+	binds        []string
+}
+
+type SandboxOption func(*sandboxOptions)
+
+// WithRootfs runs the sandbox in rootfs instead of the host system
+// directories. The rootfs is not removed by Cleanup.
+func WithRootfs(rootfs string) SandboxOption {
+	return func(o *sandboxOptions) {
+		o.rootfs = rootfs
+	}
+}
+
+// WithBinds mounts the host paths binds read-only
+// into the sandbox at the same paths.
+func WithBinds(binds []string) SandboxOption {
+	return func(o *sandboxOptions) {
+		o.binds = append(o.binds, binds...)
+	}
 }
 
 func (i *SandboxHandlerInstance) Exec(ctx context.Context, args []string) error {
 	return i.handler(ctx, args)
 }
 
+// Run runs args in the sandbox outside of a shell script.
+func (i *SandboxHandlerInstance) Run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	process := &libcontainer.Process{
+		Args:         args,
+		Env:          []string{"PATH=" + defaultPath, "HOME=/root", "DEBIAN_FRONTEND=noninteractive"},
+		Cwd:          "/",
+		Stdout:       stdout,
+		Stderr:       stderr,
+		Capabilities: i.caps,
+	}
+
+	if err := i.container.Run(process); err != nil {
+		return fmt.Errorf("run failed: %w", err)
+	}
+
+	err := waitForProcess(ctx, process, i.killTimeout)
+	if err != nil && ctx.Err() == nil {
+		if limitErr := i.events.check(); limitErr != nil {
+			return limitErr
+		}
+	}
+	return err
+}
+
 func (i *SandboxHandlerInstance) Cleanup() {
 	i.cleanup()
 }
@@ -63,58 +118,87 @@ func (i *SandboxHandlerInstance) Rootfs() string {
 	return i.container.Config().Rootfs
 }
 
-func SandboxHandler(killTimeout time.Duration, dirs types.Directories, disableNetwork bool) (*SandboxHandlerInstance, error) {
-	container, cleanup, err := createContainer(dirs, disableNetwork, true)
+func SandboxHandler(killTimeout time.Duration, dirs types.Directories, disableNetwork bool, opts ...SandboxOption) (*SandboxHandlerInstance, error) {
+	var o sandboxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.seccomp != nil && !SeccompSupported() {
+		return nil, ErrSeccompUnsupported
+	}
+
+	container, cleanup, err := createContainer(dirs, disableNetwork, true, o)
 	if err != nil {
 		return nil, err
 	}
 
-	err = startInitProcess(container)
+	caps := o.capabilitySet()
+
+	err = startInitProcess(container, caps)
 	if err != nil && isMountError(err) {
 		cleanup()
 
 		slog.Debug("cannot create isolated /proc, retrying bind mount")
 
-		container, cleanup, err = createContainer(dirs, disableNetwork, false)
+		container, cleanup, err = createContainer(dirs, disableNetwork, false, o)
 		if err != nil {
 			return nil, err
 		}
 
-		err = startInitProcess(container)
+		err = startInitProcess(container, caps)
 		if err != nil {
 			cleanup()
-			return nil, err
+			return nil, limitsError(err, o.limits)
 		}
 	} else if err != nil {
 		cleanup()
-		return nil, err
+		return nil, limitsError(err, o.limits)
 	}
 
-	handler := createHandler(container, killTimeout)
+	events := newLimitEvents(container)
+	handler := createHandler(container, killTimeout, o.rootfs, caps, events)
 
 	return &SandboxHandlerInstance{
 		handler,
 		container,
 		cleanup,
+		killTimeout,
+		events,
+		caps,
 	}, nil
 }
 
-func createContainer(dirs types.Directories, disableNetwork, isolatedProc bool) (*libcontainer.Container, func(), error) {
-	rootfsDir, containerDir, err := createTempDirs()
+// limitsError tells that err may be caused by the resource limits,
+// which can only be applied if the cgroups can be managed.
+func limitsError(err error, limits Limits) error {
+	if limits.IsZero() {
+		return err
+	}
+	return fmt.Errorf("applying the resource limits of the sandbox: %w", err)
+}
+
+func createContainer(dirs types.Directories, disableNetwork, isolatedProc bool, o sandboxOptions) (*libcontainer.Container, func(), error) {
+	rootfsDir, containerDir, err := createTempDirs(o.rootfs)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	cleanup := func() {
-		os.RemoveAll(rootfsDir)
+		if o.rootfs == "" {
+			os.RemoveAll(rootfsDir)
+		}
 		os.RemoveAll(containerDir)
 	}
 
-	spec, err := buildContainerSpec(rootfsDir, dirs, disableNetwork, isolatedProc)
+	spec, err := buildContainerSpec(rootfsDir, dirs, disableNetwork, isolatedProc, o.rootfs != "")
 	if err != nil {
 		cleanup()
 		return nil, nil, err
 	}
+	spec.Mounts = append(spec.Mounts, buildBindMounts(o.binds)...)
+	spec.Linux.Resources = o.limits.resources()
+	spec.Linux.Seccomp = o.seccomp
 
 	libcontainerConfig, err := createLibcontainerConfig(spec)
 	if err != nil {
@@ -137,27 +221,33 @@ func createContainer(dirs types.Directories, disableNetwork, isolatedProc bool)
 	return container, containerCleanup, nil
 }
 
-func createTempDirs() (string, string, error) {
-	rootfsDir, err := os.MkdirTemp("", "stplr-container-rootfs-*")
-	if err != nil {
-		return "", "", err
-	}
+func createTempDirs(rootfs string) (string, string, error) {
+	rootfsDir := rootfs
+	if rootfsDir == "" {
+		var err error
+		rootfsDir, err = os.MkdirTemp("", "stplr-container-rootfs-*")
+		if err != nil {
+			return "", "", err
+		}
 
-	tmpDir := filepath.Join(rootfsDir, "tmp")
-	if err := os.Mkdir(tmpDir, 0o1777); err != nil {
-		return "", "", err
+		tmpDir := filepath.Join(rootfsDir, "tmp")
+		if err := os.Mkdir(tmpDir, 0o1777); err != nil {
+			return "", "", err
+		}
 	}
 
 	containerDir, err := os.MkdirTemp("", "stplr-container-state-*")
 	if err != nil {
-		os.RemoveAll(rootfsDir)
+		if rootfs == "" {
+			os.RemoveAll(rootfsDir)
+		}
 		return "", "", err
 	}
 
 	return rootfsDir, containerDir, nil
 }
 
-func buildContainerSpec(rootfsDir string, dirs types.Directories, disableNetwork, isolatedProc bool) (*specs.Spec, error) {
+func buildContainerSpec(rootfsDir string, dirs types.Directories, disableNetwork, isolatedProc, cleanRoot bool) (*specs.Spec, error) {
 	uidMappings, gidMappings, err := generateMappings()
 	if err != nil {
 		return nil, err
@@ -168,7 +258,10 @@ func buildContainerSpec(rootfsDir string, dirs types.Directories, disableNetwork
 		return nil, err
 	}
 
-	mounts := buildMounts(homeDir, dirs, isolatedProc)
+	mounts := buildMounts(homeDir, dirs, isolatedProc, cleanRoot)
+	if cleanRoot && !disableNetwork {
+		mounts = append(mounts, buildResolvConfMount()...)
+	}
 	mounts = append(mounts, specs.Mount{
 		Destination: "/tmp",
 		Type:        "bind",
@@ -194,7 +287,9 @@ func buildContainerSpec(rootfsDir string, dirs types.Directories, disableNetwork
 	return spec, nil
 }
 
-func buildMounts(realHomeDir string, dirs types.Directories, isolatedProc bool) []specs.Mount {
+// buildMounts returns the mounts of the sandbox. The host system
+// directories are not mounted into a clean root.
+func buildMounts(realHomeDir string, dirs types.Directories, isolatedProc, cleanRoot bool) []specs.Mount {
 	mounts := []specs.Mount{
 		{
 			Destination: "/dev",
@@ -226,7 +321,9 @@ func buildMounts(realHomeDir string, dirs types.Directories, isolatedProc bool)
 		slog.Debug("mounting /proc with bind mount")
 	}
 
-	mounts = append(mounts, buildSystemMounts()...)
+	if !cleanRoot {
+		mounts = append(mounts, buildSystemMounts()...)
+	}
 	mounts = append(mounts, buildTmpfsMounts(realHomeDir)...)
 	mounts = append(mounts, buildWorkspaceMounts(dirs)...)
 
@@ -271,6 +368,21 @@ func buildSystemMounts() []specs.Mount {
 	return mounts
 }
 
+// buildResolvConfMount lets a clean root resolve host names
+// the same way the host does.
+func buildResolvConfMount() []specs.Mount {
+	const resolvConf = "/etc/resolv.conf"
+	if _, err := os.Stat(resolvConf); err != nil {
+		return nil
+	}
+	return []specs.Mount{{
+		Destination: resolvConf,
+		Type:        "bind",
+		Source:      resolvConf,
+		Options:     []string{"rbind", "ro"},
+	}}
+}
+
 func buildTmpfsMounts(homeDir string) []specs.Mount {
 	tmpfsPaths := []string{
 		constants.SystemCachePath,
@@ -309,7 +421,12 @@ func buildWorkspaceMounts(dirs types.Directories) []specs.Mount {
 		},
 	}
 
-	for _, path := range []string{dirs.SrcDir, dirs.PkgDir, dirs.HomeDir} {
+	paths := []string{dirs.SrcDir, dirs.PkgDir, dirs.HomeDir}
+	if dirs.CacheDir != "" {
+		paths = append(paths, dirs.CacheDir)
+	}
+
+	for _, path := range paths {
 		mounts = append(mounts, specs.Mount{
 			Destination: path,
 			Type:        "bind",
@@ -321,6 +438,19 @@ func buildWorkspaceMounts(dirs types.Directories) []specs.Mount {
 	return mounts
 }
 
+func buildBindMounts(binds []string) []specs.Mount {
+	mounts := make([]specs.Mount, 0, len(binds))
+	for _, path := range binds {
+		mounts = append(mounts, specs.Mount{
+			Destination: path,
+			Type:        "bind",
+			Source:      path,
+			Options:     []string{"rbind", "ro"},
+		})
+	}
+	return mounts
+}
+
 func buildNamespaces(disableNetwork bool) []specs.LinuxNamespace {
 	namespaces := []specs.LinuxNamespace{
 		{Type: specs.PIDNamespace},
@@ -367,33 +497,11 @@ func createLibcontainerConfig(spec *specs.Spec) (*configs.Config, error) {
 	return libcontainerConfig, nil
 }
 
-func getCapabilities() *configs.Capabilities {
-	capList := []string{
-		"CAP_CHOWN",
-		"CAP_DAC_OVERRIDE",
-		"CAP_FOWNER",
-		"CAP_FSETID",
-		"CAP_KILL",
-		"CAP_NET_BIND_SERVICE",
-		"CAP_SETFCAP",
-		"CAP_SETGID",
-		"CAP_SETPCAP",
-		"CAP_SETUID",
-		"CAP_SYS_CHROOT",
-	}
-
-	return &configs.Capabilities{
-		Bounding:  capList,
-		Effective: capList,
-		Permitted: capList,
-	}
-}
-
-func startInitProcess(container *libcontainer.Container) error {
+func startInitProcess(container *libcontainer.Container, caps *configs.Capabilities) error {
 	initProcess := &libcontainer.Process{
 		Args:         []string{"/bin/sh", "-c", "sleep infinity"},
 		Init:         true,
-		Capabilities: getCapabilities(),
+		Capabilities: caps,
 	}
 
 	if err := container.Run(initProcess); err != nil {
@@ -403,7 +511,7 @@ func startInitProcess(container *libcontainer.Container) error {
 	return nil
 }
 
-func createProcess(path string, args []string, hc interp.HandlerContext) *libcontainer.Process {
+func createProcess(path string, args []string, hc interp.HandlerContext, caps *configs.Capabilities) *libcontainer.Process {
 	return &libcontainer.Process{
 		Args:         append([]string{path}, args[1:]...),
 		Env:          execEnv(hc.Env),
@@ -411,11 +519,11 @@ func createProcess(path string, args []string, hc interp.HandlerContext) *libcon
 		Stdin:        hc.Stdin,
 		Stdout:       hc.Stdout,
 		Stderr:       hc.Stderr,
-		Capabilities: getCapabilities(),
+		Capabilities: caps,
 	}
 }
 
-func createHandler(container *libcontainer.Container, killTimeout time.Duration) interp.ExecHandlerFunc {
+func createHandler(container *libcontainer.Container, killTimeout time.Duration, rootfs string, caps *configs.Capabilities, events *limitEvents) interp.ExecHandlerFunc {
 	return func(ctx context.Context, args []string) error {
 		hc := interp.HandlerCtx(ctx)
 
@@ -424,21 +532,69 @@ func createHandler(container *libcontainer.Container, killTimeout time.Duration)
 			return interp.ExitStatus(127)
 		}
 
-		path, err := interp.LookPathDir(hc.Dir, hc.Env, args[0])
+		lookPath := interp.LookPathDir
+		if rootfs != "" {
+			lookPath = func(cwd string, env expand.Environ, file string) (string, error) {
+				return lookPathInRootfs(rootfs, env, file)
+			}
+		}
+
+		path, err := lookPath(hc.Dir, hc.Env, args[0])
 		if err != nil {
 			fmt.Fprintln(hc.Stderr, err)
 			return interp.ExitStatus(127)
 		}
 
-		process := createProcess(path, args, hc)
+		process := createProcess(path, args, hc, caps)
 
 		if err := container.Run(process); err != nil {
 			fmt.Fprintf(hc.Stderr, "run failed: %v\n", err)
 			return interp.ExitStatus(1)
 		}
 
-		return waitForProcess(ctx, process, killTimeout)
+		err = waitForProcess(ctx, process, killTimeout)
+		if err != nil && ctx.Err() == nil {
+			// a limit that was hit fails the whole script
+			if limitErr := events.check(); limitErr != nil {
+				fmt.Fprintln(hc.Stderr, limitErr)
+				return limitErr
+			}
+		}
+		return err
+	}
+}
+
+// defaultPath is used for commands that are not run by a script.
+const defaultPath = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+// lookPathInRootfs finds file in the PATH directories of rootfs. The
+// returned path is the one inside the rootfs.
+func lookPathInRootfs(rootfs string, env expand.Environ, file string) (string, error) {
+	if strings.Contains(file, "/") {
+		return file, nil
+	}
+
+	pathEnv := env.Get("PATH").String()
+	if pathEnv == "" {
+		pathEnv = defaultPath
 	}
+
+	for _, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" || !filepath.IsAbs(dir) {
+			continue
+		}
+		path := filepath.Join(dir, file)
+		hostPath, err := securejoin.SecureJoin(rootfs, path)
+		if err != nil {
+			continue
+		}
+		fi, err := os.Stat(hostPath)
+		if err == nil && fi.Mode().IsRegular() && fi.Mode().Perm()&0o111 != 0 {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%q: executable file not found in $PATH", file)
 }
 
 func waitForProcess(ctx context.Context, process *libcontainer.Process, killTimeout time.Duration) error {
@@ -20,11 +20,14 @@ package handlers
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"mvdan.cc/sh/v3/expand"
 
 	"go.stplr.dev/stplr/pkg/types"
 )
@@ -61,7 +64,7 @@ func TestBuildMounts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mounts := buildMounts(tt.homeDir, types.Directories{SrcDir: tt.srcDir, PkgDir: tt.pkgDir}, tt.isolatedProc)
+			mounts := buildMounts(tt.homeDir, types.Directories{SrcDir: tt.srcDir, PkgDir: tt.pkgDir}, tt.isolatedProc, false)
 
 			require.NotEmpty(t, mounts, "buildMounts should return non-empty slice")
 
@@ -158,7 +161,7 @@ func TestBuildMountsStructure(t *testing.T) {
 	dirs := types.Directories{SrcDir: srcDir, PkgDir: pkgDir, HomeDir: homeDir}
 
 	t.Run("check essential mounts present", func(t *testing.T) {
-		mounts := buildMounts(realHomeDir, dirs, true)
+		mounts := buildMounts(realHomeDir, dirs, true, false)
 
 		essentialMounts := []string{"/proc", "/dev", realHomeDir, srcDir, pkgDir, homeDir}
 
@@ -169,7 +172,7 @@ func TestBuildMountsStructure(t *testing.T) {
 	})
 
 	t.Run("isolated proc has no options", func(t *testing.T) {
-		mounts := buildMounts(realHomeDir, dirs, true)
+		mounts := buildMounts(realHomeDir, dirs, true, false)
 
 		procMount := findMount(mounts, "/proc")
 		require.NotNil(t, procMount)
@@ -181,7 +184,7 @@ func TestBuildMountsStructure(t *testing.T) {
 	})
 
 	t.Run("bind proc is readonly", func(t *testing.T) {
-		mounts := buildMounts(realHomeDir, dirs, false)
+		mounts := buildMounts(realHomeDir, dirs, false, false)
 
 		procMount := findMount(mounts, "/proc")
 		require.NotNil(t, procMount)
@@ -193,7 +196,7 @@ func TestBuildMountsStructure(t *testing.T) {
 	})
 
 	t.Run("dev mount has correct options", func(t *testing.T) {
-		mounts := buildMounts(realHomeDir, dirs, true)
+		mounts := buildMounts(realHomeDir, dirs, true, false)
 
 		devMount := findMount(mounts, "/dev")
 		require.NotNil(t, devMount)
@@ -204,6 +207,30 @@ func TestBuildMountsStructure(t *testing.T) {
 		assert.Contains(t, devMount.Options, "mode=755")
 		assert.Contains(t, devMount.Options, "size=65536k")
 	})
+
+	t.Run("build cache is mounted when set", func(t *testing.T) {
+		assert.Nil(t, findMount(buildMounts(realHomeDir, dirs, true, false), "/tmp/test-cache"))
+
+		cached := dirs
+		cached.CacheDir = "/tmp/test-cache"
+		cacheMount := findMount(buildMounts(realHomeDir, cached, true, false), cached.CacheDir)
+		require.NotNil(t, cacheMount)
+		assert.Equal(t, cached.CacheDir, cacheMount.Source)
+		assert.Contains(t, cacheMount.Options, "rw")
+	})
+}
+
+func TestBuildBindMounts(t *testing.T) {
+	assert.Empty(t, buildBindMounts(nil))
+
+	mounts := buildBindMounts([]string{"/opt/sdk", "/etc/vendor.lic"})
+	require.Len(t, mounts, 2)
+	for _, path := range []string{"/opt/sdk", "/etc/vendor.lic"} {
+		m := findMount(mounts, path)
+		require.NotNil(t, m, path)
+		assert.Equal(t, path, m.Source)
+		assert.Equal(t, []string{"rbind", "ro"}, m.Options)
+	}
 }
 
 func TestBuildMountsConsistency(t *testing.T) {
@@ -214,15 +241,15 @@ func TestBuildMountsConsistency(t *testing.T) {
 	dirs := types.Directories{SrcDir: srcDir, PkgDir: pkgDir, HomeDir: homeDir}
 
 	t.Run("isolated and bind produce same number of mounts", func(t *testing.T) {
-		isolatedMounts := buildMounts(realHomeDir, dirs, true)
-		bindMounts := buildMounts(realHomeDir, dirs, false)
+		isolatedMounts := buildMounts(realHomeDir, dirs, true, false)
+		bindMounts := buildMounts(realHomeDir, dirs, false, false)
 
 		assert.Equal(t, len(isolatedMounts), len(bindMounts),
 			"both mount modes should produce same number of mounts")
 	})
 
 	t.Run("all mounts have destination", func(t *testing.T) {
-		mounts := buildMounts(realHomeDir, dirs, true)
+		mounts := buildMounts(realHomeDir, dirs, true, false)
 
 		for i, mount := range mounts {
 			assert.NotEmpty(t, mount.Destination,
@@ -231,7 +258,7 @@ func TestBuildMountsConsistency(t *testing.T) {
 	})
 
 	t.Run("no duplicate destinations", func(t *testing.T) {
-		mounts := buildMounts(realHomeDir, dirs, true)
+		mounts := buildMounts(realHomeDir, dirs, true, false)
 
 		destinations := make(map[string]bool)
 		for _, mount := range mounts {
@@ -242,6 +269,43 @@ func TestBuildMountsConsistency(t *testing.T) {
 	})
 }
 
+func TestBuildMountsCleanRoot(t *testing.T) {
+	dirs := types.Directories{SrcDir: "/tmp/test-src", PkgDir: "/tmp/test-pkg", HomeDir: "/tmp/test-home"}
+
+	mounts := buildMounts("/home/testuser", dirs, true, true)
+
+	for _, path := range []string{"/usr", "/etc", "/lib", "/var"} {
+		assert.Nil(t, findMount(mounts, path), "host %q should not be mounted into a clean root", path)
+	}
+	for _, path := range []string{"/proc", "/dev", dirs.SrcDir, dirs.PkgDir, dirs.HomeDir} {
+		assert.NotNil(t, findMount(mounts, path), "mount %q should be present", path)
+	}
+}
+
+func TestLookPathInRootfs(t *testing.T) {
+	rootfs := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootfs, "usr/bin"), 0o755))
+	require.NoError(t, os.Symlink("usr/bin", filepath.Join(rootfs, "bin")))
+	require.NoError(t, os.WriteFile(filepath.Join(rootfs, "usr/bin/make"), nil, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootfs, "usr/bin/notes"), nil, 0o644))
+
+	env := expand.ListEnviron("PATH=/bin:/usr/local/bin")
+
+	path, err := lookPathInRootfs(rootfs, env, "make")
+	require.NoError(t, err)
+	assert.Equal(t, "/bin/make", path)
+
+	_, err = lookPathInRootfs(rootfs, env, "notes")
+	require.Error(t, err)
+
+	_, err = lookPathInRootfs(rootfs, env, "sh")
+	require.Error(t, err)
+
+	path, err = lookPathInRootfs(rootfs, env, "./configure")
+	require.NoError(t, err)
+	assert.Equal(t, "./configure", path)
+}
+
 // Helper function
 func findMount(mounts []specs.Mount, destination string) *specs.Mount {
 	for i := range mounts {
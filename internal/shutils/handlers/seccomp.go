@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/capabilities"
+	"github.com/opencontainers/runc/libcontainer/configs"
+	"github.com/opencontainers/runc/libcontainer/seccomp"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+// SeccompUnconfined turns off the seccomp filter of a sandbox
+// when it is used instead of the path of a profile.
+const SeccompUnconfined = "unconfined"
+
+// ErrSeccompUnsupported is returned when a sandbox should be run with
+// a seccomp profile by a stplr that was built without the seccomp tag,
+// which needs cgo and libseccomp.
+var ErrSeccompUnsupported = errors.New("seccomp is not supported by this build of stplr")
+
+// SeccompSupported tells if the sandbox can apply seccomp profiles.
+func SeccompSupported() bool {
+	return seccomp.Enabled
+}
+
+// DefaultCapabilities are the capabilities of the processes of a
+// sandbox unless WithCapabilities sets others.
+var DefaultCapabilities = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_SETFCAP",
+	"CAP_SETGID",
+	"CAP_SETPCAP",
+	"CAP_SETUID",
+	"CAP_SYS_CHROOT",
+}
+
+// deniedSyscalls fail with EPERM under the default profile. They manage
+// kernel keys, mounts, BPF programs, perf events, kernel modules and the
+// running kernel, none of which a build needs.
+var deniedSyscalls = []string{
+	"acct",
+	"add_key",
+	"bpf",
+	"delete_module",
+	"finit_module",
+	"fsconfig",
+	"fsmount",
+	"fsopen",
+	"fspick",
+	"init_module",
+	"kexec_file_load",
+	"kexec_load",
+	"keyctl",
+	"mount",
+	"mount_setattr",
+	"move_mount",
+	"open_by_handle_at",
+	"open_tree",
+	"perf_event_open",
+	"pivot_root",
+	"process_vm_readv",
+	"process_vm_writev",
+	"reboot",
+	"request_key",
+	"swapoff",
+	"swapon",
+	"umount",
+	"umount2",
+}
+
+// seccompArchitectures are the architectures the syscalls of the
+// default profile are denied for, so that a build can't get around
+// it with the syscalls of a compatibility mode.
+var seccompArchitectures = map[string][]specs.Arch{
+	"386":     {specs.ArchX86},
+	"amd64":   {specs.ArchX86_64, specs.ArchX86, specs.ArchX32},
+	"arm":     {specs.ArchARM},
+	"arm64":   {specs.ArchAARCH64, specs.ArchARM},
+	"loong64": {specs.ArchLOONGARCH64},
+	"ppc64le": {specs.ArchPPC64LE},
+	"riscv64": {specs.ArchRISCV64},
+	"s390x":   {specs.ArchS390X, specs.ArchS390},
+}
+
+// DefaultSeccompProfile returns the profile the build sandbox is run
+// with. It allows every syscall except the denied ones and ptrace
+// attaching to other processes, a process may still ask its parent
+// to trace it.
+func DefaultSeccompProfile() *specs.LinuxSeccomp {
+	eperm := uint(unix.EPERM)
+
+	ptraceRule := func(request uint64) specs.LinuxSyscall {
+		return specs.LinuxSyscall{
+			Names:    []string{"ptrace"},
+			Action:   specs.ActErrno,
+			ErrnoRet: &eperm,
+			Args: []specs.LinuxSeccompArg{{
+				Index: 0,
+				Value: request,
+				Op:    specs.OpEqualTo,
+			}},
+		}
+	}
+
+	return &specs.LinuxSeccomp{
+		DefaultAction: specs.ActAllow,
+		Architectures: slices.Clone(seccompArchitectures[runtime.GOARCH]),
+		Syscalls: []specs.LinuxSyscall{
+			{
+				Names:    slices.Clone(deniedSyscalls),
+				Action:   specs.ActErrno,
+				ErrnoRet: &eperm,
+			},
+			ptraceRule(unix.PTRACE_ATTACH),
+			ptraceRule(unix.PTRACE_SEIZE),
+		},
+	}
+}
+
+// LoadSeccompProfile reads a seccomp profile in the format of the OCI
+// runtime spec, which is also used by Docker and Podman.
+func LoadSeccompProfile(path string) (*specs.LinuxSeccomp, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading seccomp profile: %w", err)
+	}
+
+	var profile specs.LinuxSeccomp
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("parsing seccomp profile %s: %w", path, err)
+	}
+	if profile.DefaultAction == "" {
+		return nil, fmt.Errorf("seccomp profile %s has no defaultAction", path)
+	}
+
+	return &profile, nil
+}
+
+// WithSeccomp filters the syscalls of the sandbox with profile.
+// A nil profile runs the sandbox without a filter.
+func WithSeccomp(profile *specs.LinuxSeccomp) SandboxOption {
+	return func(o *sandboxOptions) {
+		o.seccomp = profile
+	}
+}
+
+// ParseCapabilities checks the capability names, which may be written
+// without the CAP_ prefix and in any case, and returns their canonical
+// form.
+func ParseCapabilities(names []string) ([]string, error) {
+	known := capabilities.KnownCapabilities()
+
+	caps := make([]string, 0, len(names))
+	for _, name := range names {
+		c := strings.ToUpper(strings.TrimSpace(name))
+		if !strings.HasPrefix(c, "CAP_") {
+			c = "CAP_" + c
+		}
+		if !slices.Contains(known, c) {
+			return nil, fmt.Errorf("unknown capability %q", name)
+		}
+		if !slices.Contains(caps, c) {
+			caps = append(caps, c)
+		}
+	}
+
+	return caps, nil
+}
+
+// WithCapabilities gives the processes of the sandbox the capabilities
+// caps instead of DefaultCapabilities. An empty caps drops them all.
+func WithCapabilities(caps []string) SandboxOption {
+	return func(o *sandboxOptions) {
+		o.capabilities = slices.Clone(caps)
+		if o.capabilities == nil {
+			o.capabilities = []string{}
+		}
+	}
+}
+
+// capabilitySet returns the capabilities of the processes of a sandbox.
+func (o sandboxOptions) capabilitySet() *configs.Capabilities {
+	capList := o.capabilities
+	if capList == nil {
+		capList = DefaultCapabilities
+	}
+
+	return &configs.Capabilities{
+		Bounding:  capList,
+		Effective: capList,
+		Permitted: capList,
+	}
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build seccomp
+
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/opencontainers/runc/libcontainer"
+	_ "github.com/opencontainers/runc/libcontainer/nsenter"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+	"mvdan.cc/sh/v3/expand"
+	"mvdan.cc/sh/v3/interp"
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+// probeEnv makes the test binary run the syscall it names and print
+// how it went instead of running the tests. The binary is copied into
+// the sandbox to be run by Exec.
+const probeEnv = "STPLR_SECCOMP_PROBE"
+
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == "init" {
+		libcontainer.Init()
+	}
+	if name := os.Getenv(probeEnv); name != "" {
+		fmt.Println(probeSyscall(name))
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func probeSyscall(name string) string {
+	var err error
+	errnoErr := func(errno unix.Errno) error {
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	}
+
+	switch name {
+	case "keyctl":
+		_, err = unix.KeyctlInt(unix.KEYCTL_GET_KEYRING_ID, unix.KEY_SPEC_SESSION_KEYRING, 1, 0, 0)
+	case "bpf":
+		_, _, errno := unix.Syscall(unix.SYS_BPF, unix.BPF_MAP_CREATE, 0, 0)
+		err = errnoErr(errno)
+	case "perf_event_open":
+		attr := unix.PerfEventAttr{Type: unix.PERF_TYPE_SOFTWARE, Config: unix.PERF_COUNT_SW_CPU_CLOCK}
+		attr.Size = uint32(unsafe.Sizeof(attr))
+		var fd int
+		if fd, err = unix.PerfEventOpen(&attr, 0, -1, -1, 0); err == nil {
+			unix.Close(fd)
+		}
+	case "kexec_load":
+		_, _, errno := unix.Syscall6(unix.SYS_KEXEC_LOAD, 0, 0, 0, 0, 0, 0)
+		err = errnoErr(errno)
+	case "mount":
+		err = unix.Mount("tmpfs", os.TempDir(), "tmpfs", 0, "")
+	case "ptrace":
+		err = unix.PtraceAttach(1)
+	case "uname":
+		var uts unix.Utsname
+		err = unix.Uname(&uts)
+	default:
+		return "unknown syscall " + name
+	}
+
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, unix.EPERM):
+		return "EPERM"
+	}
+	return err.Error()
+}
+
+// execProbe runs the syscall name through Exec in a sandbox
+// filtered by profile and returns what the probe printed.
+func execProbe(t *testing.T, profile *specs.LinuxSeccomp, name string) string {
+	t.Helper()
+
+	// /tmp of the sandbox is not the one of the host
+	dir, err := os.MkdirTemp("/var/tmp", "stplr-seccomp-test-*")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	dirs := types.Directories{
+		SrcDir:  filepath.Join(dir, "src"),
+		PkgDir:  filepath.Join(dir, "pkg"),
+		HomeDir: filepath.Join(dir, "home"),
+	}
+	for _, d := range []string{dirs.SrcDir, dirs.PkgDir, dirs.HomeDir} {
+		require.NoError(t, os.Mkdir(d, 0o755))
+	}
+	copyTestBinary(t, filepath.Join(dirs.SrcDir, "probe"))
+
+	sandbox, err := SandboxHandler(time.Second, dirs, true, WithSeccomp(profile))
+	if err != nil {
+		t.Skipf("the sandbox can't be created here: %v", err)
+	}
+	t.Cleanup(sandbox.Cleanup)
+
+	var out bytes.Buffer
+	runner, err := interp.New(
+		interp.Env(expand.ListEnviron("PATH="+defaultPath, probeEnv+"="+name)),
+		interp.Dir(dirs.SrcDir),
+		interp.StdIO(nil, &out, &out),
+		interp.ExecHandlers(func(interp.ExecHandlerFunc) interp.ExecHandlerFunc {
+			return sandbox.Exec
+		}),
+	)
+	require.NoError(t, err)
+
+	file, err := syntax.NewParser().Parse(strings.NewReader("./probe"), "")
+	require.NoError(t, err)
+	require.NoError(t, runner.Run(t.Context(), file), out.String())
+
+	return strings.TrimSpace(out.String())
+}
+
+func copyTestBinary(t *testing.T, dst string) {
+	t.Helper()
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	src, err := os.Open(exe)
+	require.NoError(t, err)
+	defer src.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY, 0o755)
+	require.NoError(t, err)
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	require.NoError(t, err)
+}
+
+func TestExecSeccompDefaultProfile(t *testing.T) {
+	for _, name := range []string{"keyctl", "bpf", "perf_event_open", "kexec_load", "mount", "ptrace"} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, "EPERM", execProbe(t, DefaultSeccompProfile(), name))
+		})
+	}
+
+	t.Run("uname", func(t *testing.T) {
+		assert.Equal(t, "ok", execProbe(t, DefaultSeccompProfile(), "uname"))
+	})
+}
+
+func TestExecSeccompUnconfined(t *testing.T) {
+	assert.Equal(t, "ok", execProbe(t, nil, "keyctl"))
+}
+
+func TestExecSeccompCustomProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"defaultAction": "SCMP_ACT_ALLOW",
+		"syscalls": [
+			{"names": ["uname"], "action": "SCMP_ACT_ERRNO", "errnoRet": 1}
+		]
+	}`), 0o644))
+
+	profile, err := LoadSeccompProfile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "EPERM", execProbe(t, profile, "uname"))
+	assert.Equal(t, "ok", execProbe(t, profile, "keyctl"))
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/runc/libcontainer/specconv"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+func TestDefaultSeccompProfile(t *testing.T) {
+	profile := DefaultSeccompProfile()
+	assert.Equal(t, specs.ActAllow, profile.DefaultAction)
+
+	var denied []string
+	var ptraceRequests []uint64
+	for _, rule := range profile.Syscalls {
+		assert.Equal(t, specs.ActErrno, rule.Action)
+		require.NotNil(t, rule.ErrnoRet)
+		assert.Equal(t, uint(unix.EPERM), *rule.ErrnoRet)
+
+		if len(rule.Args) == 0 {
+			denied = append(denied, rule.Names...)
+			continue
+		}
+		assert.Equal(t, []string{"ptrace"}, rule.Names)
+		ptraceRequests = append(ptraceRequests, rule.Args[0].Value)
+	}
+
+	for _, name := range []string{"keyctl", "add_key", "mount", "bpf", "perf_event_open", "kexec_load", "kexec_file_load"} {
+		assert.Contains(t, denied, name)
+	}
+	assert.NotContains(t, denied, "ptrace")
+	assert.ElementsMatch(t, []uint64{unix.PTRACE_ATTACH, unix.PTRACE_SEIZE}, ptraceRequests)
+
+	// the profile is converted by libcontainer when the sandbox is created
+	_, err := specconv.SetupSeccomp(profile)
+	require.NoError(t, err)
+
+	// the profile can't be changed through the one returned before
+	profile.Syscalls[0].Names[0] = "read"
+	assert.Equal(t, deniedSyscalls[0], DefaultSeccompProfile().Syscalls[0].Names[0])
+}
+
+func TestLoadSeccompProfile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	profile, err := LoadSeccompProfile(write("profile.json", `{
+		"defaultAction": "SCMP_ACT_ALLOW",
+		"syscalls": [
+			{"names": ["uname"], "action": "SCMP_ACT_ERRNO", "errnoRet": 1}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, specs.ActAllow, profile.DefaultAction)
+	require.Len(t, profile.Syscalls, 1)
+	assert.Equal(t, []string{"uname"}, profile.Syscalls[0].Names)
+
+	_, err = LoadSeccompProfile(write("empty.json", `{"syscalls": []}`))
+	assert.ErrorContains(t, err, "no defaultAction")
+
+	_, err = LoadSeccompProfile(write("broken.json", `{"defaultAction":`))
+	assert.Error(t, err)
+
+	_, err = LoadSeccompProfile(filepath.Join(dir, "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseCapabilities(t *testing.T) {
+	caps, err := ParseCapabilities([]string{"chown", "CAP_KILL", " net_bind_service ", "cap_chown"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CAP_CHOWN", "CAP_KILL", "CAP_NET_BIND_SERVICE"}, caps)
+
+	_, err = ParseCapabilities([]string{"CAP_CHOWN", "CAP_FLY"})
+	assert.ErrorContains(t, err, `"CAP_FLY"`)
+}
+
+func TestCapabilitySet(t *testing.T) {
+	var o sandboxOptions
+	assert.Equal(t, DefaultCapabilities, o.capabilitySet().Bounding)
+
+	WithCapabilities([]string{"CAP_KILL"})(&o)
+	set := o.capabilitySet()
+	assert.Equal(t, []string{"CAP_KILL"}, set.Bounding)
+	assert.Equal(t, []string{"CAP_KILL"}, set.Effective)
+	assert.Equal(t, []string{"CAP_KILL"}, set.Permitted)
+
+	WithCapabilities(nil)(&o)
+	assert.Empty(t, o.capabilitySet().Bounding)
+	assert.NotNil(t, o.capabilitySet().Bounding)
+}
+
+func TestSandboxHandlerSeccompUnsupported(t *testing.T) {
+	if SeccompSupported() {
+		t.Skip("stplr is built with seccomp")
+	}
+
+	_, err := SandboxHandler(time.Second, types.Directories{}, true, WithSeccomp(DefaultSeccompProfile()))
+	assert.ErrorIs(t, err, ErrSeccompUnsupported)
+}
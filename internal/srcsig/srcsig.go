@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package srcsig verifies the detached OpenPGP signatures that
+// upstreams publish for their sources.
+package srcsig
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+)
+
+// KeyringDir is the directory of a repository with the public keys,
+// one or more per *.asc file, that sources of its packages are signed
+// with.
+var KeyringDir = filepath.Join("keys", "pgp")
+
+var (
+	ErrNoKeyring     = errors.New("the repository has no keys to verify source signatures with")
+	ErrUnknownKey    = errors.New("signed by a key that is not in the keyring of the repository")
+	ErrKeyNotAllowed = errors.New("signed by a key that is not in validpgpkeys")
+	ErrBadSignature  = errors.New("bad signature")
+)
+
+// IsSignature tells by its extension if the file name is
+// a detached signature.
+func IsSignature(name string) bool {
+	switch path.Ext(name) {
+	case ".sig", ".asc":
+		return true
+	}
+	return false
+}
+
+// SignedFile returns the name of the file the signature sig is for.
+func SignedFile(sig string) string {
+	return strings.TrimSuffix(sig, path.Ext(sig))
+}
+
+// FindKeyring returns the keyring directory of the repository that has
+// the script in scriptDir, or an empty string if it has none. A script
+// is either at the root of its repository or in a directory of it.
+func FindKeyring(scriptDir string) string {
+	for _, dir := range []string{scriptDir, filepath.Dir(scriptDir)} {
+		keyring := filepath.Join(dir, KeyringDir)
+		if fi, err := os.Stat(keyring); err == nil && fi.IsDir() {
+			return keyring
+		}
+	}
+	return ""
+}
+
+// LoadKeyring reads the keys of the *.asc files in dir, which can be
+// ASCII-armored or binary.
+func LoadKeyring(dir string) (openpgp.EntityList, error) {
+	if dir == "" {
+		return nil, ErrNoKeyring
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.asc"))
+	if err != nil {
+		return nil, err
+	}
+
+	var keyring openpgp.EntityList
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file: %w", err)
+		}
+
+		if block, err := armor.Decode(bytes.NewReader(data)); err == nil {
+			data, err = io.ReadAll(block.Body)
+			if err != nil {
+				return nil, fmt.Errorf("decoding key file %s: %w", file, err)
+			}
+		}
+
+		keys, err := openpgp.ReadKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("parsing key file %s: %w", file, err)
+		}
+		keyring = append(keyring, keys...)
+	}
+
+	if len(keyring) == 0 {
+		return nil, ErrNoKeyring
+	}
+	return keyring, nil
+}
+
+// ParseFingerprints checks the fingerprints of validpgpkeys, which may
+// be written in any case and with spaces, and returns them as upper
+// case hex.
+func ParseFingerprints(keys []string) ([]string, error) {
+	fingerprints := make([]string, 0, len(keys))
+	for _, key := range keys {
+		fp := strings.ToUpper(strings.ReplaceAll(key, " ", ""))
+		if _, err := hex.DecodeString(fp); err != nil || len(fp) != 40 && len(fp) != 64 {
+			return nil, fmt.Errorf("%q is not a full key fingerprint", key)
+		}
+		if !slices.Contains(fingerprints, fp) {
+			fingerprints = append(fingerprints, fp)
+		}
+	}
+	return fingerprints, nil
+}
+
+// Verifier checks signatures against the keys of a repository.
+type Verifier struct {
+	keyring openpgp.EntityList
+	valid   []string
+}
+
+// NewVerifier returns a Verifier that accepts signatures made by the
+// keys of keyring. If valid has fingerprints, the primary key of the
+// signer must also be one of them.
+func NewVerifier(keyring openpgp.EntityList, valid []string) *Verifier {
+	return &Verifier{keyring: keyring, valid: valid}
+}
+
+// Verify checks the detached signature sig of file and returns the
+// fingerprint of the primary key of the signer.
+func (v *Verifier) Verify(file, sig string) (string, error) {
+	sigData, err := os.ReadFile(sig)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	check := openpgp.CheckDetachedSignature
+	if _, err := armor.Decode(bytes.NewReader(sigData)); err == nil {
+		check = openpgp.CheckArmoredDetachedSignature
+	}
+
+	signer, err := check(v.keyring, f, bytes.NewReader(sigData), nil)
+	switch {
+	case errors.Is(err, pgperrors.ErrUnknownIssuer):
+		return "", ErrUnknownKey
+	case err != nil:
+		return "", fmt.Errorf("%w: %w", ErrBadSignature, err)
+	}
+
+	fingerprint := strings.ToUpper(hex.EncodeToString(signer.PrimaryKey.Fingerprint))
+	if len(v.valid) > 0 && !slices.Contains(v.valid, fingerprint) {
+		return "", fmt.Errorf("%w: %s", ErrKeyNotAllowed, fingerprint)
+	}
+	return fingerprint, nil
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package srcsig_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/srcsig"
+)
+
+func newKey(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+	e, err := openpgp.NewEntity(name, "", name+"@example.com", &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA})
+	require.NoError(t, err)
+	return e
+}
+
+func fingerprint(e *openpgp.Entity) string {
+	return strings.ToUpper(hex.EncodeToString(e.PrimaryKey.Fingerprint))
+}
+
+func writePublicKey(t *testing.T, dir string, e *openpgp.Entity) {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, e.PrimaryIdentity().UserId.Name+".asc"), buf.Bytes(), 0o644))
+}
+
+func sign(t *testing.T, file string, e *openpgp.Entity, armored bool) string {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	var sig bytes.Buffer
+	if armored {
+		require.NoError(t, openpgp.ArmoredDetachSign(&sig, e, bytes.NewReader(data), nil))
+	} else {
+		require.NoError(t, openpgp.DetachSign(&sig, e, bytes.NewReader(data), nil))
+	}
+
+	ext := ".sig"
+	if armored {
+		ext = ".asc"
+	}
+	require.NoError(t, os.WriteFile(file+ext, sig.Bytes(), 0o644))
+	return file + ext
+}
+
+func TestVerify(t *testing.T) {
+	repo := t.TempDir()
+	keys := filepath.Join(repo, srcsig.KeyringDir)
+	require.NoError(t, os.MkdirAll(keys, 0o755))
+	pkgDir := filepath.Join(repo, "foo")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+
+	upstream := newKey(t, "upstream")
+	other := newKey(t, "other")
+	stranger := newKey(t, "stranger")
+	writePublicKey(t, keys, upstream)
+	writePublicKey(t, keys, other)
+
+	assert.Equal(t, keys, srcsig.FindKeyring(pkgDir))
+	assert.Equal(t, keys, srcsig.FindKeyring(repo))
+	assert.Empty(t, srcsig.FindKeyring(t.TempDir()))
+
+	keyring, err := srcsig.LoadKeyring(keys)
+	require.NoError(t, err)
+	assert.Len(t, keyring, 2)
+
+	src := t.TempDir()
+	file := filepath.Join(src, "foo-1.0.tar.gz")
+	require.NoError(t, os.WriteFile(file, []byte("sources"), 0o644))
+
+	v := srcsig.NewVerifier(keyring, nil)
+	for _, armored := range []bool{false, true} {
+		fp, err := v.Verify(file, sign(t, file, upstream, armored))
+		require.NoError(t, err)
+		assert.Equal(t, fingerprint(upstream), fp)
+	}
+
+	v = srcsig.NewVerifier(keyring, []string{fingerprint(upstream)})
+	_, err = v.Verify(file, sign(t, file, other, false))
+	assert.ErrorIs(t, err, srcsig.ErrKeyNotAllowed)
+
+	_, err = v.Verify(file, sign(t, file, stranger, false))
+	assert.ErrorIs(t, err, srcsig.ErrUnknownKey)
+
+	sig := sign(t, file, upstream, false)
+	require.NoError(t, os.WriteFile(file, []byte("tampered"), 0o644))
+	_, err = v.Verify(file, sig)
+	assert.ErrorIs(t, err, srcsig.ErrBadSignature)
+}
+
+func TestLoadKeyringEmpty(t *testing.T) {
+	_, err := srcsig.LoadKeyring("")
+	assert.ErrorIs(t, err, srcsig.ErrNoKeyring)
+
+	_, err = srcsig.LoadKeyring(t.TempDir())
+	assert.ErrorIs(t, err, srcsig.ErrNoKeyring)
+}
+
+func TestParseFingerprints(t *testing.T) {
+	fps, err := srcsig.ParseFingerprints([]string{
+		"a2c0 c1f8 3c52 17e2 a9b4  7d5e 6c31 9b0a f0d1 7e44",
+		"A2C0C1F83C5217E2A9B47D5E6C319B0AF0D17E44",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A2C0C1F83C5217E2A9B47D5E6C319B0AF0D17E44"}, fps)
+
+	for _, key := range []string{"F0D17E44", "6C319B0AF0D17E44", "not a key"} {
+		_, err := srcsig.ParseFingerprints([]string{key})
+		assert.Error(t, err, key)
+	}
+}
+
+func TestIsSignature(t *testing.T) {
+	assert.True(t, srcsig.IsSignature("foo-1.0.tar.gz.sig"))
+	assert.True(t, srcsig.IsSignature("foo-1.0.tar.gz.asc"))
+	assert.False(t, srcsig.IsSignature("foo-1.0.tar.gz"))
+	assert.Equal(t, "foo-1.0.tar.gz", srcsig.SignedFile("foo-1.0.tar.gz.asc"))
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package units_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/units"
+)
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"100":   100,
+		"1K":    1024,
+		"10M":   10 << 20,
+		"1.5G":  3 << 29,
+		"2GiB":  2 << 30,
+		"1tb":   1 << 40,
+		" 5 M ": 5 << 20,
+	} {
+		got, err := units.ParseSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "G", "-1G", "10X"} {
+		_, err := units.ParseSize(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"30d":  30 * 24 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"1.5d": 36 * time.Hour,
+	} {
+		got, err := units.ParseAge(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "d", "-1d", "forever"} {
+		_, err := units.ParseAge(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", units.FormatSize(512))
+	assert.Equal(t, "1.5 KiB", units.FormatSize(1536))
+	assert.Equal(t, "2.0 GiB", units.FormatSize(2<<30))
+}
@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package upstream finds the latest versions that upstream projects
+// published, as the update_check arrays of Staplerfiles tell.
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"go.elara.ws/vercmp"
+)
+
+// Kinds of checks.
+const (
+	KindRegex  = "regex"
+	KindGit    = "git"
+	KindGitHub = "github"
+	KindGitea  = "gitea"
+	KindPyPI   = "pypi"
+	KindCrates = "crates"
+)
+
+// The APIs that are used if a check has no URL.
+const (
+	DefaultGitHubAPI = "https://api.github.com"
+	DefaultPyPI      = "https://pypi.org"
+	DefaultCrates    = "https://crates.io"
+)
+
+// defaultTagRegex matches the tags of releases, such as v1.2.3,
+// and captures their versions.
+const defaultTagRegex = `^v?([0-9]+(?:\.[0-9]+)*)$`
+
+var ErrNoVersion = errors.New("no upstream version found")
+
+// Check is an update_check array of a Staplerfile, such as
+//
+//	update_check=(['type']='github' ['repo']='owner/name')
+//
+// The keys are quoted, so that the array is associative.
+type Check struct {
+	// Kind is the type key, one of the Kind constants.
+	Kind string
+	// URL is the page of a regex check, the repository of a git check,
+	// or the base URL of the API of other checks.
+	URL string
+	// Regex finds the versions in the page of a regex check or in the
+	// tags of git, GitHub and Gitea checks. Its first group is the
+	// version, or all of the match if it has no groups.
+	Regex string
+	// Repo is the owner/name of a GitHub or Gitea repository.
+	Repo string
+	// Project is the name of a PyPI project or crate.
+	// It is the name of the package by default.
+	Project string
+}
+
+// ParseCheck returns the check of the update_check array spec of the
+// package name.
+func ParseCheck(name string, spec map[string]string) (*Check, error) {
+	c := &Check{
+		Kind:    spec["type"],
+		URL:     spec["url"],
+		Regex:   spec["regex"],
+		Repo:    spec["repo"],
+		Project: spec["project"],
+	}
+	if c.Project == "" {
+		c.Project = name
+	}
+
+	switch c.Kind {
+	case KindRegex:
+		if c.URL == "" || c.Regex == "" {
+			return nil, fmt.Errorf("%s check needs url and regex", c.Kind)
+		}
+	case KindGit, KindGitea:
+		if c.URL == "" {
+			return nil, fmt.Errorf("%s check needs url", c.Kind)
+		}
+	case KindGitHub:
+		if c.URL == "" {
+			c.URL = DefaultGitHubAPI
+		}
+	case KindPyPI:
+		if c.URL == "" {
+			c.URL = DefaultPyPI
+		}
+	case KindCrates:
+		if c.URL == "" {
+			c.URL = DefaultCrates
+		}
+	case "":
+		return nil, errors.New("check has no type")
+	default:
+		return nil, fmt.Errorf("unknown check type %q", c.Kind)
+	}
+
+	if (c.Kind == KindGitHub || c.Kind == KindGitea) && c.Repo == "" {
+		return nil, fmt.Errorf("%s check needs repo", c.Kind)
+	}
+	if c.Regex == "" {
+		c.Regex = defaultTagRegex
+	}
+	if _, err := regexp.Compile(c.Regex); err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	return c, nil
+}
+
+type Checker struct {
+	client *http.Client
+}
+
+// NewChecker returns a checker that makes requests with client,
+// or a client with a timeout if it is nil.
+func NewChecker(client *http.Client) *Checker {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Checker{client: client}
+}
+
+// Latest returns the latest upstream version of c.
+func (ch *Checker) Latest(ctx context.Context, c *Check) (string, error) {
+	re, err := regexp.Compile(c.Regex)
+	if err != nil {
+		return "", err
+	}
+
+	switch c.Kind {
+	case KindRegex:
+		body, err := ch.get(ctx, c.URL)
+		if err != nil {
+			return "", err
+		}
+		return latest(re, re.FindAllStringSubmatch(string(body), -1))
+	case KindGit:
+		tags, err := ch.tags(ctx, c.URL)
+		if err != nil {
+			return "", err
+		}
+		var matches [][]string
+		for _, tag := range tags {
+			if m := re.FindStringSubmatch(tag); m != nil {
+				matches = append(matches, m)
+			}
+		}
+		return latest(re, matches)
+	case KindGitHub:
+		return ch.release(ctx, re, joinURL(c.URL, "repos", c.Repo, "releases/latest"))
+	case KindGitea:
+		return ch.release(ctx, re, joinURL(c.URL, "api/v1/repos", c.Repo, "releases/latest"))
+	case KindPyPI:
+		var res struct {
+			Info struct {
+				Version string `json:"version"`
+			} `json:"info"`
+		}
+		if err := ch.getJSON(ctx, joinURL(c.URL, "pypi", url.PathEscape(c.Project), "json"), &res); err != nil {
+			return "", err
+		}
+		return nonEmpty(res.Info.Version)
+	case KindCrates:
+		var res struct {
+			Crate struct {
+				MaxStableVersion string `json:"max_stable_version"`
+				MaxVersion       string `json:"max_version"`
+			} `json:"crate"`
+		}
+		if err := ch.getJSON(ctx, joinURL(c.URL, "api/v1/crates", url.PathEscape(c.Project)), &res); err != nil {
+			return "", err
+		}
+		if res.Crate.MaxStableVersion != "" {
+			return res.Crate.MaxStableVersion, nil
+		}
+		return nonEmpty(res.Crate.MaxVersion)
+	}
+	return "", fmt.Errorf("unknown check type %q", c.Kind)
+}
+
+// release returns the version in the tag of the latest release
+// of a GitHub or Gitea repository.
+func (ch *Checker) release(ctx context.Context, re *regexp.Regexp, u string) (string, error) {
+	var res struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := ch.getJSON(ctx, u, &res); err != nil {
+		return "", err
+	}
+	m := re.FindStringSubmatch(res.TagName)
+	if m == nil {
+		return "", fmt.Errorf("tag %q of the latest release does not match %q", res.TagName, re)
+	}
+	return latest(re, [][]string{m})
+}
+
+// tags lists the tags of the git repository at u like git ls-remote.
+func (ch *Checker) tags(ctx context.Context, u string) ([]string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{
+		Name: "origin",
+		URLs: []string{u},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the refs of %s: %w", u, err)
+	}
+
+	var tags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags = append(tags, ref.Name().Short())
+		}
+	}
+	return tags, nil
+}
+
+func (ch *Checker) get(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	// crates.io rejects requests without a user agent
+	req.Header.Set("User-Agent", "stplr")
+
+	res, err := ch.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", u, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+func (ch *Checker) getJSON(ctx context.Context, u string, v any) error {
+	body, err := ch.get(ctx, u)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%s: %w", u, err)
+	}
+	return nil
+}
+
+// latest returns the greatest version of the matches of re.
+func latest(re *regexp.Regexp, matches [][]string) (string, error) {
+	group := 0
+	if re.NumSubexp() > 0 {
+		group = 1
+	}
+
+	best := ""
+	for _, m := range matches {
+		if v := m[group]; v != "" && (best == "" || vercmp.Compare(v, best) > 0) {
+			best = v
+		}
+	}
+	return nonEmpty(best)
+}
+
+func nonEmpty(version string) (string, error) {
+	if version == "" {
+		return "", ErrNoVersion
+	}
+	return version, nil
+}
+
+func joinURL(base string, parts ...string) string {
+	return strings.TrimSuffix(base, "/") + "/" + strings.Join(parts, "/")
+}
+
+// IsNewer tells if the upstream version is newer than the current one.
+func IsNewer(current, upstream string) bool {
+	return vercmp.Compare(upstream, current) > 0
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package upstream_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/upstream"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/downloads/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<a href="foo-1.9.tar.gz">foo-1.9.tar.gz</a>
+<a href="foo-1.10.tar.gz">foo-1.10.tar.gz</a>
+<a href="foo-1.2.tar.gz">foo-1.2.tar.gz</a>`))
+	})
+	mux.HandleFunc("/repos/owner/foo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "v2.0.1"}`))
+	})
+	mux.HandleFunc("/api/v1/repos/owner/foo/releases/latest", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name": "3.1"}`))
+	})
+	mux.HandleFunc("/pypi/foo/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"info": {"version": "4.0.0"}}`))
+	})
+	mux.HandleFunc("/api/v1/crates/foo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"crate": {"max_version": "5.1.0-beta.1", "max_stable_version": "5.0.2"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLatest(t *testing.T) {
+	srv := newServer(t)
+
+	for _, tc := range []struct {
+		spec map[string]string
+		want string
+	}{
+		{map[string]string{"type": "regex", "url": srv.URL + "/downloads/", "regex": `foo-([0-9.]+)\.tar\.gz`}, "1.10"},
+		{map[string]string{"type": "github", "url": srv.URL, "repo": "owner/foo"}, "2.0.1"},
+		{map[string]string{"type": "gitea", "url": srv.URL, "repo": "owner/foo"}, "3.1"},
+		{map[string]string{"type": "pypi", "url": srv.URL}, "4.0.0"},
+		{map[string]string{"type": "crates", "url": srv.URL}, "5.0.2"},
+	} {
+		t.Run(tc.spec["type"], func(t *testing.T) {
+			c, err := upstream.ParseCheck("foo", tc.spec)
+			require.NoError(t, err)
+
+			v, err := upstream.NewChecker(srv.Client()).Latest(t.Context(), c)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, v)
+		})
+	}
+}
+
+func TestLatestGit(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	hash, err := wt.Commit("init", &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	for _, tag := range []string{"v0.9", "v0.10.0", "nightly", "v1.0-rc1"} {
+		_, err := repo.CreateTag(tag, hash, nil)
+		require.NoError(t, err)
+	}
+
+	c, err := upstream.ParseCheck("foo", map[string]string{"type": "git", "url": "file://" + dir})
+	require.NoError(t, err)
+
+	v, err := upstream.NewChecker(nil).Latest(t.Context(), c)
+	require.NoError(t, err)
+	assert.Equal(t, "0.10.0", v)
+}
+
+func TestLatestErrors(t *testing.T) {
+	srv := newServer(t)
+	ch := upstream.NewChecker(srv.Client())
+
+	c, err := upstream.ParseCheck("foo", map[string]string{"type": "regex", "url": srv.URL + "/downloads/", "regex": `bar-([0-9.]+)`})
+	require.NoError(t, err)
+	_, err = ch.Latest(t.Context(), c)
+	assert.ErrorIs(t, err, upstream.ErrNoVersion)
+
+	c, err = upstream.ParseCheck("bar", map[string]string{"type": "pypi", "url": srv.URL})
+	require.NoError(t, err)
+	_, err = ch.Latest(t.Context(), c)
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestParseCheck(t *testing.T) {
+	c, err := upstream.ParseCheck("foo", map[string]string{"type": "crates"})
+	require.NoError(t, err)
+	assert.Equal(t, upstream.DefaultCrates, c.URL)
+	assert.Equal(t, "foo", c.Project)
+
+	for _, spec := range []map[string]string{
+		{},
+		{"type": "svn"},
+		{"type": "regex", "url": "https://example.com"},
+		{"type": "git"},
+		{"type": "github"},
+		{"type": "git", "url": "https://example.com/foo.git", "regex": "("},
+	} {
+		_, err := upstream.ParseCheck("foo", spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	assert.True(t, upstream.IsNewer("1.9", "1.10"))
+	assert.False(t, upstream.IsNewer("1.10", "1.10"))
+	assert.False(t, upstream.IsNewer("2.0", "1.10"))
+}
@@ -20,10 +20,16 @@ package build
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 
 	stdErrors "errors"
 
@@ -36,6 +42,7 @@ import (
 	"go.stplr.dev/stplr/internal/cliutils"
 	"go.stplr.dev/stplr/internal/commonbuild"
 	"go.stplr.dev/stplr/internal/config"
+	"go.stplr.dev/stplr/internal/config/common"
 	"go.stplr.dev/stplr/internal/copier"
 	"go.stplr.dev/stplr/internal/manager"
 	"go.stplr.dev/stplr/pkg/distro"
@@ -51,6 +58,12 @@ type useCase struct {
 	manager manager.Manager
 	finder  build.PackageFinder
 	fsys    afero.Fs
+	stdout  io.Writer
+
+	// format is the package format to build and cross is set if
+	// it or info differ from the host ones.
+	format string
+	cross  bool
 
 	cleanups []func()
 }
@@ -73,6 +86,7 @@ func New(o ConstructOptions) *useCase {
 		manager: o.Manager,
 		finder:  o.Finder,
 		fsys:    afero.NewOsFs(),
+		stdout:  os.Stdout,
 	}
 }
 
@@ -82,6 +96,27 @@ type RunOptions struct {
 	Clean       bool
 	Interactive bool
 	NoSuffix    bool
+	Jobs        int
+	NoCheck     bool
+	DryRun      bool
+	Json        bool
+	// Reproducible enables reproducible package output.
+	Reproducible bool
+	// VerifyReproducible builds the package twice and fails
+	// if the results differ.
+	VerifyReproducible bool
+	// SBOM writes an SPDX document and a provenance statement
+	// next to every package.
+	SBOM bool
+	// CleanRoot builds in a rootfs unpacked from the configured base
+	// image. BaseImage overrides the configured image and implies
+	// CleanRoot.
+	CleanRoot bool
+	BaseImage string
+	// TargetDistro is an os-release file or id:version and TargetFormat
+	// a package format to build for instead of the host ones.
+	TargetDistro string
+	TargetFormat string
 
 	Script  string
 	Package string
@@ -92,6 +127,46 @@ func (u *useCase) Run(ctx context.Context, o RunOptions) error {
 		return err
 	}
 
+	image, err := u.cleanRootImage(o)
+	if err != nil {
+		return err
+	}
+	o.BaseImage = image
+
+	if err := u.resolveTarget(ctx, o); err != nil {
+		return err
+	}
+
+	var pkgs []*commonbuild.BuiltDep
+	if o.VerifyReproducible && !o.DryRun {
+		pkgs, err = u.verifyReproducible(ctx, o)
+	} else {
+		pkgs, err = u.build(ctx, o)
+	}
+	if err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		if err := u.builder.Plan().Write(u.stdout, o.Json); err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error writing the plan"))
+		}
+		return nil
+	}
+
+	builtPkgs := make([]commonbuild.BuiltDep, len(pkgs))
+	for i, pkg := range pkgs {
+		builtPkgs[i] = commonbuild.BuiltDep{Name: pkg.Name, Path: pkg.Path}
+	}
+
+	if err := u.copier.CopyOut(ctx, builtPkgs); err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error moving the package"))
+	}
+
+	return nil
+}
+
+func (u *useCase) build(ctx context.Context, o RunOptions) ([]*commonbuild.BuiltDep, error) {
 	var err error
 	var pkgs []*commonbuild.BuiltDep
 
@@ -101,7 +176,7 @@ func (u *useCase) Run(ctx context.Context, o RunOptions) error {
 	case o.Script != "":
 		pkgs, err = u.runForScript(ctx, o)
 	default:
-		return fmt.Errorf("either Script or Package must be specified")
+		return nil, fmt.Errorf("either Script or Package must be specified")
 	}
 	var ctxErr *build.BuildContextError
 	if stdErrors.As(err, &ctxErr) {
@@ -109,21 +184,140 @@ func (u *useCase) Run(ctx context.Context, o RunOptions) error {
 			"Error when building the package. Report the issue here: %s\nError trace",
 			ctxErr.ReportUrl,
 		)
-		return errors.WrapIntoI18nError(ctxErr.Unwrap(), msg)
+		return nil, errors.WrapIntoI18nError(ctxErr.Unwrap(), msg)
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	builtPkgs := make([]commonbuild.BuiltDep, len(pkgs))
-	for i, pkg := range pkgs {
-		builtPkgs[i] = commonbuild.BuiltDep{Name: pkg.Name, Path: pkg.Path}
+	return pkgs, nil
+}
+
+// verifyReproducible builds the package twice from scratch and compares
+// the checksums of the results. The packages of the second build are
+// returned if all of them are identical.
+func (u *useCase) verifyReproducible(ctx context.Context, o RunOptions) ([]*commonbuild.BuiltDep, error) {
+	o.Clean = true
+	o.Reproducible = true
+
+	first, err := u.build(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	// The second build removes the files of the first one.
+	firstSums, err := u.checksums(first)
+	if err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error calculating package checksums"))
 	}
 
-	if err := u.copier.CopyOut(ctx, builtPkgs); err != nil {
-		return errors.WrapIntoI18nError(err, gotext.Get("Error moving the package"))
+	second, err := u.build(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+	secondSums, err := u.checksums(second)
+	if err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error calculating package checksums"))
+	}
+
+	var differ []string
+	for _, pkg := range second {
+		name := filepath.Base(pkg.Path)
+		if firstSums[name] == secondSums[name] {
+			fmt.Fprintf(u.stdout, "%s %s\n", secondSums[name], name)
+			continue
+		}
+		fmt.Fprintf(u.stdout, "%s %s\n", gotext.Get("differs:"), name)
+		fmt.Fprintf(u.stdout, "  %s %s\n", gotext.Get("first build: "), firstSums[name])
+		fmt.Fprintf(u.stdout, "  %s %s\n", gotext.Get("second build:"), secondSums[name])
+		differ = append(differ, name)
+	}
+
+	if len(differ) > 0 {
+		return nil, errors.NewI18nError(gotext.Get("Build is not reproducible: %s", strings.Join(differ, ", ")))
+	}
+
+	return second, nil
+}
+
+// checksums returns the sha256 sums of pkgs keyed by file name.
+func (u *useCase) checksums(pkgs []*commonbuild.BuiltDep) (map[string]string, error) {
+	sums := make(map[string]string, len(pkgs))
+	for _, pkg := range pkgs {
+		f, err := u.fsys.Open(pkg.Path)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+		sums[filepath.Base(pkg.Path)] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// cleanRootImage returns the absolute path of the base image
+// or "" if the build doesn't use a clean root.
+func (u *useCase) cleanRootImage(o RunOptions) (string, error) {
+	if !o.CleanRoot && o.BaseImage == "" {
+		return "", nil
 	}
 
+	image := o.BaseImage
+	if image == "" {
+		image = u.config.CleanRootImage()
+	}
+	if image == "" {
+		return "", errors.NewI18nError(gotext.Get("No base image for the clean root, set %q in the config or use --base-image", common.CLEAN_ROOT_IMAGE))
+	}
+
+	// the image is read by the builder, which runs in another directory
+	abs, err := filepath.Abs(image)
+	if err != nil {
+		return "", err
+	}
+	if _, err := u.fsys.Stat(abs); err != nil {
+		return "", errors.WrapIntoI18nError(err, gotext.Get("Error reading the base image"))
+	}
+
+	return abs, nil
+}
+
+// resolveTarget replaces the host release and package format with the
+// ones given in o. The release drives override resolution and package
+// metadata, so it is set before the script is read.
+func (u *useCase) resolveTarget(ctx context.Context, o RunOptions) error {
+	u.format = build.GetPkgFormat(u.manager)
+	if o.TargetDistro == "" && o.TargetFormat == "" {
+		return nil
+	}
+
+	info := u.info
+	if o.TargetDistro != "" {
+		var err error
+		info, err = distro.ParseTarget(ctx, o.TargetDistro)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error parsing the target distro"))
+		}
+	}
+
+	format := o.TargetFormat
+	if format == "" {
+		format = build.FormatForDistro(info)
+	}
+	if format == "" {
+		return errors.NewI18nError(gotext.Get("Unknown package format for %q, use --target-format", info.ID))
+	}
+	if !slices.Contains(manager.SupportedPackageFormats(), format) {
+		return errors.NewI18nError(gotext.Get("Unsupported package format %q, supported formats: %s", format, strings.Join(manager.SupportedPackageFormats(), ", ")))
+	}
+
+	u.cross = format != u.format || !reflect.DeepEqual(info, u.info)
+	u.info = info
+	u.format = format
+
 	return nil
 }
 
@@ -161,11 +355,18 @@ func (u *useCase) runForDb(ctx context.Context, o RunOptions) ([]*commonbuild.Bu
 			Packages: packages,
 			BuildArgs: build.BuildArgs{
 				Opts: &types.BuildOpts{
-					Clean:       o.Clean,
-					Interactive: o.Interactive,
-					NoSuffix:    o.NoSuffix,
+					Clean:          o.Clean,
+					Interactive:    o.Interactive,
+					NoSuffix:       o.NoSuffix,
+					Jobs:           o.Jobs,
+					NoCheck:        o.NoCheck,
+					DryRun:         o.DryRun,
+					Reproducible:   o.Reproducible,
+					SBOM:           o.SBOM,
+					CleanRootImage: o.BaseImage,
+					CrossTarget:    u.cross,
 				},
-				PkgFormat_: build.GetPkgFormat(u.manager),
+				PkgFormat_: u.format,
 				Info:       u.info,
 			},
 		},
@@ -195,18 +396,31 @@ func (u *useCase) runForScript(ctx context.Context, o RunOptions) ([]*commonbuil
 		packages = []string{o.Subpackage}
 	}
 
+	sourceURL, sourceCommit := build.ScriptSource(o.Script)
+
 	return u.builder.BuildPackageFromScript(
 		ctx,
 		&build.BuildPackageFromScriptArgs{
 			Script:   script,
 			Packages: packages,
+			// the script was copied, so take the time from the original
+			SourceDateEpoch: build.SourceDateEpoch(o.Script),
+			SourceURL:       sourceURL,
+			SourceCommit:    sourceCommit,
 			BuildArgs: build.BuildArgs{
 				Opts: &types.BuildOpts{
-					Clean:       o.Clean,
-					Interactive: o.Interactive,
-					NoSuffix:    o.NoSuffix,
+					Clean:          o.Clean,
+					Interactive:    o.Interactive,
+					NoSuffix:       o.NoSuffix,
+					Jobs:           o.Jobs,
+					NoCheck:        o.NoCheck,
+					DryRun:         o.DryRun,
+					Reproducible:   o.Reproducible,
+					SBOM:           o.SBOM,
+					CleanRootImage: o.BaseImage,
+					CrossTarget:    u.cross,
 				},
-				PkgFormat_: build.GetPkgFormat(u.manager),
+				PkgFormat_: u.format,
 				Info:       u.info,
 			},
 		},
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/cachegc"
+	"go.stplr.dev/stplr/internal/units"
+)
+
+type Cache interface {
+	List(ctx context.Context) ([]cachegc.Entry, error)
+	Remove(ctx context.Context, entries []cachegc.Entry) error
+}
+
+type useCase struct {
+	cache  Cache
+	out    output.Output
+	stdout io.Writer
+	now    func() time.Time
+}
+
+func New(cache Cache, out output.Output) *useCase {
+	return &useCase{
+		cache:  cache,
+		out:    out,
+		stdout: os.Stdout,
+		now:    time.Now,
+	}
+}
+
+func (u *useCase) entries(ctx context.Context) ([]cachegc.Entry, error) {
+	entries, err := u.cache.List(ctx)
+	if err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error reading the caches"))
+	}
+	return entries, nil
+}
+
+// List prints every entry of the caches.
+func (u *useCase) List(ctx context.Context) error {
+	entries, err := u.entries(ctx)
+	if err != nil {
+		return err
+	}
+	u.print(entries)
+	return nil
+}
+
+func (u *useCase) print(entries []cachegc.Entry) {
+	w := tabwriter.NewWriter(u.stdout, 0, 0, 2, ' ', 0)
+	for _, e := range entries {
+		name := e.Name()
+		if e.Orphan {
+			name += " " + gotext.Get("(removed repo)")
+		}
+		version := e.Version
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\n",
+			kindName(e.Kind),
+			name,
+			version,
+			units.FormatSize(e.Size),
+			e.ModTime.Local().Format(time.DateTime),
+		)
+	}
+	_ = w.Flush()
+}
+
+// Du prints the size of every cache.
+func (u *useCase) Du(ctx context.Context) error {
+	entries, err := u.entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	byKind := make(map[cachegc.Kind][]cachegc.Entry)
+	for _, e := range entries {
+		byKind[e.Kind] = append(byKind[e.Kind], e)
+	}
+
+	w := tabwriter.NewWriter(u.stdout, 0, 0, 2, ' ', 0)
+	for _, kind := range []cachegc.Kind{cachegc.KindPackage, cachegc.KindBuildDir, cachegc.KindSource, cachegc.KindRepo, cachegc.KindCompilerCache} {
+		fmt.Fprintf(w, "%s\t%s\n", kindTitle(kind), units.FormatSize(cachegc.Total(byKind[kind])))
+	}
+	fmt.Fprintf(w, "%s\t%s\n", gotext.Get("Total"), units.FormatSize(cachegc.Total(entries)))
+	return w.Flush()
+}
+
+type PruneOptions struct {
+	OlderThan string
+	KeepLast  int
+	MaxSize   string
+	DryRun    bool
+}
+
+func (o PruneOptions) policy() (cachegc.Policy, error) {
+	p := cachegc.Policy{KeepLast: o.KeepLast}
+	var err error
+	if o.OlderThan != "" {
+		if p.OlderThan, err = units.ParseAge(o.OlderThan); err != nil {
+			return p, errors.WrapIntoI18nError(err, gotext.Get("Invalid --older-than value"))
+		}
+	}
+	if o.MaxSize != "" {
+		if p.MaxSize, err = units.ParseSize(o.MaxSize); err != nil {
+			return p, errors.WrapIntoI18nError(err, gotext.Get("Invalid --max-size value"))
+		}
+	}
+	if p.KeepLast < 0 {
+		return p, errors.NewI18nError(gotext.Get("--keep-last must not be negative"))
+	}
+	return p, nil
+}
+
+// Prune removes the entries selected by opts. The clones of
+// removed repos are always pruned.
+func (u *useCase) Prune(ctx context.Context, opts PruneOptions) error {
+	policy, err := opts.policy()
+	if err != nil {
+		return err
+	}
+
+	entries, err := u.entries(ctx)
+	if err != nil {
+		return err
+	}
+
+	pruned := cachegc.Select(entries, policy, u.now(), nil)
+	if len(pruned) == 0 {
+		u.out.Info(gotext.Get("Nothing to prune"))
+		return nil
+	}
+
+	u.print(pruned)
+	size := units.FormatSize(cachegc.Total(pruned))
+	if opts.DryRun {
+		u.out.Info(gotext.Get("Would prune %d cache entries, freeing %s", len(pruned), size))
+		return nil
+	}
+
+	if err := u.cache.Remove(ctx, pruned); err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error pruning the caches"))
+	}
+	u.out.Info(gotext.Get("Pruned %d cache entries, freed %s", len(pruned), size))
+	return nil
+}
+
+func kindName(kind cachegc.Kind) string {
+	switch kind {
+	case cachegc.KindPackage:
+		return gotext.Get("package")
+	case cachegc.KindBuildDir:
+		return gotext.Get("build")
+	case cachegc.KindSource:
+		return gotext.Get("source")
+	case cachegc.KindRepo:
+		return gotext.Get("repo")
+	case cachegc.KindCompilerCache:
+		return gotext.Get("compiler cache")
+	}
+	return string(kind)
+}
+
+func kindTitle(kind cachegc.Kind) string {
+	switch kind {
+	case cachegc.KindPackage:
+		return gotext.Get("Built packages")
+	case cachegc.KindBuildDir:
+		return gotext.Get("Build directories")
+	case cachegc.KindSource:
+		return gotext.Get("Sources")
+	case cachegc.KindRepo:
+		return gotext.Get("Repo clones")
+	case cachegc.KindCompilerCache:
+		return gotext.Get("Compiler caches")
+	}
+	return string(kind)
+}
@@ -0,0 +1,352 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package checksums
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/scriptedit"
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/dl"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+type Options struct {
+	Script string
+	// Update writes the checksums into the script
+	// instead of printing them.
+	Update    bool
+	Algorithm string
+}
+
+type useCase struct {
+	info   *distro.OSRelease
+	out    output.Output
+	stdout io.Writer
+}
+
+func New(info *distro.OSRelease, out output.Output) *useCase {
+	return &useCase{
+		info:   info,
+		out:    out,
+		stdout: os.Stdout,
+	}
+}
+
+// site is an assignment of checksums that is updated,
+// or one that is added after the sources it is for.
+type site struct {
+	scope  string
+	name   string
+	values []string
+	pkg    string
+
+	stmt   *syntax.Stmt
+	assign *syntax.Assign
+	like   *syntax.Assign
+}
+
+// Run computes the checksums of the sources of every package of the
+// script, for every override of the sources.
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	if opts.Algorithm == "" {
+		opts.Algorithm = "sha256"
+	}
+	if _, err := (dl.Options{HashAlgorithm: opts.Algorithm}).NewHash(); err != nil {
+		return errors.NewI18nError(gotext.Get("Unknown hash algorithm %q", opts.Algorithm))
+	}
+
+	src, err := os.ReadFile(opts.Script)
+	if err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error reading the script"))
+	}
+
+	sf, err := staplerfile.ReadFromIOReader(bytes.NewReader(src), opts.Script)
+	if err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error parsing the script"))
+	}
+
+	_, pkgs, err := sf.ParseBuildVars(ctx, u.info, nil)
+	if err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error parsing the script"))
+	}
+
+	tmp, err := os.MkdirTemp("", "stplr-checksums-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	h := &hasher{
+		out:       u.out,
+		dir:       tmp,
+		localDir:  filepath.Dir(opts.Script),
+		algorithm: opts.Algorithm,
+		sums:      map[string]string{},
+	}
+
+	sites, err := u.sites(ctx, sf.File(), pkgs, h)
+	if err != nil {
+		return err
+	}
+
+	if !opts.Update {
+		u.print(sites)
+		return nil
+	}
+
+	e := scriptedit.NewEditor(src)
+	for _, s := range sites {
+		if s.assign != nil {
+			if err := e.SetArray(s.assign, s.values); err != nil {
+				return errors.WrapIntoI18nError(err, gotext.Get("Error updating the checksums"))
+			}
+		} else {
+			e.AddArrayAfter(s.stmt, s.like, s.name, s.values)
+		}
+	}
+
+	fi, err := os.Stat(opts.Script)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(opts.Script, e.Bytes(), fi.Mode().Perm()); err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error writing the script"))
+	}
+
+	u.out.Info("%s", gotext.Get("Updated the checksums of %s", opts.Script))
+	return nil
+}
+
+// sites finds where the checksums of every package go. A package of a
+// multi-package script has them in its meta function if it overrides
+// them there and in the top level of the script otherwise. Missing
+// checksums are added next to their sources.
+func (u *useCase) sites(ctx context.Context, f *syntax.File, pkgs []*staplerfile.Package, h *hasher) ([]*site, error) {
+	var sites []*site
+	byName := map[string]*site{}
+
+	for _, pkg := range pkgs {
+		var scopes []string
+		if pkg.BasePkgName != "" {
+			scopes = append(scopes, "meta_"+pkg.Name)
+		}
+		scopes = append(scopes, "")
+
+		sources := pkg.Sources.All()
+		existing := pkg.Checksums.All()
+
+		for _, key := range sortedKeys(sources) {
+			name, sourcesName := overrideName("checksums", key), overrideName("sources", key)
+
+			values, err := h.checksums(ctx, sources[key], existing[key])
+			if err != nil {
+				return nil, err
+			}
+
+			s, err := findSite(f, scopes, name, sourcesName)
+			if err != nil {
+				return nil, errors.WrapIntoI18nError(err, gotext.Get("Error finding the checksums of %s", pkg.Name))
+			}
+			if s == nil {
+				u.out.Warn("%s", gotext.Get("Skipping %s of %s: %s is not assigned in the script", name, pkg.Name, sourcesName))
+				continue
+			}
+			s.values, s.pkg = values, pkg.Name
+
+			id := s.scope + "\x00" + s.name
+			if prev, ok := byName[id]; ok {
+				if !slices.Equal(prev.values, s.values) {
+					return nil, errors.NewI18nError(gotext.Get(
+						"Packages %s and %s share %s but need different checksums, move them into their meta functions",
+						prev.pkg, s.pkg, name,
+					))
+				}
+				continue
+			}
+			byName[id] = s
+			sites = append(sites, s)
+		}
+	}
+
+	return sites, nil
+}
+
+func findSite(f *syntax.File, scopes []string, name, sourcesName string) (*site, error) {
+	for _, scope := range scopes {
+		stmt, assign, err := scriptedit.Find(f, scope, name)
+		if err != nil {
+			return nil, err
+		}
+		if assign != nil {
+			return &site{scope: scope, name: name, stmt: stmt, assign: assign}, nil
+		}
+	}
+
+	for _, scope := range scopes {
+		stmt, like, err := scriptedit.Find(f, scope, sourcesName)
+		if err != nil {
+			return nil, err
+		}
+		if like != nil {
+			return &site{scope: scope, name: name, stmt: stmt, like: like}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (u *useCase) print(sites []*site) {
+	for _, s := range sites {
+		if s.scope != "" {
+			fmt.Fprintf(u.stdout, "# %s\n", s.scope)
+		}
+		fmt.Fprintf(u.stdout, "%s=(%s)\n", s.name, strings.Join(quote(s.values), " "))
+	}
+}
+
+func quote(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = "'" + v + "'"
+	}
+	return out
+}
+
+func overrideName(name, key string) string {
+	if key == "" {
+		return name
+	}
+	return name + "_" + key
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// hasher downloads sources and computes their checksums.
+type hasher struct {
+	out       output.Output
+	dir       string
+	localDir  string
+	algorithm string
+	// sums are the checksums of the sources already downloaded
+	sums map[string]string
+}
+
+// checksums returns the checksums of sources. Sources that have the
+// checksum SKIP in existing keep it.
+func (h *hasher) checksums(ctx context.Context, sources, existing []string) ([]string, error) {
+	values := make([]string, len(sources))
+	for i, src := range sources {
+		if i < len(existing) && strings.EqualFold(existing[i], "SKIP") {
+			values[i] = existing[i]
+			continue
+		}
+
+		sum, err := h.checksum(ctx, src)
+		if err != nil {
+			return nil, errors.WrapIntoI18nError(err, gotext.Get("Error downloading %s", src))
+		}
+		values[i] = sum
+	}
+	return values, nil
+}
+
+func (h *hasher) checksum(ctx context.Context, src string) (string, error) {
+	if sum, ok := h.sums[src]; ok {
+		return sum, nil
+	}
+
+	dest, err := os.MkdirTemp(h.dir, "src-")
+	if err != nil {
+		return "", err
+	}
+
+	opts := dl.Options{
+		Name:             src,
+		URL:              src,
+		HashAlgorithm:    h.algorithm,
+		Destination:      dest,
+		LocalDir:         h.localDir,
+		CacheDisabled:    true,
+		PostprocDisabled: true,
+		Progress:         os.Stderr,
+		Output:           h.out,
+	}
+
+	res, err := dl.Download(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	sum := "SKIP"
+	// VCS checkouts have no checksum
+	if res.Type == dl.TypeFile {
+		sum, err = hashFile(opts, filepath.Join(dest, res.Name))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	h.sums[src] = sum
+	return sum, nil
+}
+
+func hashFile(opts dl.Options, path string) (string, error) {
+	hash, err := opts.NewHash()
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	// SHA-256 is the default algorithm of checksums
+	if opts.HashAlgorithm == "sha256" {
+		return sum, nil
+	}
+	return opts.HashAlgorithm + ":" + sum, nil
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package checksums
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/pkg/distro"
+)
+
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("world"), 0o644))
+
+	path := filepath.Join(dir, "Staplerfile")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o644))
+	return path
+}
+
+func TestRunUpdate(t *testing.T) {
+	path := writeScript(t, `name=foo
+version=1.0
+release=1
+
+sources=(
+	'local:///a.txt'
+	'local:///b.txt'
+)
+checksums=(
+	'SKIP'
+	'0000'
+)
+sources_amd64=("local:///b.txt")
+`)
+
+	u := New(&distro.OSRelease{ID: "fedora"}, output.NewConsoleOutput())
+	require.NoError(t, u.Run(t.Context(), Options{Script: path, Update: true}))
+
+	b := sha256.Sum256([]byte("world"))
+	sum := hex.EncodeToString(b[:])
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `name=foo
+version=1.0
+release=1
+
+sources=(
+	'local:///a.txt'
+	'local:///b.txt'
+)
+checksums=(
+	'SKIP'
+	'`+sum+`'
+)
+sources_amd64=("local:///b.txt")
+checksums_amd64=("`+sum+`")
+`, string(data))
+}
+
+func TestRunPrint(t *testing.T) {
+	path := writeScript(t, `name=foo
+version=1.0
+release=1
+sources=('local:///a.txt')
+`)
+
+	var stdout bytes.Buffer
+	u := New(&distro.OSRelease{ID: "fedora"}, output.NewConsoleOutput())
+	u.stdout = &stdout
+	require.NoError(t, u.Run(t.Context(), Options{Script: path, Algorithm: "sha512"}))
+
+	a := sha512.Sum512([]byte("hello"))
+	assert.Equal(t, "checksums=('sha512:"+hex.EncodeToString(a[:])+"')\n", stdout.String())
+
+	assert.Error(t, u.Run(t.Context(), Options{Script: path, Algorithm: "crc32"}))
+}
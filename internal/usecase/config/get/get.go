@@ -53,6 +53,24 @@ type ConfigGetter interface {
 	HideFirejailExcludeWarning() bool
 	ForbidSkipInChecksums() bool
 	ForbidBuildCommand() bool
+	NoCheck() bool
+	Reproducible() bool
+	SBOM() bool
+	CleanRootImage() string
+	CacheMaxSize() string
+	CacheMaxAge() string
+	PackageOptions() []string
+	DebugInfo() bool
+	BuildMemoryMax() string
+	BuildCPUMax() string
+	BuildPidsMax() string
+	BuildIOWeight() string
+	BuildTimeout() string
+	SandboxSeccompProfile() string
+	SandboxCapabilities() []string
+	BuildCache() bool
+	SandboxBinds() []string
+	SandboxEnvPassthrough() []string
 	GetPaths() *config.Paths
 }
 
@@ -66,9 +84,18 @@ func New(cfg ConfigGetter, out output.Output) *useCase {
 
 func (u *useCase) Run(ctx context.Context, key string) error {
 	stringGetters := map[string]func() string{
-		common.ROOT_CMD:    u.cfg.RootCmd,
-		common.PAGER_STYLE: u.cfg.PagerStyle,
-		common.LOG_LEVEL:   u.cfg.LogLevel,
+		common.ROOT_CMD:                u.cfg.RootCmd,
+		common.PAGER_STYLE:             u.cfg.PagerStyle,
+		common.LOG_LEVEL:               u.cfg.LogLevel,
+		common.CLEAN_ROOT_IMAGE:        u.cfg.CleanRootImage,
+		common.CACHE_MAX_SIZE:          u.cfg.CacheMaxSize,
+		common.CACHE_MAX_AGE:           u.cfg.CacheMaxAge,
+		common.BUILD_MEMORY_MAX:        u.cfg.BuildMemoryMax,
+		common.BUILD_CPU_MAX:           u.cfg.BuildCPUMax,
+		common.BUILD_PIDS_MAX:          u.cfg.BuildPidsMax,
+		common.BUILD_IO_WEIGHT:         u.cfg.BuildIOWeight,
+		common.BUILD_TIMEOUT:           u.cfg.BuildTimeout,
+		common.SANDBOX_SECCOMP_PROFILE: u.cfg.SandboxSeccompProfile,
 	}
 
 	boolGetters := map[string]func() bool{
@@ -77,11 +104,20 @@ func (u *useCase) Run(ctx context.Context, key string) error {
 		common.FORBID_SKIP_IN_CHECKSUMS:      u.cfg.ForbidSkipInChecksums,
 		common.FORBID_BUILD_COMMAND:          u.cfg.ForbidBuildCommand,
 		common.HIDE_FIREJAIL_EXCLUDE_WARNING: u.cfg.HideFirejailExcludeWarning,
+		common.NO_CHECK:                      u.cfg.NoCheck,
+		common.REPRODUCIBLE:                  u.cfg.Reproducible,
+		common.SBOM:                          u.cfg.SBOM,
+		common.DEBUG_INFO:                    u.cfg.DebugInfo,
+		common.BUILD_CACHE:                   u.cfg.BuildCache,
 	}
 
 	listGetters := map[string]func() []string{
-		common.IGNORE_PKG_UPDATES: u.cfg.IgnorePkgUpdates,
-		common.FIREJAIL_EXCLUDE:   u.cfg.FirejailExclude,
+		common.IGNORE_PKG_UPDATES:      u.cfg.IgnorePkgUpdates,
+		common.FIREJAIL_EXCLUDE:        u.cfg.FirejailExclude,
+		common.PACKAGE_OPTIONS:         u.cfg.PackageOptions,
+		common.SANDBOX_CAPABILITIES:    u.cfg.SandboxCapabilities,
+		common.SANDBOX_BINDS:           u.cfg.SandboxBinds,
+		common.SANDBOX_ENV_PASSTHROUGH: u.cfg.SandboxEnvPassthrough,
 	}
 
 	if key == common.PAGER_STYLE {
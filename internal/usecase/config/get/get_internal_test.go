@@ -45,7 +45,25 @@ func TestAllAllowedKeysHandled(t *testing.T) {
 	mockConfig.EXPECT().ForbidBuildCommand().Return(true)
 	mockConfig.EXPECT().ForbidSkipInChecksums().Return(true)
 	mockConfig.EXPECT().HideFirejailExcludeWarning().Return(true)
+	mockConfig.EXPECT().NoCheck().Return(true)
+	mockConfig.EXPECT().Reproducible().Return(true)
+	mockConfig.EXPECT().SBOM().Return(true)
+	mockConfig.EXPECT().CleanRootImage().Return("1")
+	mockConfig.EXPECT().CacheMaxSize().Return("1")
+	mockConfig.EXPECT().CacheMaxAge().Return("1")
 	mockConfig.EXPECT().FirejailExclude().Return([]string{})
+	mockConfig.EXPECT().PackageOptions().Return([]string{})
+	mockConfig.EXPECT().DebugInfo().Return(true)
+	mockConfig.EXPECT().BuildMemoryMax().Return("1")
+	mockConfig.EXPECT().BuildCPUMax().Return("1")
+	mockConfig.EXPECT().BuildPidsMax().Return("1")
+	mockConfig.EXPECT().BuildIOWeight().Return("1")
+	mockConfig.EXPECT().BuildTimeout().Return("1")
+	mockConfig.EXPECT().SandboxSeccompProfile().Return("1")
+	mockConfig.EXPECT().SandboxCapabilities().Return([]string{})
+	mockConfig.EXPECT().BuildCache().Return(true)
+	mockConfig.EXPECT().SandboxBinds().Return([]string{})
+	mockConfig.EXPECT().SandboxEnvPassthrough().Return([]string{})
 
 	for _, key := range config.AllowedKeys() {
 		useCase := New(mockConfig, output.NewConsoleOutput())
@@ -55,6 +55,146 @@ func (mr *MockConfigGetterMockRecorder) AutoPull() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutoPull", reflect.TypeOf((*MockConfigGetter)(nil).AutoPull))
 }
 
+// BuildCPUMax mocks base method.
+func (m *MockConfigGetter) BuildCPUMax() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildCPUMax")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildCPUMax indicates an expected call of BuildCPUMax.
+func (mr *MockConfigGetterMockRecorder) BuildCPUMax() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildCPUMax", reflect.TypeOf((*MockConfigGetter)(nil).BuildCPUMax))
+}
+
+// BuildCache mocks base method.
+func (m *MockConfigGetter) BuildCache() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildCache")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// BuildCache indicates an expected call of BuildCache.
+func (mr *MockConfigGetterMockRecorder) BuildCache() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildCache", reflect.TypeOf((*MockConfigGetter)(nil).BuildCache))
+}
+
+// BuildIOWeight mocks base method.
+func (m *MockConfigGetter) BuildIOWeight() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildIOWeight")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildIOWeight indicates an expected call of BuildIOWeight.
+func (mr *MockConfigGetterMockRecorder) BuildIOWeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildIOWeight", reflect.TypeOf((*MockConfigGetter)(nil).BuildIOWeight))
+}
+
+// BuildMemoryMax mocks base method.
+func (m *MockConfigGetter) BuildMemoryMax() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildMemoryMax")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildMemoryMax indicates an expected call of BuildMemoryMax.
+func (mr *MockConfigGetterMockRecorder) BuildMemoryMax() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildMemoryMax", reflect.TypeOf((*MockConfigGetter)(nil).BuildMemoryMax))
+}
+
+// BuildPidsMax mocks base method.
+func (m *MockConfigGetter) BuildPidsMax() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildPidsMax")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildPidsMax indicates an expected call of BuildPidsMax.
+func (mr *MockConfigGetterMockRecorder) BuildPidsMax() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildPidsMax", reflect.TypeOf((*MockConfigGetter)(nil).BuildPidsMax))
+}
+
+// BuildTimeout mocks base method.
+func (m *MockConfigGetter) BuildTimeout() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildTimeout")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// BuildTimeout indicates an expected call of BuildTimeout.
+func (mr *MockConfigGetterMockRecorder) BuildTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildTimeout", reflect.TypeOf((*MockConfigGetter)(nil).BuildTimeout))
+}
+
+// CacheMaxAge mocks base method.
+func (m *MockConfigGetter) CacheMaxAge() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheMaxAge")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CacheMaxAge indicates an expected call of CacheMaxAge.
+func (mr *MockConfigGetterMockRecorder) CacheMaxAge() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheMaxAge", reflect.TypeOf((*MockConfigGetter)(nil).CacheMaxAge))
+}
+
+// CacheMaxSize mocks base method.
+func (m *MockConfigGetter) CacheMaxSize() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CacheMaxSize")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CacheMaxSize indicates an expected call of CacheMaxSize.
+func (mr *MockConfigGetterMockRecorder) CacheMaxSize() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheMaxSize", reflect.TypeOf((*MockConfigGetter)(nil).CacheMaxSize))
+}
+
+// CleanRootImage mocks base method.
+func (m *MockConfigGetter) CleanRootImage() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CleanRootImage")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// CleanRootImage indicates an expected call of CleanRootImage.
+func (mr *MockConfigGetterMockRecorder) CleanRootImage() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CleanRootImage", reflect.TypeOf((*MockConfigGetter)(nil).CleanRootImage))
+}
+
+// DebugInfo mocks base method.
+func (m *MockConfigGetter) DebugInfo() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DebugInfo")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// DebugInfo indicates an expected call of DebugInfo.
+func (mr *MockConfigGetterMockRecorder) DebugInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DebugInfo", reflect.TypeOf((*MockConfigGetter)(nil).DebugInfo))
+}
+
 // FirejailExclude mocks base method.
 func (m *MockConfigGetter) FirejailExclude() []string {
 	m.ctrl.T.Helper()
@@ -153,6 +293,34 @@ func (mr *MockConfigGetterMockRecorder) LogLevel() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogLevel", reflect.TypeOf((*MockConfigGetter)(nil).LogLevel))
 }
 
+// NoCheck mocks base method.
+func (m *MockConfigGetter) NoCheck() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NoCheck")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NoCheck indicates an expected call of NoCheck.
+func (mr *MockConfigGetterMockRecorder) NoCheck() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NoCheck", reflect.TypeOf((*MockConfigGetter)(nil).NoCheck))
+}
+
+// PackageOptions mocks base method.
+func (m *MockConfigGetter) PackageOptions() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PackageOptions")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// PackageOptions indicates an expected call of PackageOptions.
+func (mr *MockConfigGetterMockRecorder) PackageOptions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PackageOptions", reflect.TypeOf((*MockConfigGetter)(nil).PackageOptions))
+}
+
 // PagerStyle mocks base method.
 func (m *MockConfigGetter) PagerStyle() string {
 	m.ctrl.T.Helper()
@@ -181,6 +349,20 @@ func (mr *MockConfigGetterMockRecorder) Repos() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Repos", reflect.TypeOf((*MockConfigGetter)(nil).Repos))
 }
 
+// Reproducible mocks base method.
+func (m *MockConfigGetter) Reproducible() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reproducible")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Reproducible indicates an expected call of Reproducible.
+func (mr *MockConfigGetterMockRecorder) Reproducible() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reproducible", reflect.TypeOf((*MockConfigGetter)(nil).Reproducible))
+}
+
 // RootCmd mocks base method.
 func (m *MockConfigGetter) RootCmd() string {
 	m.ctrl.T.Helper()
@@ -195,6 +377,76 @@ func (mr *MockConfigGetterMockRecorder) RootCmd() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootCmd", reflect.TypeOf((*MockConfigGetter)(nil).RootCmd))
 }
 
+// SBOM mocks base method.
+func (m *MockConfigGetter) SBOM() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SBOM")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SBOM indicates an expected call of SBOM.
+func (mr *MockConfigGetterMockRecorder) SBOM() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SBOM", reflect.TypeOf((*MockConfigGetter)(nil).SBOM))
+}
+
+// SandboxBinds mocks base method.
+func (m *MockConfigGetter) SandboxBinds() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SandboxBinds")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SandboxBinds indicates an expected call of SandboxBinds.
+func (mr *MockConfigGetterMockRecorder) SandboxBinds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SandboxBinds", reflect.TypeOf((*MockConfigGetter)(nil).SandboxBinds))
+}
+
+// SandboxCapabilities mocks base method.
+func (m *MockConfigGetter) SandboxCapabilities() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SandboxCapabilities")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SandboxCapabilities indicates an expected call of SandboxCapabilities.
+func (mr *MockConfigGetterMockRecorder) SandboxCapabilities() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SandboxCapabilities", reflect.TypeOf((*MockConfigGetter)(nil).SandboxCapabilities))
+}
+
+// SandboxEnvPassthrough mocks base method.
+func (m *MockConfigGetter) SandboxEnvPassthrough() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SandboxEnvPassthrough")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// SandboxEnvPassthrough indicates an expected call of SandboxEnvPassthrough.
+func (mr *MockConfigGetterMockRecorder) SandboxEnvPassthrough() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SandboxEnvPassthrough", reflect.TypeOf((*MockConfigGetter)(nil).SandboxEnvPassthrough))
+}
+
+// SandboxSeccompProfile mocks base method.
+func (m *MockConfigGetter) SandboxSeccompProfile() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SandboxSeccompProfile")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SandboxSeccompProfile indicates an expected call of SandboxSeccompProfile.
+func (mr *MockConfigGetterMockRecorder) SandboxSeccompProfile() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SandboxSeccompProfile", reflect.TypeOf((*MockConfigGetter)(nil).SandboxSeccompProfile))
+}
+
 // UseRootCmd mocks base method.
 func (m *MockConfigGetter) UseRootCmd() bool {
 	m.ctrl.T.Helper()
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package format
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/scriptfmt"
+)
+
+type Options struct {
+	Scripts []string
+	// Write writes the formatted scripts back
+	// instead of printing them.
+	Write bool
+	// Diff prints the diffs of the scripts that are not formatted.
+	Diff bool
+	// Check fails if a script is not formatted.
+	Check bool
+}
+
+type useCase struct {
+	stdout io.Writer
+}
+
+func New() *useCase {
+	return &useCase{
+		stdout: os.Stdout,
+	}
+}
+
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	unformatted := false
+	for _, script := range opts.Scripts {
+		src, err := os.ReadFile(script)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error reading the script"))
+		}
+
+		out, err := scriptfmt.Format(src)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error formatting %s", script))
+		}
+
+		changed := !bytes.Equal(src, out)
+		if changed {
+			unformatted = true
+		}
+
+		switch {
+		case opts.Diff:
+			if changed {
+				fmt.Fprint(u.stdout, udiff.Unified(script, script, string(src), string(out)))
+			}
+		case opts.Check:
+			if changed {
+				fmt.Fprintln(u.stdout, script)
+			}
+		case !opts.Write:
+			if _, err := u.stdout.Write(out); err != nil {
+				return err
+			}
+		}
+
+		if opts.Write && changed {
+			fi, err := os.Stat(script)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(script, out, fi.Mode().Perm()); err != nil {
+				return errors.WrapIntoI18nError(err, gotext.Get("Error writing the script"))
+			}
+		}
+	}
+
+	if opts.Check && unformatted {
+		return errors.NewI18nError(gotext.Get("Some build scripts are not formatted"))
+	}
+	return nil
+}
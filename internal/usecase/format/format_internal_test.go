@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package format
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	unformatted = "version=1.0\nname=foo\n"
+	formatted   = "name=\"foo\"\nversion=\"1.0\"\n"
+)
+
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Staplerfile")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o644))
+	return path
+}
+
+func run(t *testing.T, opts Options) (string, error) {
+	t.Helper()
+	var stdout bytes.Buffer
+	u := New()
+	u.stdout = &stdout
+	err := u.Run(t.Context(), opts)
+	return stdout.String(), err
+}
+
+func TestRunPrint(t *testing.T) {
+	path := writeScript(t, unformatted)
+
+	out, err := run(t, Options{Scripts: []string{path}})
+	require.NoError(t, err)
+	assert.Equal(t, formatted, out)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, unformatted, string(data))
+}
+
+func TestRunWrite(t *testing.T) {
+	path := writeScript(t, unformatted)
+
+	out, err := run(t, Options{Scripts: []string{path}, Write: true})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, formatted, string(data))
+}
+
+func TestRunDiff(t *testing.T) {
+	path := writeScript(t, unformatted)
+
+	out, err := run(t, Options{Scripts: []string{path}, Diff: true})
+	require.NoError(t, err)
+	assert.Contains(t, out, "-version=1.0\n")
+	assert.Contains(t, out, "+name=\"foo\"\n")
+}
+
+func TestRunCheck(t *testing.T) {
+	bad := writeScript(t, unformatted)
+	good := writeScript(t, formatted)
+
+	out, err := run(t, Options{Scripts: []string{bad, good}, Check: true})
+	require.Error(t, err)
+	assert.Equal(t, bad+"\n", out)
+
+	out, err = run(t, Options{Scripts: []string{good}, Check: true})
+	require.NoError(t, err)
+	assert.Empty(t, out)
+}
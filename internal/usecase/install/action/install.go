@@ -20,7 +20,9 @@ package action
 
 import (
 	"context"
+	"io"
 	"log/slog"
+	"os"
 
 	"github.com/leonelquinteros/gotext"
 
@@ -37,12 +39,15 @@ import (
 
 type builder interface {
 	InstallPkgs(ctx context.Context, input build.InstallInput, pkgs []string) ([]*commonbuild.BuiltDep, error)
+	Plan() *build.Plan
 }
 
 type useCase struct {
 	builder builder
 	mgr     manager.Manager
 	info    *distro.OSRelease
+
+	stdout io.Writer
 }
 
 func New(builder builder, mgr manager.Manager, info *distro.OSRelease) *useCase {
@@ -50,6 +55,7 @@ func New(builder builder, mgr manager.Manager, info *distro.OSRelease) *useCase
 		builder: builder,
 		mgr:     mgr,
 		info:    info,
+		stdout:  os.Stdout,
 	}
 }
 
@@ -57,6 +63,10 @@ type Options struct {
 	Pkgs        []string
 	Clean       bool
 	Interactive bool
+	Jobs        int
+	NoCheck     bool
+	DryRun      bool
+	Json        bool
 }
 
 func (u *useCase) Run(ctx context.Context, opts Options) error {
@@ -68,6 +78,9 @@ func (u *useCase) Run(ctx context.Context, opts Options) error {
 			Opts: &types.BuildOpts{
 				Clean:       opts.Clean,
 				Interactive: opts.Interactive,
+				Jobs:        opts.Jobs,
+				NoCheck:     opts.NoCheck,
+				DryRun:      opts.DryRun,
 			},
 			Info:       u.info,
 			PkgFormat_: build.GetPkgFormat(u.mgr),
@@ -95,5 +108,11 @@ func (u *useCase) Run(ctx context.Context, opts Options) error {
 		return errors.WrapIntoI18nError(err, gotext.Get("Error when installing the package"))
 	}
 
+	if opts.DryRun {
+		if err := u.builder.Plan().Write(u.stdout, opts.Json); err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error writing the plan"))
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/leonelquinteros/gotext"
+	"mvdan.cc/sh/v3/syntax"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/cpu"
+	"go.stplr.dev/stplr/internal/scriptlint"
+	"go.stplr.dev/stplr/pkg/distro"
+	"go.stplr.dev/stplr/pkg/staplerfile"
+)
+
+type Options struct {
+	Scripts []string
+	// JSON prints the diagnostics as a JSON array.
+	JSON bool
+}
+
+// lintTargets are the distros and lintArches the architectures the
+// packages of a script are evaluated for besides the host, so that
+// assignments depending on DISTRO_ID or ARCH are checked too.
+var (
+	lintTargets = []string{
+		"altlinux:p11",
+		"debian:12",
+		"ubuntu:24.04",
+		"fedora:42",
+		"rocky:9",
+		"opensuse-tumbleweed",
+		"alpine:3.22",
+		"arch",
+	}
+	lintArches = []string{"amd64", "386", "arm64", "arm7", "riscv64", "loong64", "ppc64le"}
+)
+
+type useCase struct {
+	info   *distro.OSRelease
+	stdout io.Writer
+}
+
+func New(info *distro.OSRelease) *useCase {
+	return &useCase{
+		info:   info,
+		stdout: os.Stdout,
+	}
+}
+
+type jsonDiagnostic struct {
+	File string `json:"file"`
+	scriptlint.Diagnostic
+}
+
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	all := []jsonDiagnostic{}
+	failed := false
+	for _, script := range opts.Scripts {
+		diags, err := u.lint(ctx, script)
+		if err != nil {
+			return err
+		}
+
+		for _, d := range diags {
+			if opts.JSON {
+				all = append(all, jsonDiagnostic{File: script, Diagnostic: d})
+			} else {
+				fmt.Fprintf(u.stdout, "%s:%s\n", script, d)
+			}
+		}
+		if scriptlint.HasErrors(diags) {
+			failed = true
+		}
+	}
+
+	if opts.JSON {
+		enc := json.NewEncoder(u.stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return errors.NewI18nError(gotext.Get("Lint errors found"))
+	}
+	return nil
+}
+
+// lint checks the syntax tree of the script, then evaluates it and
+// checks its packages. Errors of the script itself are diagnostics.
+func (u *useCase) lint(ctx context.Context, script string) ([]scriptlint.Diagnostic, error) {
+	src, err := os.ReadFile(script)
+	if err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error reading the script"))
+	}
+
+	f, err := syntax.NewParser().Parse(bytes.NewReader(src), script)
+	if err != nil {
+		return []scriptlint.Diagnostic{scriptlint.ParseError(err)}, nil
+	}
+	diags := scriptlint.Check(f)
+
+	sf, err := staplerfile.ReadFromIOReader(bytes.NewReader(src), script)
+	if err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error parsing the script"))
+	}
+
+	infos, err := u.targets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var evalDiags []scriptlint.Diagnostic
+	for _, arch := range u.arches() {
+		err := withArch(arch, func() error {
+			for _, info := range infos {
+				_, pkgs, err := sf.ParseBuildVars(ctx, info, nil)
+				if err != nil {
+					evalDiags = append(evalDiags, scriptlint.EvalError(err))
+					continue
+				}
+				evalDiags = append(evalDiags, scriptlint.CheckPackages(f, pkgs)...)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, d := range evalDiags {
+		if !slices.Contains(diags, d) {
+			diags = append(diags, d)
+		}
+	}
+	return diags, nil
+}
+
+// targets returns the host and the distros of lintTargets.
+func (u *useCase) targets(ctx context.Context) ([]*distro.OSRelease, error) {
+	infos := []*distro.OSRelease{u.info}
+	for _, spec := range lintTargets {
+		info, err := distro.ParseTarget(ctx, spec)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// arches returns the host architecture and lintArches.
+func (u *useCase) arches() []string {
+	arches := []string{cpu.Arch()}
+	for _, arch := range lintArches {
+		if !slices.Contains(arches, arch) {
+			arches = append(arches, arch)
+		}
+	}
+	return arches
+}
+
+// withArch runs fn with scripts evaluated for arch, which is read
+// from STPLR_ARCH like everywhere else.
+func withArch(arch string, fn func() error) error {
+	prev, ok := os.LookupEnv("STPLR_ARCH")
+	if err := os.Setenv("STPLR_ARCH", arch); err != nil {
+		return err
+	}
+	defer func() {
+		if ok {
+			_ = os.Setenv("STPLR_ARCH", prev)
+		} else {
+			_ = os.Unsetenv("STPLR_ARCH")
+		}
+	}()
+	return fn()
+}
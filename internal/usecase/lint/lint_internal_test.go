@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/pkg/distro"
+)
+
+func writeScript(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Staplerfile")
+	require.NoError(t, os.WriteFile(path, []byte(script), 0o644))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	path := writeScript(t, `name=foo
+version=1.0
+release=1
+license=('MIT')
+build_depends=('gcc')
+
+sources=('https://example.com/foo.tar.gz')
+checksums=('SKIP')
+sources_amd64=('https://example.com/foo.tar.gz' 'https://example.com/bar.tar.gz')
+`)
+
+	var stdout bytes.Buffer
+	u := New(&distro.OSRelease{ID: "fedora"})
+	u.stdout = &stdout
+
+	require.Error(t, u.Run(t.Context(), Options{Scripts: []string{path}}))
+	assert.Equal(t, path+":5:1: warning: unknown-variable: unknown variable build_depends, did you mean build_deps?\n"+
+		path+":9:1: error: checksums-length: checksums_amd64 of foo has 1 entries, but there are 2 sources\n",
+		stdout.String())
+}
+
+func TestRunTargets(t *testing.T) {
+	path := writeScript(t, `name=foo
+version=1.0
+release=1
+license=('MIT')
+if [ "$DISTRO_ID" = "debian" ] && [ "$ARCH" = "arm64" ]; then
+	license=()
+fi
+`)
+
+	var stdout bytes.Buffer
+	u := New(&distro.OSRelease{ID: "fedora"})
+	u.stdout = &stdout
+
+	require.NoError(t, u.Run(t.Context(), Options{Scripts: []string{path}}))
+	assert.Equal(t, path+":1:1: warning: missing-license: package foo has no license\n", stdout.String())
+}
+
+func TestRunJSON(t *testing.T) {
+	path := writeScript(t, "name=foo\nversion=1.0\nrelease=1\n")
+
+	var stdout bytes.Buffer
+	u := New(&distro.OSRelease{ID: "fedora"})
+	u.stdout = &stdout
+
+	require.NoError(t, u.Run(t.Context(), Options{Scripts: []string{path}, JSON: true}))
+
+	var diags []map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &diags))
+	assert.Equal(t, []map[string]any{{
+		"file":     path,
+		"line":     float64(1),
+		"column":   float64(1),
+		"severity": "warning",
+		"check":    "missing-license",
+		"message":  "package foo has no license",
+	}}, diags)
+}
+
+func TestRunSyntaxError(t *testing.T) {
+	path := writeScript(t, "name=foo\nif true; then\n")
+
+	var stdout bytes.Buffer
+	u := New(&distro.OSRelease{ID: "fedora"})
+	u.stdout = &stdout
+
+	require.Error(t, u.Run(t.Context(), Options{Scripts: []string{path}}))
+	assert.Contains(t, stdout.String(), path+":2:10: error: syntax: ")
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package lintpkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/config"
+	"go.stplr.dev/stplr/internal/pkglint"
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type Config interface {
+	Repos() []types.Repo
+	GetPaths() *config.Paths
+}
+
+type useCase struct {
+	cfg    Config
+	stdout io.Writer
+}
+
+func New(cfg Config) *useCase {
+	return &useCase{
+		cfg:    cfg,
+		stdout: os.Stdout,
+	}
+}
+
+type Options struct {
+	Paths []string
+	// Format overrides the format detected from the file names.
+	Format string
+	// Repo is the repository whose lint settings are used.
+	// The defaults are used if it is empty.
+	Repo string
+}
+
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	levels, err := u.levels(opts.Repo)
+	if err != nil {
+		return err
+	}
+
+	// RPATHs into any build directory are reported
+	buildDirs := []string{u.cfg.GetPaths().PkgsDir}
+
+	failed := false
+	for _, path := range opts.Paths {
+		format := opts.Format
+		if format == "" {
+			format = pkgsig.Format(path)
+		}
+		if format == "" {
+			return errors.NewI18nError(gotext.Get("Cannot detect the format of %s, use --format", path))
+		}
+
+		findings, err := pkglint.LintFile(path, format, levels, buildDirs)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error reading the package"))
+		}
+
+		for _, f := range findings {
+			fmt.Fprintf(u.stdout, "%s: %s\n", path, f)
+		}
+		if pkglint.HasErrors(findings) {
+			failed = true
+		}
+	}
+
+	if failed {
+		return errors.NewI18nError(gotext.Get("Lint errors found"))
+	}
+	return nil
+}
+
+func (u *useCase) levels(repo string) (pkglint.Levels, error) {
+	if repo == "" {
+		return pkglint.DefaultLevels(), nil
+	}
+
+	for _, r := range u.cfg.Repos() {
+		if r.Name != repo {
+			continue
+		}
+		levels, err := pkglint.ParseLevels(r.Lint)
+		if err != nil {
+			return nil, errors.WrapIntoI18nError(err, gotext.Get("Invalid lint settings of repo %q", repo))
+		}
+		return levels, nil
+	}
+
+	return nil, errors.NewI18nError(gotext.Get("Repo \"%s\" does not exist", repo))
+}
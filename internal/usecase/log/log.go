@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/leonelquinteros/gotext"
+	"github.com/mattn/go-isatty"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/app/tui/pager"
+	"go.stplr.dev/stplr/internal/buildlog"
+)
+
+type useCase struct {
+	stores []*buildlog.Store
+
+	stdout io.Writer
+	page   func(name, content string) error
+}
+
+func New(stores []*buildlog.Store) *useCase {
+	return &useCase{
+		stores: stores,
+		stdout: os.Stdout,
+		page:   pageOrPrint,
+	}
+}
+
+type Options struct {
+	Pkg    string
+	Last   bool
+	Failed bool
+}
+
+type entry struct {
+	*buildlog.Entry
+	store *buildlog.Store
+}
+
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	var entries []entry
+	for _, s := range u.stores {
+		found, err := s.List(opts.Pkg)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error reading build logs"))
+		}
+		for _, e := range found {
+			entries = append(entries, entry{e, s})
+		}
+	}
+	slices.SortFunc(entries, func(a, b entry) int {
+		return b.Started.Compare(a.Started)
+	})
+
+	if opts.Failed {
+		entries = slices.DeleteFunc(entries, func(e entry) bool {
+			return e.Status != buildlog.StatusFailed
+		})
+		if len(entries) == 0 {
+			return errors.NewI18nError(gotext.Get("No failed builds of %s found", opts.Pkg))
+		}
+	}
+
+	if len(entries) == 0 {
+		return errors.NewI18nError(gotext.Get("No build logs of %s found", opts.Pkg))
+	}
+
+	if !opts.Last && !opts.Failed {
+		u.list(entries)
+		return nil
+	}
+
+	content, err := u.render(entries[0])
+	if err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error reading build logs"))
+	}
+
+	return u.page(entries[0].FullName(), content)
+}
+
+func (u *useCase) list(entries []entry) {
+	for _, e := range entries {
+		fmt.Fprintf(
+			u.stdout,
+			"%s  %s %s  %s  %s\n",
+			e.Started.Local().Format(time.DateTime),
+			e.FullName(),
+			e.Version,
+			e.Status,
+			duration(e.Entry),
+		)
+	}
+}
+
+func (u *useCase) render(e entry) (string, error) {
+	output, err := e.store.ReadOutput(e.Entry)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", e.FullName(), e.Version)
+	fmt.Fprintf(&b, "%s %s\n", gotext.Get("Started:"), e.Started.Local().Format(time.DateTime))
+	fmt.Fprintf(&b, "%s %s\n", gotext.Get("Status:"), e.Status)
+	if !e.Finished.IsZero() {
+		fmt.Fprintf(&b, "%s %s\n", gotext.Get("Duration:"), duration(e.Entry))
+	}
+	if e.Error != "" {
+		fmt.Fprintf(&b, "%s %s\n", gotext.Get("Error:"), e.Error)
+	}
+	if len(e.Steps) > 0 {
+		fmt.Fprintf(&b, "%s\n", gotext.Get("Steps:"))
+		for _, step := range e.Steps {
+			fmt.Fprintf(&b, "  %-20s %s\n", step.Name, step.Duration.Round(time.Millisecond))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(output)
+
+	return b.String(), nil
+}
+
+func duration(e *buildlog.Entry) string {
+	if e.Finished.IsZero() {
+		return "-"
+	}
+	return e.Finished.Sub(e.Started).Round(time.Second).String()
+}
+
+func pageOrPrint(name, content string) error {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		_, err := io.WriteString(os.Stdout, content)
+		return err
+	}
+	return pager.NewCode(name, content).Run()
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/buildlog"
+)
+
+func newTestUseCase(t *testing.T) (*useCase, *bytes.Buffer, *string) {
+	t.Helper()
+
+	fs := afero.NewMemMapFs()
+	s := buildlog.NewStore(fs, "/logs")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ok, err := s.Create("default", "foo", "1.0", start)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, ok.OutputPath(), []byte("all good\n"), 0o644))
+	require.NoError(t, s.Finish(ok, start.Add(time.Minute), nil))
+
+	failed, err := s.Create("default", "foo", "1.1", start.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, failed.OutputPath(), []byte("compile error\n"), 0o644))
+	require.NoError(t, s.Finish(failed, start.Add(time.Hour+time.Minute), errors.New("exit status 1")))
+
+	stdout := &bytes.Buffer{}
+	paged := new(string)
+	u := &useCase{
+		stores: []*buildlog.Store{s},
+		stdout: stdout,
+		page: func(name, content string) error {
+			*paged = content
+			return nil
+		},
+	}
+	return u, stdout, paged
+}
+
+func TestList(t *testing.T) {
+	u, stdout, _ := newTestUseCase(t)
+
+	require.NoError(t, u.Run(context.Background(), Options{Pkg: "foo"}))
+
+	lines := bytes.Split(bytes.TrimSpace(stdout.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), "default/foo 1.1  failed  1m0s")
+	assert.Contains(t, string(lines[1]), "default/foo 1.0  succeeded  1m0s")
+}
+
+func TestLast(t *testing.T) {
+	u, _, paged := newTestUseCase(t)
+
+	require.NoError(t, u.Run(context.Background(), Options{Pkg: "foo", Last: true}))
+	assert.Contains(t, *paged, "default/foo 1.1")
+	assert.Contains(t, *paged, "exit status 1")
+	assert.Contains(t, *paged, "compile error")
+}
+
+func TestFailed(t *testing.T) {
+	u, _, paged := newTestUseCase(t)
+
+	require.NoError(t, u.Run(context.Background(), Options{Pkg: "default/foo", Failed: true}))
+	assert.Contains(t, *paged, "compile error")
+}
+
+func TestNotFound(t *testing.T) {
+	u, _, _ := newTestUseCase(t)
+
+	require.Error(t, u.Run(context.Background(), Options{Pkg: "bar"}))
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package newpkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/scaffold"
+	"go.stplr.dev/stplr/internal/xtract"
+	"go.stplr.dev/stplr/pkg/dl"
+)
+
+type Options struct {
+	Name string
+	// From is a local archive or directory, or the URL of a source.
+	From string
+	// Output is the directory of the Staplerfile.
+	// It is the name of the package if it is empty.
+	Output string
+}
+
+type useCase struct {
+	out output.Output
+}
+
+func New(out output.Output) *useCase {
+	return &useCase{
+		out: out,
+	}
+}
+
+// source is a source tree that a Staplerfile is generated for.
+type source struct {
+	// root is the root of the tree on disk.
+	root string
+	// dir is the root of the tree in the source directory of a build.
+	dir      string
+	url      string
+	checksum string
+	version  string
+}
+
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	outDir := opts.Output
+	if outDir == "" {
+		outDir = opts.Name
+	}
+	script := filepath.Join(outDir, "Staplerfile")
+	if _, err := os.Stat(script); err == nil {
+		return errors.NewI18nError(gotext.Get("%s already exists", script))
+	}
+
+	tmp, err := os.MkdirTemp("", "stplr-new-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error creating the directory of the package"))
+	}
+
+	src, err := u.fetch(ctx, opts.From, tmp, outDir)
+	if err != nil {
+		return err
+	}
+
+	spec := scaffold.Spec{
+		Name:        opts.Name,
+		Version:     src.version,
+		Licenses:    scaffold.DetectLicenses(src.root),
+		Dir:         src.dir,
+		BuildSystem: scaffold.DetectBuildSystem(src.root),
+	}
+	if src.url != "" {
+		spec.Sources = []string{src.url}
+		spec.Checksums = []string{src.checksum}
+	} else {
+		u.out.Warn("%s", gotext.Get("A directory cannot be a source, add the sources of the package to %s", script))
+	}
+	if v := spec.BuildSystem.Version(src.root); v != "" {
+		spec.Version = v
+	}
+	if spec.BuildSystem == nil {
+		u.out.Warn("%s", gotext.Get("Cannot detect the build system, fill in the package function"))
+	}
+	if len(spec.Licenses) == 0 {
+		u.out.Warn("%s", gotext.Get("Cannot detect the license, fill in the license variable"))
+	}
+
+	data, err := scaffold.Generate(spec)
+	if err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error generating the Staplerfile"))
+	}
+	if err := os.WriteFile(script, data, 0o644); err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error writing the Staplerfile"))
+	}
+
+	u.out.Info("%s", gotext.Get("Created %s", script))
+	return nil
+}
+
+// fetch returns the source tree of from. Local archives are copied to
+// outDir, next to the Staplerfile, and extracted to tmp like the ones
+// that are downloaded.
+func (u *useCase) fetch(ctx context.Context, from, tmp, outDir string) (*source, error) {
+	if fi, err := os.Stat(from); err == nil {
+		if fi.IsDir() {
+			return &source{root: from}, nil
+		}
+		if !xtract.IsSupported(from) {
+			return nil, errors.NewI18nError(gotext.Get("%s is not a supported archive", from))
+		}
+
+		name := filepath.Base(from)
+		if err := copyFile(from, filepath.Join(outDir, name)); err != nil {
+			return nil, errors.WrapIntoI18nError(err, gotext.Get("Error copying the archive"))
+		}
+		return u.extract(from, "local:///"+name, tmp)
+	}
+
+	url := from
+	if !strings.HasPrefix(url, "git+") && strings.HasSuffix(url, ".git") {
+		url = "git+" + url
+	}
+
+	dest := filepath.Join(tmp, "download")
+	res, err := dl.Download(ctx, dl.Options{
+		Name:             filepath.Base(url),
+		URL:              url,
+		Destination:      dest,
+		CacheDisabled:    true,
+		PostprocDisabled: true,
+		Progress:         os.Stderr,
+		Output:           u.out,
+	})
+	if err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error downloading the source"))
+	}
+
+	if res.Type == dl.TypeDir {
+		// VCS checkouts have no checksum
+		return &source{
+			root:     filepath.Join(dest, res.Name),
+			dir:      res.Name,
+			url:      url,
+			checksum: "SKIP",
+		}, nil
+	}
+	return u.extract(filepath.Join(dest, res.Name), url, tmp)
+}
+
+// extract extracts the archive at path, which is the source url.
+func (u *useCase) extract(path, url, tmp string) (*source, error) {
+	sum, err := sha256File(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root := filepath.Join(tmp, "src")
+	if _, err := xtract.ExtractArchive(path, root); err != nil {
+		return nil, errors.WrapIntoI18nError(err, gotext.Get("Error extracting the archive"))
+	}
+
+	src := &source{
+		root:     root,
+		url:      url,
+		checksum: sum,
+		version:  scaffold.VersionFromName(trimArchiveExt(filepath.Base(path))),
+	}
+
+	// Archives usually have one directory with the source tree
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		src.dir = entries[0].Name()
+		src.root = filepath.Join(root, src.dir)
+	}
+	return src, nil
+}
+
+func trimArchiveExt(name string) string {
+	lower := strings.ToLower(name)
+	ext := ""
+	for _, e := range xtract.SupportedExtensions() {
+		if strings.HasSuffix(lower, e) && len(e) > len(ext) {
+			ext = e
+		}
+	}
+	return name[:len(name)-len(ext)]
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func copyFile(from, to string) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package newpkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/app/output"
+)
+
+func writeTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Cargo.toml"), []byte("[package]\nname = \"foo\"\nversion = \"0.3.0\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("MIT License\n\nPermission is hereby granted, free of charge\n"), 0o644))
+	return dir
+}
+
+func TestRunDir(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "foo")
+
+	u := New(output.NewConsoleOutput())
+	require.NoError(t, u.Run(t.Context(), Options{Name: "foo", From: writeTree(t), Output: out}))
+
+	data, err := os.ReadFile(filepath.Join(out, "Staplerfile"))
+	require.NoError(t, err)
+	assert.Equal(t, `name="foo"
+version="0.3.0"
+release=1
+license=("MIT")
+summary=""
+build_deps=("cargo")
+sources=()
+checksums=()
+
+build() {
+    cargo build --release --locked
+}
+
+package() {
+    install-binary "target/release/${name}"
+}
+`, string(data))
+
+	require.Error(t, u.Run(t.Context(), Options{Name: "foo", From: writeTree(t), Output: out}))
+}
+
+func TestRunGit(t *testing.T) {
+	dir := writeTree(t)
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, wt.AddGlob("."))
+	_, err = wt.Commit("init", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	out := filepath.Join(t.TempDir(), "foo")
+	u := New(output.NewConsoleOutput())
+	require.NoError(t, u.Run(t.Context(), Options{Name: "foo", From: "git+file://" + dir, Output: out}))
+
+	data, err := os.ReadFile(filepath.Join(out, "Staplerfile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `sources=("git+file://`+dir+`")
+checksums=("SKIP")
+
+build() {
+    cd "${srcdir}/`+filepath.Base(dir)+`"
+    cargo build --release --locked
+}
+`)
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package outdated
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/repoprocessor"
+	"go.stplr.dev/stplr/internal/upstream"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type Options struct {
+	// Repo is the repository whose packages are checked.
+	// All repositories are checked if it is empty.
+	Repo string
+}
+
+type useCase struct {
+	repos []types.Repo
+	// repoDir is where the repositories are pulled to.
+	repoDir string
+	out     output.Output
+	checker *upstream.Checker
+	stdout  io.Writer
+}
+
+func New(repos []types.Repo, repoDir string, out output.Output) *useCase {
+	return &useCase{
+		repos:   repos,
+		repoDir: repoDir,
+		out:     out,
+		checker: upstream.NewChecker(nil),
+		stdout:  os.Stdout,
+	}
+}
+
+// Run prints the current and upstream versions of the packages of the
+// repositories. Packages without an update_check array are listed with
+// their current version only.
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	repos := u.repos
+	if opts.Repo != "" {
+		repos = nil
+		for _, r := range u.repos {
+			if r.Name == opts.Repo {
+				repos = append(repos, r)
+			}
+		}
+		if len(repos) == 0 {
+			return errors.NewI18nError(gotext.Get("Repo \"%s\" does not exist", opts.Repo))
+		}
+	}
+
+	w := tabwriter.NewWriter(u.stdout, 0, 0, 2, ' ', 0)
+	failed := 0
+	for _, repo := range repos {
+		pkgs, err := repoprocessor.New().Process(ctx, repo, filepath.Join(u.repoDir, repo.Name))
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error reading the packages of repo %q", repo.Name))
+		}
+
+		for _, pkg := range pkgs {
+			name := repo.Name + "/" + pkg.Name
+			if len(pkg.UpdateCheck) == 0 {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, pkg.Version, "-", gotext.Get("no update_check"))
+				continue
+			}
+
+			latest, err := u.latest(ctx, pkg.Name, pkg.UpdateCheck)
+			if err != nil {
+				u.out.Warn("%s", gotext.Get("Cannot check the upstream version of %s: %s", name, err))
+				failed++
+				continue
+			}
+
+			status := gotext.Get("up to date")
+			if upstream.IsNewer(pkg.Version, latest) {
+				status = gotext.Get("outdated")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, pkg.Version, latest, status)
+		}
+	}
+	_ = w.Flush()
+
+	if failed > 0 {
+		return errors.NewI18nError(gotext.GetN("Checking %d package failed", "Checking %d packages failed", failed, failed))
+	}
+	return nil
+}
+
+func (u *useCase) latest(ctx context.Context, name string, spec map[string]string) (string, error) {
+	check, err := upstream.ParseCheck(name, spec)
+	if err != nil {
+		return "", err
+	}
+	return u.checker.Latest(ctx, check)
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package outdated
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.stplr.dev/stplr/internal/app/output"
+	"go.stplr.dev/stplr/internal/upstream"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+func writeScript(t *testing.T, dir, script string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Staplerfile"), []byte(script), 0o644))
+}
+
+func TestRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pypi/foo/json":
+			w.Write([]byte(`{"info": {"version": "1.1"}}`))
+		case "/pypi/bar/json":
+			w.Write([]byte(`{"info": {"version": "2.0"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	repoDir := t.TempDir()
+	writeScript(t, filepath.Join(repoDir, "default", "foo"), `name=foo
+version=1.0
+release=1
+update_check=(['type']=pypi ['url']=`+srv.URL+`)
+`)
+	writeScript(t, filepath.Join(repoDir, "default", "bar"), `name=bar
+version=2.0
+release=1
+update_check=(['type']=pypi ['url']=`+srv.URL+`)
+`)
+	writeScript(t, filepath.Join(repoDir, "default", "baz"), `name=baz
+version=1.0
+release=1
+`)
+	writeScript(t, filepath.Join(repoDir, "other"), `name=qux
+version=1.0
+release=1
+update_check=(['type']=pypi ['url']=`+srv.URL+`)
+`)
+
+	repos := []types.Repo{{Name: "default"}, {Name: "other"}}
+
+	var stdout bytes.Buffer
+	u := New(repos, repoDir, output.NewConsoleOutput())
+	u.checker = upstream.NewChecker(srv.Client())
+	u.stdout = &stdout
+
+	want := "default/bar  2.0  2.0  up to date\n" +
+		"default/baz  1.0  -    no update_check\n" +
+		"default/foo  1.0  1.1  outdated\n"
+
+	require.NoError(t, u.Run(t.Context(), Options{Repo: "default"}))
+	assert.Equal(t, want, stdout.String())
+
+	stdout.Reset()
+	require.Error(t, u.Run(t.Context(), Options{}))
+	assert.Equal(t, want, stdout.String())
+
+	require.Error(t, u.Run(t.Context(), Options{Repo: "missing"}))
+}
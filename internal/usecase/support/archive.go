@@ -35,6 +35,9 @@ import (
 
 	"github.com/coreos/go-systemd/v22/sdjournal"
 	"github.com/spf13/afero"
+
+	"go.stplr.dev/stplr/internal/buildlog"
+	"go.stplr.dev/stplr/internal/constants"
 )
 
 type CommandExecutor interface {
@@ -108,6 +111,10 @@ func (ac *archiveCreator) CreateSupportArchive(ctx context.Context, archivePath
 		return fmt.Errorf("add stplr logs: %w", err)
 	}
 
+	if err := ac.addBuildLogs(tw); err != nil {
+		return fmt.Errorf("add build logs: %w", err)
+	}
+
 	return nil
 }
 
@@ -158,6 +165,40 @@ func (ac *archiveCreator) addStplrLogs(tw *tar.Writer) error {
 	return addReaderToTar(tw, "journal.log", bytes.NewReader(buf.Bytes()), n)
 }
 
+// buildLogsCount is how many of the latest build logs go to the archive.
+const buildLogsCount = 5
+
+func (ac *archiveCreator) addBuildLogs(tw *tar.Writer) error {
+	logsDir := buildlog.Dir(constants.SystemCachePath)
+
+	entries, err := buildlog.NewStore(ac.fs, logsDir).Latest(buildLogsCount)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		rel, err := filepath.Rel(logsDir, e.Dir)
+		if err != nil {
+			return err
+		}
+		for _, path := range e.Files() {
+			data, err := afero.ReadFile(ac.fs, path)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return err
+			}
+			tarName := filepath.Join("build-logs", rel, filepath.Base(path))
+			if err := addReaderToTar(tw, tarName, bytes.NewReader(data), int64(len(data))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func (ac *archiveCreator) addFilteredRepoDir(tw *tar.Writer, path string) error {
 	matches, err := afero.Glob(ac.fs, path)
 	if err != nil {
@@ -141,6 +141,8 @@ func TestCreateSupportArchiveExtractAndVerify(t *testing.T) {
 
 	afero.WriteFile(fs, "/etc/os-release", []byte("NAME=TestOS"), 0o644)
 	afero.WriteFile(fs, "/etc/stplr/stplr.toml", []byte("url = \"secret\"\nname = \"test\""), 0o644)
+	afero.WriteFile(fs, "/var/cache/stplr/logs/default/foo/1.0/20260102T030405.000Z/meta.json", []byte(`{"package":"foo"}`), 0o644)
+	afero.WriteFile(fs, "/var/cache/stplr/logs/default/foo/1.0/20260102T030405.000Z/output.log", []byte("build output"), 0o644)
 
 	mockExecutor := NewMockCommandExecutor(ctrl)
 	mockJournal := NewMockJournalReader(ctrl)
@@ -209,6 +211,7 @@ func TestCreateSupportArchiveExtractAndVerify(t *testing.T) {
 	assert.Contains(t, files, "commands.log")
 	assert.Contains(t, files, "journal.log")
 	assert.Contains(t, files, "disk-usage.log")
+	assert.Equal(t, "build output", files["build-logs/default/foo/1.0/20260102T030405.000Z/output.log"])
 
 	assert.Contains(t, files["/etc/stplr/stplr.toml.filtered"], `url = "<filtered>"`)
 	assert.NotContains(t, files["/etc/stplr/stplr.toml.filtered"], "secret")
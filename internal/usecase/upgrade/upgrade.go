@@ -20,6 +20,8 @@ package upgrade
 
 import (
 	"context"
+	"io"
+	"os"
 
 	"github.com/leonelquinteros/gotext"
 
@@ -42,6 +44,7 @@ type builder interface {
 		args build.InstallInput,
 		pkgs []staplerfile.Package,
 	) error
+	Plan() *build.Plan
 }
 
 type useCase struct {
@@ -52,7 +55,8 @@ type useCase struct {
 	upd     *updater.Updater
 	repos   *repos.Repos
 
-	out output.Output
+	out    output.Output
+	stdout io.Writer
 }
 
 func New(builder builder, upd *updater.Updater, manager manager.Manager, db *db.Database, repos *repos.Repos, info *distro.OSRelease, out output.Output) *useCase {
@@ -64,6 +68,7 @@ func New(builder builder, upd *updater.Updater, manager manager.Manager, db *db.
 		upd:     upd,
 		repos:   repos,
 		out:     out,
+		stdout:  os.Stdout,
 	}
 }
 
@@ -71,12 +76,20 @@ type Options struct {
 	Pkgs        []string
 	Clean       bool
 	Interactive bool
+	Jobs        int
+	NoCheck     bool
+	DryRun      bool
+	Json        bool
 }
 
 func (u *useCase) Run(ctx context.Context, opts Options) error {
-	err := u.repos.PullAll(ctx)
-	if err != nil {
-		return errors.WrapIntoI18nError(err, gotext.Get("Error pulling repositories"))
+	// A dry run plans against the repositories that are already pulled,
+	// since pulling changes them.
+	if !opts.DryRun {
+		err := u.repos.PullAll(ctx)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Error pulling repositories"))
+		}
 	}
 
 	updates, err := u.upd.CheckForUpdates(ctx)
@@ -84,6 +97,10 @@ func (u *useCase) Run(ctx context.Context, opts Options) error {
 		return errors.WrapIntoI18nError(err, gotext.Get("Error checking for updates"))
 	}
 
+	if opts.DryRun {
+		return u.runDry(ctx, opts, updates)
+	}
+
 	if len(updates) == 0 {
 		u.out.Info(gotext.Get("There is nothing to do."))
 		return nil
@@ -117,6 +134,8 @@ func (u *useCase) Run(ctx context.Context, opts Options) error {
 					Opts: &types.BuildOpts{
 						Clean:       opts.Clean,
 						Interactive: opts.Interactive,
+						Jobs:        opts.Jobs,
+						NoCheck:     opts.NoCheck,
 					},
 					Info:       u.info,
 					PkgFormat_: build.GetPkgFormat(u.mgr),
@@ -166,6 +185,35 @@ func (u *useCase) Run(ctx context.Context, opts Options) error {
 	return nil
 }
 
+func (u *useCase) runDry(ctx context.Context, opts Options, updates []updater.UpdateInfo) error {
+	for _, update := range updates {
+		err := u.builder.InstallALRPackages(
+			ctx,
+			&build.BuildArgs{
+				Opts: &types.BuildOpts{
+					Clean:       opts.Clean,
+					Interactive: opts.Interactive,
+					Jobs:        opts.Jobs,
+					NoCheck:     opts.NoCheck,
+					DryRun:      true,
+				},
+				Info:       u.info,
+				PkgFormat_: build.GetPkgFormat(u.mgr),
+			},
+			[]staplerfile.Package{*update.Package},
+		)
+		if err != nil {
+			return errors.WrapIntoI18nError(err, gotext.Get("Failed to plan upgrade of %s", update.Package.Name))
+		}
+	}
+
+	if err := u.builder.Plan().Write(u.stdout, opts.Json); err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Error writing the plan"))
+	}
+
+	return nil
+}
+
 func (u *useCase) printSummary(succeeded []string, failed []struct {
 	pkg string
 	err error
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package verify
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+
+	"go.stplr.dev/stplr/internal/app/errors"
+	"go.stplr.dev/stplr/internal/pkgsig"
+	"go.stplr.dev/stplr/pkg/types"
+)
+
+type Config interface {
+	Signing() types.Signing
+}
+
+type useCase struct {
+	cfg    Config
+	stdout io.Writer
+}
+
+func New(cfg Config) *useCase {
+	return &useCase{
+		cfg:    cfg,
+		stdout: os.Stdout,
+	}
+}
+
+type Options struct {
+	Path string
+	// Format overrides the format detected from the file name.
+	Format string
+	// Key overrides the configured public key.
+	Key string
+}
+
+func (u *useCase) Run(ctx context.Context, opts Options) error {
+	format := opts.Format
+	if format == "" {
+		format = pkgsig.Format(opts.Path)
+	}
+	if format == "" {
+		return errors.NewI18nError(gotext.Get("Cannot detect the format of %s, use --format", opts.Path))
+	}
+
+	key := opts.Key
+	if key == "" {
+		key = u.cfg.Signing().For(format).PublicKeyFile
+	}
+	if key == "" {
+		return errors.NewI18nError(gotext.Get("No public key is configured for %s packages", format))
+	}
+
+	signer, err := pkgsig.Verify(opts.Path, format, key)
+	if stdErrors.Is(err, pkgsig.ErrNotSigned) {
+		return errors.NewI18nError(gotext.Get("Package %s is not signed", opts.Path))
+	}
+	if err != nil {
+		return errors.WrapIntoI18nError(err, gotext.Get("Signature verification failed"))
+	}
+
+	fmt.Fprintln(u.stdout, gotext.Get("Good signature from %q", signer))
+	return nil
+}
@@ -106,3 +106,88 @@ func TestParseOSReleaseFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected OSRelease
+	}{
+		{
+			spec: "fedora:42",
+			expected: OSRelease{
+				ID:         "fedora",
+				VersionID:  "42",
+				ReleaseID:  "42",
+				PlatformID: "platform:f42",
+			},
+		},
+		{
+			spec: "almalinux:9.4",
+			expected: OSRelease{
+				ID:         "almalinux",
+				Like:       []string{"rhel", "centos", "fedora"},
+				VersionID:  "9.4",
+				ReleaseID:  "9",
+				PlatformID: "platform:el9",
+			},
+		},
+		{
+			spec: "ubuntu:24.04",
+			expected: OSRelease{
+				ID:        "ubuntu",
+				Like:      []string{"debian"},
+				VersionID: "24.04",
+				ReleaseID: "noble",
+			},
+		},
+		{
+			spec: "debian:trixie",
+			expected: OSRelease{
+				ID:        "debian",
+				ReleaseID: "trixie",
+			},
+		},
+		{
+			spec: "altlinux:p11",
+			expected: OSRelease{
+				ID:        "altlinux",
+				VersionID: "p11",
+				ReleaseID: "p11",
+			},
+		},
+		{
+			spec: "alpine",
+			expected: OSRelease{
+				ID: "alpine",
+			},
+		},
+		{
+			spec: filepath.Join("tests-fixtures", "debian-12"),
+			expected: OSRelease{
+				ID:        "debian",
+				VersionID: "12",
+				ReleaseID: "bookworm",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			actual, err := ParseTarget(context.Background(), tt.spec)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expected.ID, actual.ID)
+			assert.Equal(t, tt.expected.VersionID, actual.VersionID)
+			assert.Equal(t, tt.expected.ReleaseID, actual.ReleaseID)
+			if tt.expected.Like != nil {
+				assert.Equal(t, tt.expected.Like, actual.Like)
+			}
+			if tt.expected.PlatformID != "" {
+				assert.Equal(t, tt.expected.PlatformID, actual.PlatformID)
+			}
+		})
+	}
+
+	_, err := ParseTarget(context.Background(), ":42")
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// Stapler
+// Copyright (C) 2026 The Stapler Authors
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package distro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// knownLikes holds ID_LIKE of the distros that can be given as id:version.
+var knownLikes = map[string][]string{
+	"ubuntu":              {"debian"},
+	"linuxmint":           {"ubuntu", "debian"},
+	"pop":                 {"ubuntu", "debian"},
+	"centos":              {"rhel", "fedora"},
+	"rocky":               {"rhel", "centos", "fedora"},
+	"almalinux":           {"rhel", "centos", "fedora"},
+	"opensuse-leap":       {"suse", "opensuse"},
+	"opensuse-tumbleweed": {"opensuse", "suse"},
+	"manjaro":             {"arch"},
+	"endeavouros":         {"arch"},
+}
+
+// knownCodenames maps versions of Debian-like distros to VERSION_CODENAME.
+var knownCodenames = map[string]string{
+	"debian:11":    "bullseye",
+	"debian:12":    "bookworm",
+	"debian:13":    "trixie",
+	"ubuntu:20.04": "focal",
+	"ubuntu:22.04": "jammy",
+	"ubuntu:24.04": "noble",
+	"ubuntu:24.10": "oracular",
+	"ubuntu:25.04": "plucky",
+}
+
+// ParseTarget returns the release of a distro that packages are built
+// for. spec is either a path to an os-release file or id:version, for
+// example "fedora:42" or "ubuntu:noble".
+func ParseTarget(ctx context.Context, spec string) (*OSRelease, error) {
+	if _, err := os.Stat(spec); err == nil {
+		f, err := os.Open(spec)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseOSReleaseFromFile(ctx, f)
+	}
+
+	id, version, _ := strings.Cut(spec, ":")
+	if id == "" || strings.Contains(id, "/") {
+		return nil, fmt.Errorf("invalid target distro %q", spec)
+	}
+	return targetFromID(id, version)
+}
+
+func targetFromID(id, version string) (*OSRelease, error) {
+	if strings.ContainsAny(version, " \t\n") {
+		return nil, errors.New("invalid target distro version")
+	}
+
+	info := &OSRelease{
+		ID:        id,
+		Like:      knownLikes[id],
+		VersionID: version,
+	}
+
+	numeric := regexp.MustCompile(`^\d`).MatchString(version)
+
+	switch {
+	case IsIdEqualOrLike(info, "altlinux"):
+		info.ReleaseID = version
+
+	case IsIdEqualOrLike(info, "fedora") && numeric:
+		major, _, _ := strings.Cut(version, ".")
+		if IsIdEqualOrLike(info, "rhel") {
+			info.PlatformID = "platform:el" + major
+		} else {
+			info.PlatformID = "platform:f" + major
+		}
+
+	case IsIdEqualOrLike(info, "debian"), IsIdEqualOrLike(info, "ubuntu"):
+		if !numeric {
+			// a codename was given instead of the version
+			info.VersionID = ""
+			info.ReleaseID = version
+		} else {
+			info.ReleaseID = knownCodenames[id+":"+version]
+		}
+	}
+
+	if info.ReleaseID == "" && numeric {
+		info.ReleaseID, _, _ = strings.Cut(version, ".")
+	}
+
+	re := regexp.MustCompile(`[^A-Za-z0-9_]`)
+	info.ReleaseID = re.ReplaceAllString(info.ReleaseID, "_")
+
+	return info, nil
+}
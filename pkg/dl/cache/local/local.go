@@ -19,12 +19,14 @@
 package local
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"go.stplr.dev/stplr/internal/osutils"
 	"go.stplr.dev/stplr/pkg/dl/cache"
@@ -298,6 +300,39 @@ func (c *LocalCache) CleanupOldPackageSources(ctx context.Context, repository, b
 	return nil
 }
 
+// Record is a source cached for a package version.
+type Record struct {
+	ID         cache.CacheID
+	Repository string
+	Package    string
+	Version    string
+	// Dir holds the source. The records of the same URL share it.
+	Dir string
+}
+
+// Records returns all records of the cache.
+func (c *LocalCache) Records(ctx context.Context) ([]Record, error) {
+	var records []cacheRecord
+	if err := c.engine.Context(ctx).Find(&records); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(records, func(a, b cacheRecord) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	res := make([]Record, 0, len(records))
+	for _, r := range records {
+		res = append(res, Record{
+			ID:         toCacheId(r.ID),
+			Repository: r.Repo,
+			Package:    r.Pkg,
+			Version:    r.Ver,
+			Dir:        c.recordDir(r),
+		})
+	}
+	return res, nil
+}
+
 func (c *LocalCache) recordEntry(r cacheRecord) string {
 	return filepath.Join(c.recordDir(r), r.Name)
 }
@@ -284,6 +284,43 @@ func TestLocalCacheCleanupOldPackageSources(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestLocalCacheRecords(t *testing.T) {
+	tempDir := t.TempDir()
+	localCache := NewLocalCache(tempDir)
+
+	err := localCache.Init()
+	require.NoError(t, err)
+
+	testFile := filepath.Join(tempDir, "test.txt")
+	err = os.WriteFile(testFile, []byte("test content"), 0o644)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	cacheID, err := localCache.Put(ctx, cache.CachePutRequest{
+		Path: testFile,
+		URL:  "http://example.com/test.txt",
+		Metadata: BuildMetadata(LocalCacheMetadata{
+			Repository: "test-repo",
+			Package:    "test-package",
+			Version:    "1.0.0",
+		}),
+	})
+	require.NoError(t, err)
+
+	records, err := localCache.Records(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, Record{
+		ID:         cacheID,
+		Repository: "test-repo",
+		Package:    "test-package",
+		Version:    "1.0.0",
+		Dir:        filepath.Join(tempDir, hashUrl("http://example.com/test.txt")),
+	}, records[0])
+	assert.FileExists(t, filepath.Join(records[0].Dir, "test.txt"))
+}
+
 func TestLocalCacheReset(t *testing.T) {
 	tempDir := t.TempDir()
 	localCache := NewLocalCache(tempDir)
@@ -156,18 +156,46 @@ func (fd FileDownloader) postProcess(path string, fl *os.File, name string, opts
 		return TypeFile, name, nil
 	}
 
-	_, err := xtract.ExtractArchive(path, opts.Destination)
+	t, err := Extract(path, opts.Destination)
+	if err != nil || t == TypeFile {
+		return t, name, err
+	}
+	return TypeDir, "", nil
+}
+
+// Extract unpacks the archive at path into destination and removes it.
+// Files that are not archives are left as they are.
+func Extract(path, destination string) (Type, error) {
+	_, err := xtract.ExtractArchive(path, destination)
 	if errors.Is(err, xtractr.ErrUnknownArchiveType) {
-		return TypeFile, name, nil
+		return TypeFile, nil
 	}
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to extract with new extractor: %w", err)
+		return 0, fmt.Errorf("failed to extract with new extractor: %w", err)
 	}
 	err = os.RemoveAll(path)
 	if err != nil {
-		return 0, "", fmt.Errorf("failed to remove original archive: %w", err)
+		return 0, fmt.Errorf("failed to remove original archive: %w", err)
 	}
-	return TypeDir, "", nil
+	return TypeDir, nil
+}
+
+// SourceFileName returns the name FileDownloader saves the source at
+// downloadUrl as, if it can be told from the URL. It returns false for
+// the sources of the other downloaders.
+func SourceFileName(downloadUrl string) (string, bool) {
+	fd, ok := getDownloader(downloadUrl).(FileDownloader)
+	if !ok {
+		return "", false
+	}
+	u, name, _, err := fd.parseURLAndParams(downloadUrl)
+	if err != nil {
+		return "", false
+	}
+	if name == "" {
+		name = path.Base(u.Path)
+	}
+	return name, name != "." && name != "/"
 }
 
 // Download downloads a file using HTTP. If the file is compressed in a supported format, it will be unpacked.
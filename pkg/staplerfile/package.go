@@ -50,13 +50,17 @@ func ParseNames(dec *decoder.Decoder) (*PackageNames, error) {
 	return &pkgs, nil
 }
 
-type ScriptOptions struct{}
+// ScriptOptions are shared by all packages of a script.
+type ScriptOptions struct {
+	// Options are makepkg-style toggles such as strip or !docs.
+	Options []string `sh:"options"`
+}
 
 func ParseScriptOptions(dec *decoder.Decoder) (*ScriptOptions, error) {
 	var opts ScriptOptions
 	err := dec.DecodeVars(&opts)
 	if err != nil {
-		return nil, fmt.Errorf("fail parse names: %w", err)
+		return nil, fmt.Errorf("fail parse options: %w", err)
 	}
 	return &opts, nil
 }
@@ -97,6 +101,7 @@ type Package struct {
 	OptDepends        OverridableField[[]string] `sh:"opt_deps" xorm:"'optdepends'" json:"opt_deps,omitempty"`
 	Sources           OverridableField[[]string] `sh:"sources" xorm:"-" json:"sources"`
 	Checksums         OverridableField[[]string] `sh:"checksums" xorm:"-" json:"checksums,omitempty"`
+	ValidPGPKeys      OverridableField[[]string] `sh:"validpgpkeys" xorm:"-" json:"validpgpkeys,omitempty"`
 	Backup            OverridableField[[]string] `sh:"backup" xorm:"-" json:"backup"`
 	Scripts           OverridableField[Scripts]  `sh:"scripts" xorm:"-" json:"scripts,omitempty"`
 	AutoReqProvMethod OverridableField[string]   `sh:"auto_reqprov_method" xorm:"-" json:"auto_req_method"`
@@ -111,6 +116,24 @@ type Package struct {
 	FireJailProfiles OverridableField[map[string]string] `sh:"firejail_profiles" xorm:"-" json:"firejail_profiles,omitempty"`
 
 	DisableNetwork OverridableField[bool] `sh:"disable_network" xorm:"-" json:"disable_network"`
+	BuildCache     OverridableField[bool] `sh:"build_cache" xorm:"-" json:"build_cache"`
+
+	// The host paths and environment variables the sandbox gets,
+	// if the config allows them.
+	SandboxBinds OverridableField[[]string] `sh:"sandbox_binds" xorm:"-" json:"sandbox_binds,omitempty"`
+	SandboxEnv   OverridableField[[]string] `sh:"sandbox_env" xorm:"-" json:"sandbox_env,omitempty"`
+
+	DebugInfo OverridableField[bool] `sh:"debuginfo" xorm:"-" json:"debuginfo"`
+
+	// The build limits override the ones of the config.
+	BuildMemoryMax OverridableField[string] `sh:"build_memory_max" xorm:"-" json:"build_memory_max,omitempty"`
+	BuildCPUMax    OverridableField[string] `sh:"build_cpu_max" xorm:"-" json:"build_cpu_max,omitempty"`
+	BuildPidsMax   OverridableField[string] `sh:"build_pids_max" xorm:"-" json:"build_pids_max,omitempty"`
+	BuildIOWeight  OverridableField[string] `sh:"build_io_weight" xorm:"-" json:"build_io_weight,omitempty"`
+	BuildTimeout   OverridableField[string] `sh:"build_timeout" xorm:"-" json:"build_timeout,omitempty"`
+
+	// UpdateCheck tells where stplr outdated finds the upstream versions.
+	UpdateCheck map[string]string `sh:"update_check" xorm:"-" json:"update_check,omitempty"`
 }
 
 type Scripts struct {
@@ -55,6 +55,7 @@ type packageResolved struct {
 	OptDepends        []string             `json:"opt_deps,omitempty"`
 	Sources           []string             `json:"sources"`
 	Checksums         []string             `json:"checksums,omitempty"`
+	ValidPGPKeys      []string             `json:"validpgpkeys,omitempty"`
 	Backup            []string             `json:"backup"`
 	Scripts           Scripts              `json:"scripts,omitempty"`
 	AutoReqProvMethod string               `json:"auto_req_method"`
@@ -67,6 +68,16 @@ type packageResolved struct {
 	FireJailed        bool                 `json:"firejailed"`
 	FireJailProfiles  map[string]string    `json:"firejail_profiles,omitempty"`
 	DisableNetwork    bool                 `json:"disable_network"`
+	BuildCache        bool                 `json:"build_cache"`
+	SandboxBinds      []string             `json:"sandbox_binds,omitempty"`
+	SandboxEnv        []string             `json:"sandbox_env,omitempty"`
+	DebugInfo         bool                 `json:"debuginfo"`
+	BuildMemoryMax    string               `json:"build_memory_max,omitempty"`
+	BuildCPUMax       string               `json:"build_cpu_max,omitempty"`
+	BuildPidsMax      string               `json:"build_pids_max,omitempty"`
+	BuildIOWeight     string               `json:"build_io_weight,omitempty"`
+	BuildTimeout      string               `json:"build_timeout,omitempty"`
+	UpdateCheck       map[string]string    `json:"update_check,omitempty"`
 }
 
 func PackageToResolved(src *Package) packageResolved {
@@ -101,6 +112,7 @@ func PackageToResolved(src *Package) packageResolved {
 		OptDepends:        src.OptDepends.Resolved(),
 		Sources:           src.Sources.Resolved(),
 		Checksums:         src.Checksums.Resolved(),
+		ValidPGPKeys:      src.ValidPGPKeys.Resolved(),
 		Backup:            src.Backup.Resolved(),
 		Scripts:           src.Scripts.Resolved(),
 		AutoReqProvMethod: src.AutoReqProvMethod.Resolved(),
@@ -113,6 +125,16 @@ func PackageToResolved(src *Package) packageResolved {
 		FireJailed:        src.FireJailed.Resolved(),
 		FireJailProfiles:  src.FireJailProfiles.Resolved(),
 		DisableNetwork:    src.DisableNetwork.Resolved(),
+		BuildCache:        src.BuildCache.Resolved(),
+		SandboxBinds:      src.SandboxBinds.Resolved(),
+		SandboxEnv:        src.SandboxEnv.Resolved(),
+		DebugInfo:         src.DebugInfo.Resolved(),
+		BuildMemoryMax:    src.BuildMemoryMax.Resolved(),
+		BuildCPUMax:       src.BuildCPUMax.Resolved(),
+		BuildPidsMax:      src.BuildPidsMax.Resolved(),
+		BuildIOWeight:     src.BuildIOWeight.Resolved(),
+		BuildTimeout:      src.BuildTimeout.Resolved(),
+		UpdateCheck:       src.UpdateCheck,
 	}
 }
 
@@ -130,6 +152,7 @@ func ResolvePackage(pkg *Package, overrides []string) {
 	pkg.OptDepends.Resolve(overrides)
 	pkg.Sources.Resolve(overrides)
 	pkg.Checksums.Resolve(overrides)
+	pkg.ValidPGPKeys.Resolve(overrides)
 	pkg.Backup.Resolve(overrides)
 	pkg.Scripts.Resolve(overrides)
 	pkg.AutoReqProvMethod.Resolve(overrides)
@@ -142,6 +165,15 @@ func ResolvePackage(pkg *Package, overrides []string) {
 	pkg.FireJailed.Resolve(overrides)
 	pkg.FireJailProfiles.Resolve(overrides)
 	pkg.DisableNetwork.Resolve(overrides)
+	pkg.BuildCache.Resolve(overrides)
+	pkg.SandboxBinds.Resolve(overrides)
+	pkg.SandboxEnv.Resolve(overrides)
+	pkg.DebugInfo.Resolve(overrides)
+	pkg.BuildMemoryMax.Resolve(overrides)
+	pkg.BuildCPUMax.Resolve(overrides)
+	pkg.BuildPidsMax.Resolve(overrides)
+	pkg.BuildIOWeight.Resolve(overrides)
+	pkg.BuildTimeout.Resolve(overrides)
 }
 
 // GetCELColumnMap returns a map of CEL field names to their SQL column information
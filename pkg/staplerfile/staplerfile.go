@@ -27,9 +27,11 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/jeandeaual/go-locale"
 	"go.alt-gnome.ru/x/appstream"
@@ -113,7 +115,7 @@ func (s *ScriptFile) ParseBuildVars(ctx context.Context, info *distro.OSRelease,
 
 	scriptOpts, err := ParseScriptOptions(dec)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to parse names: %w", err)
+		return "", nil, fmt.Errorf("failed to parse options: %w", err)
 	}
 
 	pkgs, err := s.createPackagesForBuildVars(ctx, dec, info, pkgNames, targetPackages)
@@ -171,7 +173,7 @@ func refineAppstream(pkg *Package, basedir string) {
 
 func (s *ScriptFile) createRunner(info *distro.OSRelease) (*interp.Runner, error) {
 	scriptDir := filepath.Dir(s.path)
-	env := common.CreateBuildEnvVars(info, types.Directories{})
+	env := common.CreateBuildEnvVars(info, types.Directories{}, 0)
 
 	restr := handlers.WithFilter(
 		handlers.RestrictSandbox(scriptDir),
@@ -353,3 +355,27 @@ func getLocalSourceFiles(pkg *Package) ([]string, error) {
 	}
 	return files, nil
 }
+
+// LocalFiles returns the files next to the script that the resolved
+// variables of p refer to, relative to the script directory.
+func (p *Package) LocalFiles() ([]string, error) {
+	var files []string
+	for _, src := range p.Sources.Resolved() {
+		u, err := url.Parse(src)
+		if err != nil {
+			return nil, err
+		}
+		if dl.IsLocalUrl(u) {
+			files = append(files, u.Path)
+		}
+	}
+	files = append(files, p.Scripts.Resolved().Files()...)
+	profiles := p.FireJailProfiles.Resolved()
+	for _, dest := range slices.Sorted(maps.Keys(profiles)) {
+		files = append(files, profiles[dest])
+	}
+	if msgfile := p.NonFreeMsgFile.Resolved(); msgfile != "" {
+		files = append(files, msgfile)
+	}
+	return files, nil
+}
@@ -61,3 +61,20 @@ func TestParseBuildVarsOptions(t *testing.T) {
 
 	assert.Len(t, pkgs, 1)
 }
+
+func TestParseBuildVarsScriptOptions(t *testing.T) {
+	r := strings.NewReader(`name=(test test-doc)
+	basepkg_name=test
+	options=(strip '!docs' zipman)
+	`)
+	s, err := staplerfile.ReadFromIOReader(r, "Staplerfile")
+	assert.NoError(t, err)
+
+	_, pkgs, err := s.ParseBuildVars(t.Context(), &distro.OSRelease{}, []string{})
+	assert.NoError(t, err)
+
+	assert.Len(t, pkgs, 2)
+	for _, pkg := range pkgs {
+		assert.Equal(t, []string{"strip", "!docs", "zipman"}, pkg.Options.Options)
+	}
+}
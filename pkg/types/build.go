@@ -29,6 +29,29 @@ type BuildOpts struct {
 	Interactive     bool
 	NoSuffix        bool
 	DisableFirejail bool
+	// Jobs limits how many independent packages are built at once.
+	// Values below 2 mean sequential builds.
+	Jobs int
+	// DryRun only records what would be done, see build.Plan.
+	DryRun bool
+	// NoCheck skips the check() function of the build script.
+	NoCheck bool
+	// Reproducible makes the package output depend only on the sources:
+	// file times are clamped to SOURCE_DATE_EPOCH and build host fields
+	// are fixed.
+	Reproducible bool
+	// SBOM writes an SPDX document and a build provenance statement
+	// next to every built package.
+	SBOM bool
+	// CleanRootImage is a base image tarball or OCI layout. If set,
+	// the build runs in a rootfs unpacked from it with only the
+	// declared build dependencies installed.
+	CleanRootImage string
+	// CrossTarget is set when the packages are built for another distro
+	// or package format than the host one. Build dependencies are then
+	// resolved for and installed on the host, other dependencies are
+	// only built.
+	CrossTarget bool
 }
 
 type Scripts struct {
@@ -48,4 +71,7 @@ type Directories struct {
 	PkgDir    string
 	HomeDir   string
 	ScriptDir string
+	// CacheDir keeps the compiler caches of the package between
+	// builds. It is empty unless the build cache is used.
+	CacheDir string
 }
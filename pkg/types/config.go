@@ -39,6 +39,99 @@ type Config struct {
 
 	ForbidSkipInChecksums bool `json:"forbidSkipInChecksums" koanf:"forbidSkipInChecksums"`
 	ForbidBuildCommand    bool `json:"forbidBuildCommand" koanf:"forbidBuildCommand"`
+
+	NoCheck      bool `json:"noCheck" koanf:"noCheck"`
+	Reproducible bool `json:"reproducible" koanf:"reproducible"`
+	SBOM         bool `json:"sbom" koanf:"sbom"`
+
+	// PackageOptions are the default options of build scripts,
+	// see the options array of Staplerfiles.
+	PackageOptions []string `json:"packageOptions" koanf:"packageOptions"`
+	// DebugInfo splits the debug info of ELF files into
+	// a separate package for every package.
+	DebugInfo bool `json:"debugInfo" koanf:"debugInfo"`
+
+	// CleanRootImage is the base image for clean-root builds.
+	CleanRootImage string `json:"cleanRootImage" koanf:"cleanRootImage"`
+
+	// CacheMaxSize and CacheMaxAge limit the caches, which are
+	// pruned after every build when set.
+	CacheMaxSize string `json:"cacheMaxSize" koanf:"cacheMaxSize"`
+	CacheMaxAge  string `json:"cacheMaxAge" koanf:"cacheMaxAge"`
+
+	// BuildMemoryMax, BuildCPUMax, BuildPidsMax and BuildIOWeight are the
+	// cgroup limits of build sandboxes, such as 4G, 2 CPUs, 4096 processes
+	// and an I/O weight of 100. BuildTimeout, such as 2h, limits how long
+	// every build function may run. Staplerfiles can override them with
+	// the build_memory_max, build_cpu_max, build_pids_max, build_io_weight
+	// and build_timeout variables.
+	BuildMemoryMax string `json:"buildMemoryMax" koanf:"buildMemoryMax"`
+	BuildCPUMax    string `json:"buildCpuMax" koanf:"buildCpuMax"`
+	BuildPidsMax   string `json:"buildPidsMax" koanf:"buildPidsMax"`
+	BuildIOWeight  string `json:"buildIoWeight" koanf:"buildIoWeight"`
+	BuildTimeout   string `json:"buildTimeout" koanf:"buildTimeout"`
+
+	// BuildCache keeps the compiler caches of every package between
+	// builds, Staplerfiles can turn it on with the build_cache variable.
+	BuildCache bool `json:"buildCache" koanf:"buildCache"`
+
+	// SandboxSeccompProfile is the path of an OCI seccomp profile for build
+	// sandboxes. When it is empty the default profile is used, "unconfined"
+	// turns seccomp off. SandboxCapabilities replaces the default set of
+	// capabilities of build sandboxes.
+	SandboxSeccompProfile string   `json:"sandboxSeccompProfile" koanf:"sandboxSeccompProfile"`
+	SandboxCapabilities   []string `json:"sandboxCapabilities" koanf:"sandboxCapabilities"`
+
+	// SandboxBinds and SandboxEnvPassthrough allow Staplerfiles to mount
+	// host paths read-only into their build sandbox with sandbox_binds and
+	// to get environment variables with sandbox_env. The entries are a
+	// repo/package glob and a path or variable name, such as
+	// "default/vendor-sdk:/opt/sdk" or "*/foo:FOO_*".
+	SandboxBinds          []string `json:"sandboxBinds" koanf:"sandboxBinds"`
+	SandboxEnvPassthrough []string `json:"sandboxEnvPassthrough" koanf:"sandboxEnvPassthrough"`
+
+	Signing Signing `json:"signing" koanf:"signing"`
+}
+
+// Signing holds the keys that built packages are signed with.
+type Signing struct {
+	RPM       SigningKey `json:"rpm" koanf:"rpm" toml:"rpm"`
+	Deb       SigningKey `json:"deb" koanf:"deb" toml:"deb"`
+	APK       SigningKey `json:"apk" koanf:"apk" toml:"apk"`
+	ArchLinux SigningKey `json:"archlinux" koanf:"archlinux" toml:"archlinux"`
+	// Provenance is the OpenPGP key build provenance statements are
+	// signed with.
+	Provenance SigningKey `json:"provenance" koanf:"provenance" toml:"provenance"`
+}
+
+// For returns the key for packages of format.
+func (s Signing) For(format string) SigningKey {
+	switch format {
+	case "rpm":
+		return s.RPM
+	case "deb":
+		return s.Deb
+	case "apk":
+		return s.APK
+	case "archlinux":
+		return s.ArchLinux
+	}
+	return SigningKey{}
+}
+
+// SigningKey is an OpenPGP key for rpm, deb and archlinux packages
+// or an RSA key in the PEM format for apk packages.
+type SigningKey struct {
+	// KeyFile is the private key. It must be readable by the builder user.
+	KeyFile string `json:"key_file" koanf:"key_file" toml:"key_file"`
+	// PassphraseFile or PassphraseEnv is where the passphrase of
+	// an encrypted key is read from.
+	PassphraseFile string `json:"passphrase_file" koanf:"passphrase_file" toml:"passphrase_file"`
+	PassphraseEnv  string `json:"passphrase_env" koanf:"passphrase_env" toml:"passphrase_env"`
+	// PublicKeyFile is used by verify-package.
+	PublicKeyFile string `json:"public_key_file" koanf:"public_key_file" toml:"public_key_file"`
+	// KeyName is the name apk looks the public key up by in /etc/apk/keys.
+	KeyName string `json:"key_name" koanf:"key_name" toml:"key_name"`
 }
 
 // Repo represents a Stapler repo within a configuration file
@@ -59,6 +152,9 @@ type Repo struct {
 
 	RequireSignedCommits bool     `json:"require_signed_commits" koanf:"require_signed_commits" toml:"require_signed_commits"`
 	TrustedKeys          []string `json:"trusted_keys" koanf:"trusted_keys" toml:"trusted_keys"`
+
+	// Lint sets the severity (off, warning or error) of package lint checks.
+	Lint map[string]string `json:"lint" koanf:"lint" toml:"lint"`
 }
 
 func (r *Repo) MergeFrom(other *Repo) {
@@ -89,4 +185,8 @@ func (r *Repo) MergeFrom(other *Repo) {
 	if len(other.TrustedKeys) > 0 {
 		r.TrustedKeys = other.TrustedKeys
 	}
+
+	if len(other.Lint) > 0 {
+		r.Lint = other.Lint
+	}
 }
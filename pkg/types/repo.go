@@ -41,5 +41,7 @@ type RepoConfig struct {
 
 		RequireSignedCommits bool     `toml:"require_signed_commits"`
 		TrustedKeys          []string `toml:"trusted_keys"`
+
+		Lint map[string]string `toml:"lint"`
 	}
 }